@@ -0,0 +1,27 @@
+package base
+
+import (
+	"fmt"
+)
+
+// FeatureNamesIn returns a copy of inputNames, or x0, x1... if inputNames is nil.
+// it panics if inputNames has not nFeatures elements
+func FeatureNamesIn(inputNames []string, nFeatures int) []string {
+	if inputNames == nil {
+		return PrefixedFeatureNames("x", nFeatures)
+	}
+	if len(inputNames) != nFeatures {
+		panic(fmt.Errorf("got %d feature names, expected %d", len(inputNames), nFeatures))
+	}
+	return append([]string(nil), inputNames...)
+}
+
+// PrefixedFeatureNames returns prefix0, prefix1... prefix{n-1}.
+// transformers projecting their input on new components use their lower case type name as prefix, like pca0, pca1...
+func PrefixedFeatureNames(prefix string, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return names
+}
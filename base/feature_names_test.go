@@ -0,0 +1,13 @@
+package base
+
+import (
+	"fmt"
+)
+
+func ExampleFeatureNamesIn() {
+	fmt.Println(FeatureNamesIn(nil, 3), FeatureNamesIn([]string{"age", "height"}, 2))
+	fmt.Println(PrefixedFeatureNames("pca", 2))
+	// Output:
+	// [x0 x1 x2] [age height]
+	// [pca0 pca1]
+}
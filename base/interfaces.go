@@ -26,3 +26,9 @@ type Transformer interface {
 	FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense)
 	TransformerClone() Transformer
 }
+
+// FeatureNamesOuter is implemented by transformers able to name their output features given the names of their input
+// features. nil inputNames default to x0, x1...
+type FeatureNamesOuter interface {
+	GetFeatureNamesOut(inputNames []string) []string
+}
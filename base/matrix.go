@@ -202,6 +202,9 @@ func ToDense(m mat.Matrix) *mat.Dense {
 	if m == mat.Matrix(nil) {
 		return &mat.Dense{}
 	}
+	if csr, ok := m.(*CSRMatrix); ok {
+		return csr.ToDense()
+	}
 	ret := &mat.Dense{}
 	if rawmatrixer, ok := m.(mat.RawMatrixer); ok {
 		if rawmatrixer == mat.RawMatrixer(nil) {
@@ -0,0 +1,60 @@
+package base
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// MurmurHash3 returns the 32 bits MurmurHash3 (x86 variant) of key, as scikit-learn's murmurhash3_32 with
+// positive=True. convert it to int32 for the signed variant used by feature hashing
+func MurmurHash3(key []byte, seed uint32) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	h := seed
+	nBlocks := len(key) / 4
+	for i := 0; i < nBlocks; i++ {
+		k := binary.LittleEndian.Uint32(key[4*i:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+	tail := key[4*nBlocks:]
+	var k uint32
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+	h ^= uint32(len(key))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// HashIndex returns the column of name among nFeatures given by its signed 32 bits MurmurHash3 with seed 0, as in
+// scikit-learn's feature hashing, and the sign of the hash if alternateSign, else 1
+func HashIndex(name string, nFeatures int, alternateSign bool) (int, float64) {
+	h := int64(int32(MurmurHash3([]byte(name), 0)))
+	sign := 1.
+	if h < 0 {
+		if alternateSign {
+			sign = -1
+		}
+		h = -h
+	}
+	return int(h % int64(nFeatures)), sign
+}
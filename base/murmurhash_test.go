@@ -0,0 +1,25 @@
+package base
+
+import "fmt"
+
+func ExampleMurmurHash3() {
+	fmt.Printf("%#x\n", MurmurHash3([]byte(""), 1))
+	fmt.Printf("%#x\n", MurmurHash3([]byte("hello"), 0))
+	fmt.Printf("%#x\n", MurmurHash3([]byte("Hello, world!"), 1234))
+	fmt.Println(int32(MurmurHash3([]byte("foo"), 0)))
+	// Output:
+	// 0x514e28b7
+	// 0x248bfa47
+	// 0xfaf6cdb3
+	// -156908512
+}
+
+func ExampleHashIndex() {
+	fmt.Println(HashIndex("foo", 1<<20, true))
+	fmt.Println(HashIndex("foo", 1<<20, false))
+	fmt.Println(HashIndex("hello", 16, true))
+	// Output:
+	// 670688 -1
+	// 670688 1
+	// 7 1
+}
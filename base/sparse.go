@@ -0,0 +1,92 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CSRMatrix is a compressed sparse row matrix implementing mat.Matrix.
+// the column indices of the non-zero elements of row i are Indices[Indptr[i]:Indptr[i+1]], in increasing order,
+// and their values are Data[Indptr[i]:Indptr[i+1]]
+type CSRMatrix struct {
+	Rows, Cols int
+	Indptr     []int
+	Indices    []int
+	Data       []float64
+}
+
+// NewCSRMatrix returns an empty (rows,cols) *CSRMatrix. rows are appended with AppendRow
+func NewCSRMatrix(rows, cols int) *CSRMatrix {
+	return &CSRMatrix{Cols: cols, Indptr: make([]int, 1, rows+1)}
+}
+
+// AppendRow appends a row given the column indices and values of its non-zero elements.
+// indices are sorted and duplicate indices are summed
+func (m *CSRMatrix) AppendRow(indices []int, values []float64) {
+	order := make([]int, len(indices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return indices[order[a]] < indices[order[b]] })
+	start := len(m.Indices)
+	for _, o := range order {
+		j := indices[o]
+		if j < 0 || j >= m.Cols {
+			panic(fmt.Errorf("CSRMatrix: column index %d out of range [0,%d)", j, m.Cols))
+		}
+		if n := len(m.Indices); n > start && m.Indices[n-1] == j {
+			m.Data[n-1] += values[o]
+			continue
+		}
+		m.Indices = append(m.Indices, j)
+		m.Data = append(m.Data, values[o])
+	}
+	m.Indptr = append(m.Indptr, len(m.Indices))
+	m.Rows++
+}
+
+// Dims for CSRMatrix
+func (m *CSRMatrix) Dims() (int, int) { return m.Rows, m.Cols }
+
+// At for CSRMatrix
+func (m *CSRMatrix) At(i, j int) float64 {
+	if i < 0 || i >= m.Rows || j < 0 || j >= m.Cols {
+		panic(mat.ErrIndexOutOfRange)
+	}
+	start, end := m.Indptr[i], m.Indptr[i+1]
+	k := start + sort.SearchInts(m.Indices[start:end], j)
+	if k < end && m.Indices[k] == j {
+		return m.Data[k]
+	}
+	return 0
+}
+
+// T for CSRMatrix
+func (m *CSRMatrix) T() mat.Matrix { return MatTranspose{Matrix: m} }
+
+// NNZ returns the number of stored elements
+func (m *CSRMatrix) NNZ() int { return len(m.Data) }
+
+// RowNonZeros returns the column indices and values of the stored elements of row i. they must not be modified
+func (m *CSRMatrix) RowNonZeros(i int) (indices []int, values []float64) {
+	start, end := m.Indptr[i], m.Indptr[i+1]
+	return m.Indices[start:end], m.Data[start:end]
+}
+
+// ToDense returns a dense copy of the matrix
+func (m *CSRMatrix) ToDense() *mat.Dense {
+	if m.Rows == 0 || m.Cols == 0 {
+		return &mat.Dense{}
+	}
+	d := mat.NewDense(m.Rows, m.Cols, nil)
+	for i := 0; i < m.Rows; i++ {
+		row := d.RawRowView(i)
+		indices, values := m.RowNonZeros(i)
+		for k, j := range indices {
+			row[j] = values[k]
+		}
+	}
+	return d
+}
@@ -0,0 +1,25 @@
+package base
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleCSRMatrix() {
+	m := NewCSRMatrix(3, 4)
+	m.AppendRow([]int{3, 0}, []float64{2, 1})
+	m.AppendRow(nil, nil)
+	m.AppendRow([]int{1, 1, 2}, []float64{1, 2, 5})
+	fmt.Println(m.Dims())
+	fmt.Println(m.NNZ(), m.Indptr, m.Indices, m.Data)
+	fmt.Println(mat.Formatted(m))
+	fmt.Println(mat.Equal(ToDense(m), m), m.At(2, 1), m.T().At(3, 0))
+	// Output:
+	// 3 4
+	// 4 [0 2 2 4] [0 3 1 2] [1 2 3 5]
+	// ⎡1  0  0  2⎤
+	// ⎢0  0  0  0⎥
+	// ⎣0  3  5  0⎦
+	// true 3 2
+}
@@ -0,0 +1,141 @@
+package base
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomizedSVD computes a truncated randomized SVD of M.
+// it returns U (nSamples,NComponents), S (NComponents) and VT (NComponents,nFeatures)
+// NOversamples is the number of additional random vectors used to sample the range of M (10 is a good default)
+// NIter is the number of power iterations. if NIter<0, 7 is used when NComponents is small compared to min(M.Dims()), else 4
+// signs are flipped to make U's largest absolute values positive (see SVDFlip)
+// Halko, et al., 2009 "Finding structure with randomness: Stochastic algorithms for constructing approximate matrix decompositions"
+func RandomizedSVD(M mat.Matrix, NComponents, NOversamples, NIter int, RandomState Source) (U *mat.Dense, S []float64, VT *mat.Dense) {
+	nSamples, nFeatures := M.Dims()
+	nRandom := NComponents + NOversamples
+	if min := minInt(nSamples, nFeatures); nRandom > min {
+		nRandom = min
+	}
+	if NIter < 0 {
+		NIter = 4
+		if float64(NComponents) < .1*float64(minInt(nSamples, nFeatures)) {
+			NIter = 7
+		}
+	}
+	normFloat64 := rand.NormFloat64
+	if RandomState != Source(nil) {
+		if normFloat64er, ok := RandomState.(NormFloat64er); ok {
+			normFloat64 = normFloat64er.NormFloat64
+		} else {
+			normFloat64 = rand.New(RandomState).NormFloat64
+		}
+	}
+	// sample the range of M using a random gaussian matrix
+	Omega := mat.NewDense(nFeatures, nRandom, nil)
+	omega := Omega.RawMatrix().Data
+	for i := range omega {
+		omega[i] = normFloat64()
+	}
+	Q := mat.NewDense(nSamples, nRandom, nil)
+	Q.Mul(M, Omega)
+	Orthonormalize(Q)
+	// power iterations to better approximate the range when singular values decay slowly
+	Qt := mat.NewDense(nFeatures, nRandom, nil)
+	for iter := 0; iter < NIter; iter++ {
+		Qt.Mul(M.T(), Q)
+		Orthonormalize(Qt)
+		Q.Mul(M, Qt)
+		Orthonormalize(Q)
+	}
+	// project M on the sampled range and compute the svd of the small matrix B
+	B := mat.NewDense(nRandom, nFeatures, nil)
+	B.Mul(Q.T(), M)
+	var svd mat.SVD
+	if !svd.Factorize(B, mat.SVDThin) {
+		panic("RandomizedSVD: svd failed")
+	}
+	Ub, V := &mat.Dense{}, &mat.Dense{}
+	svd.UTo(Ub)
+	svd.VTo(V)
+	S = svd.Values(nil)
+
+	Ufull := mat.NewDense(nSamples, nRandom, nil)
+	Ufull.Mul(Q, Ub)
+	VTfull := mat.DenseCopyOf(V.T())
+	SVDFlip(Ufull, VTfull, true)
+	U = mat.DenseCopyOf(Ufull.Slice(0, nSamples, 0, NComponents))
+	VT = mat.DenseCopyOf(VTfull.Slice(0, NComponents, 0, nFeatures))
+	S = S[:NComponents]
+	return
+}
+
+// SVDFlip flips signs of U columns and VT rows to make SVD outputs deterministic.
+// if uBasedDecision is true, the largest absolute value in each column of U is made positive,
+// else the largest absolute value in each row of VT is made positive. U may be nil in the latter case
+func SVDFlip(U, VT *mat.Dense, uBasedDecision bool) {
+	nComponents, nFeatures := VT.Dims()
+	var nSamples int
+	if U != nil {
+		nSamples, _ = U.Dims()
+	}
+	col := make([]float64, nSamples)
+	for c := 0; c < nComponents; c++ {
+		v := VT.RawRowView(c)[:nFeatures]
+		if uBasedDecision {
+			mat.Col(col, c, U)
+			v = col
+		}
+		imax := 0
+		for i := range v {
+			if math.Abs(v[i]) > math.Abs(v[imax]) {
+				imax = i
+			}
+		}
+		if v[imax] < 0 {
+			if U != nil {
+				for i := 0; i < nSamples; i++ {
+					U.Set(i, c, -U.At(i, c))
+				}
+			}
+			floats.Scale(-1, VT.RawRowView(c)[:nFeatures])
+		}
+	}
+}
+
+// Orthonormalize replaces columns of A with an orthonormal basis of their span
+// using modified Gram-Schmidt with re-orthogonalization. columns with a vanishing norm are zeroed
+func Orthonormalize(A *mat.Dense) {
+	r, c := A.Dims()
+	cols := make([][]float64, c)
+	for j := range cols {
+		cols[j] = make([]float64, r)
+		mat.Col(cols[j], j, A)
+	}
+	for j := range cols {
+		for pass := 0; pass < 2; pass++ {
+			for k := 0; k < j; k++ {
+				floats.AddScaled(cols[j], -floats.Dot(cols[k], cols[j]), cols[k])
+			}
+		}
+		nrm := floats.Norm(cols[j], 2)
+		if nrm > 1e-12 {
+			floats.Scale(1/nrm, cols[j])
+		} else {
+			for i := range cols[j] {
+				cols[j][i] = 0
+			}
+		}
+		A.SetCol(j, cols[j])
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,27 @@
+package base
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleRandomizedSVD() {
+	M := mat.NewDense(5, 4, []float64{
+		1, 2, 3, 4,
+		2, 4, 6, 8.5,
+		0, 1, 0, 1,
+		3, 5, 9, 12,
+		1, 1, 1, 1})
+	var svd mat.SVD
+	svd.Factorize(M, mat.SVDNone)
+	_, S, VT := RandomizedSVD(M, 2, 2, -1, NewSource(1))
+	fmt.Printf("exact      : %.4f\n", svd.Values(nil)[:2])
+	fmt.Printf("randomized : %.4f\n", S)
+	r, c := VT.Dims()
+	fmt.Println("VT dims    :", r, c)
+	// Output:
+	// exact      : [20.5276 1.0610]
+	// randomized : [20.5276 1.0610]
+	// VT dims    : 2 4
+}
@@ -0,0 +1,290 @@
+package compose
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/table"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// NamedTransformer is a ColumnTransformer branch: Transformer is applied to the Columns of X.
+// Transformer is a base.Transformer, "passthrough" or "drop".
+// Columns is an int, a []int of column indices, a string or []string of column names (see ColumnTransformer.FeatureNames),
+// a []bool mask or a func(X mat.Matrix) []int
+type NamedTransformer struct {
+	Name        string
+	Transformer interface{}
+	Columns     interface{}
+}
+
+// ColumnTransformer applies transformers to subsets of the columns of X and concatenates their outputs.
+// Remainder handles the columns not selected by any transformer: "drop" (default), "passthrough" or a base.Transformer.
+// FeatureNames are the names of the columns of X, used to select columns by name. they default to the column names of
+// X if it is a *table.Table, in which case transformers receive the selected columns as a *table.Table.
+// TransformerWeights multiplies the output of the named transformers.
+// branches are fitted and transformed concurrently using NJobs goroutines. NJobs<=0 means runtime.NumCPU()
+type ColumnTransformer struct {
+	Transformers       []NamedTransformer
+	Remainder          interface{}
+	FeatureNames       []string
+	TransformerWeights map[string]float64
+	NJobs              int
+
+	NFeaturesIn int
+	// FeatureNamesIn are the names of the columns of X seen during fit, if any
+	FeatureNamesIn []string
+	// ColumnIndices are the resolved input columns of each transformer and RemainderColumns those of the remainder
+	ColumnIndices    [][]int
+	RemainderColumns []int
+	// OutputIndices maps a transformer name (or "remainder") to its [start,end) columns in the output
+	OutputIndices map[string][2]int
+}
+
+// NewColumnTransformer returns a *ColumnTransformer dropping remainder columns
+func NewColumnTransformer(transformers ...NamedTransformer) *ColumnTransformer {
+	return &ColumnTransformer{Transformers: transformers, Remainder: "drop"}
+}
+
+// TransformerClone clones the ColumnTransformer and its transformers
+func (m *ColumnTransformer) TransformerClone() base.Transformer {
+	clone := *m
+	clone.Transformers = make([]NamedTransformer, len(m.Transformers))
+	for i, nt := range m.Transformers {
+		clone.Transformers[i] = nt
+		if transformer, ok := nt.Transformer.(base.Transformer); ok {
+			clone.Transformers[i].Transformer = transformer.TransformerClone()
+		}
+	}
+	if transformer, ok := m.Remainder.(base.Transformer); ok {
+		clone.Remainder = transformer.TransformerClone()
+	}
+	return &clone
+}
+
+// Fit fits all transformers on their columns of X
+func (m *ColumnTransformer) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform fits all transformers, transforms X and concatenates the results
+func (m *ColumnTransformer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	_, m.NFeaturesIn = X.Dims()
+	m.FeatureNamesIn = m.FeatureNames
+	if t, ok := X.(*table.Table); ok && m.FeatureNamesIn == nil {
+		m.FeatureNamesIn = t.Names()
+	}
+	m.resolveColumns(X)
+	return m.apply(X, Y, true), base.ToDense(Y)
+}
+
+// Transform transforms X with each fitted transformer and concatenates the results
+func (m *ColumnTransformer) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	if _, nFeatures := X.Dims(); nFeatures != m.NFeaturesIn {
+		panic(fmt.Errorf("ColumnTransformer: X has %d features, but it was fitted with %d features", nFeatures, m.NFeaturesIn))
+	}
+	return m.apply(X, Y, false), base.ToDense(Y)
+}
+
+// GetFeatureNamesOut returns the output feature names of each branch prefixed with its name and a double underscore,
+// like scaler__age or remainder__x3. inputNames default to FeatureNamesIn, then to x0, x1...
+func (m *ColumnTransformer) GetFeatureNamesOut(inputNames []string) []string {
+	if inputNames == nil {
+		inputNames = m.FeatureNamesIn
+	}
+	inputNames = base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+	var names []string
+	branchNames, transformers, columns := m.branches()
+	for b, name := range branchNames {
+		if len(columns[b]) == 0 {
+			continue
+		}
+		selected := make([]string, len(columns[b]))
+		for c, col := range columns[b] {
+			selected[c] = inputNames[col]
+		}
+		var out []string
+		switch transformer := transformers[b].(type) {
+		case string:
+			if transformer == "drop" {
+				continue
+			}
+			out = selected
+		case base.FeatureNamesOuter:
+			out = transformer.GetFeatureNamesOut(selected)
+		default:
+			panic(fmt.Errorf("ColumnTransformer: %s has no GetFeatureNamesOut", name))
+		}
+		for _, featureName := range out {
+			names = append(names, name+"__"+featureName)
+		}
+	}
+	return names
+}
+
+// branches returns the transformers and their columns, including the remainder
+func (m *ColumnTransformer) branches() (names []string, transformers []interface{}, columns [][]int) {
+	for i, nt := range m.Transformers {
+		names = append(names, nt.Name)
+		transformers = append(transformers, nt.Transformer)
+		columns = append(columns, m.ColumnIndices[i])
+	}
+	remainder := m.Remainder
+	if remainder == nil {
+		remainder = "drop"
+	}
+	names = append(names, "remainder")
+	transformers = append(transformers, remainder)
+	columns = append(columns, m.RemainderColumns)
+	return
+}
+
+// apply runs each branch on its columns of X concurrently and stacks their outputs horizontally
+func (m *ColumnTransformer) apply(X, Y mat.Matrix, fit bool) *mat.Dense {
+	nSamples, _ := X.Dims()
+	names, transformers, columns := m.branches()
+	outputs := make([]*mat.Dense, len(names))
+	base.Parallelize(m.NJobs, len(names), func(th, start, end int) {
+		for b := start; b < end; b++ {
+			if len(columns[b]) == 0 {
+				continue
+			}
+			var out *mat.Dense
+			switch transformer := transformers[b].(type) {
+			case string:
+				switch transformer {
+				case "drop":
+					continue
+				case "passthrough":
+					out = selectColumns(X, columns[b])
+				default:
+					panic(fmt.Errorf("ColumnTransformer: unknown transformer %s for %s", transformer, names[b]))
+				}
+			case base.Transformer:
+				if fit {
+					out, _ = transformer.FitTransform(subset(X, columns[b]), Y)
+				} else {
+					out, _ = transformer.Transform(subset(X, columns[b]), Y)
+				}
+			default:
+				panic(fmt.Errorf("ColumnTransformer: %s is not a base.Transformer, \"passthrough\" nor \"drop\"", names[b]))
+			}
+			if weight, ok := m.TransformerWeights[names[b]]; ok {
+				// out may be the state of the transformer, like an Isomap Embedding, which must not be modified
+				scaled := &mat.Dense{}
+				scaled.Scale(weight, out)
+				out = scaled
+			}
+			outputs[b] = out
+		}
+	})
+	nColumns := 0
+	m.OutputIndices = make(map[string][2]int)
+	for b, out := range outputs {
+		start := nColumns
+		if out != nil {
+			r, c := out.Dims()
+			if r != nSamples {
+				panic(fmt.Errorf("ColumnTransformer: %s output has %d rows, expected %d", names[b], r, nSamples))
+			}
+			nColumns += c
+		}
+		m.OutputIndices[names[b]] = [2]int{start, nColumns}
+	}
+	if nColumns == 0 {
+		panic(fmt.Errorf("ColumnTransformer: no transformer produced output columns"))
+	}
+	Xout := mat.NewDense(nSamples, nColumns, nil)
+	for b, out := range outputs {
+		if out == nil {
+			continue
+		}
+		idx := m.OutputIndices[names[b]]
+		Xout.Slice(0, nSamples, idx[0], idx[1]).(*mat.Dense).Copy(out)
+	}
+	return Xout
+}
+
+// resolveColumns converts column selections of each transformer to column indices and computes remainder columns
+func (m *ColumnTransformer) resolveColumns(X mat.Matrix) {
+	_, nFeatures := X.Dims()
+	used := make([]bool, nFeatures)
+	m.ColumnIndices = make([][]int, len(m.Transformers))
+	for i, nt := range m.Transformers {
+		m.ColumnIndices[i] = m.columnIndices(X, nt.Name, nt.Columns)
+		for _, c := range m.ColumnIndices[i] {
+			if c < 0 || c >= nFeatures {
+				panic(fmt.Errorf("ColumnTransformer: column %d of %s out of range [0,%d)", c, nt.Name, nFeatures))
+			}
+			used[c] = true
+		}
+	}
+	m.RemainderColumns = nil
+	for c, u := range used {
+		if !u {
+			m.RemainderColumns = append(m.RemainderColumns, c)
+		}
+	}
+}
+
+func (m *ColumnTransformer) columnIndices(X mat.Matrix, name string, columns interface{}) []int {
+	switch cols := columns.(type) {
+	case nil:
+		return nil
+	case int:
+		return []int{cols}
+	case []int:
+		return cols
+	case string:
+		return m.columnIndices(X, name, []string{cols})
+	case []string:
+		idx := make([]int, len(cols))
+		for i, col := range cols {
+			idx[i] = -1
+			for j, featureName := range m.FeatureNamesIn {
+				if featureName == col {
+					idx[i] = j
+					break
+				}
+			}
+			if idx[i] < 0 {
+				panic(fmt.Errorf("ColumnTransformer: unknown column %s for %s", col, name))
+			}
+		}
+		return idx
+	case []bool:
+		var idx []int
+		for j, selected := range cols {
+			if selected {
+				idx = append(idx, j)
+			}
+		}
+		return idx
+	case func(X mat.Matrix) []int:
+		return cols(X)
+	}
+	panic(fmt.Errorf("ColumnTransformer: unsupported column selection %T for %s", columns, name))
+}
+
+// subset returns the given columns of X, as a *table.Table if X is a table, else as a *mat.Dense
+func subset(X mat.Matrix, columns []int) mat.Matrix {
+	if t, ok := X.(*table.Table); ok {
+		return t.SelectIndices(columns)
+	}
+	return selectColumns(X, columns)
+}
+
+// selectColumns returns a copy of the given columns of X
+func selectColumns(X mat.Matrix, columns []int) *mat.Dense {
+	nSamples, _ := X.Dims()
+	Xout := mat.NewDense(nSamples, len(columns), nil)
+	for i := 0; i < nSamples; i++ {
+		row := Xout.RawRowView(i)
+		for c, col := range columns {
+			row[c] = X.At(i, col)
+		}
+	}
+	return Xout
+}
@@ -0,0 +1,149 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+	"github.com/RobinRCM/sklearn/pipeline"
+	"github.com/RobinRCM/sklearn/preprocessing"
+	"github.com/RobinRCM/sklearn/table"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleColumnTransformer() {
+	// age, height and city code of 6 people
+	X := mat.NewDense(6, 3, []float64{
+		20, 160, 1,
+		30, 170, 2,
+		40, 180, 3,
+		50, 165, 1,
+		60, 175, 2,
+		70, 185, 3,
+	})
+	ct := NewColumnTransformer(
+		NamedTransformer{Name: "scaler", Transformer: preprocessing.NewStandardScaler(), Columns: []string{"age"}},
+		NamedTransformer{Name: "onehot", Transformer: preprocessing.NewOneHotEncoder(), Columns: "city"},
+	)
+	ct.FeatureNames = []string{"age", "height", "city"}
+	ct.Remainder = "passthrough"
+	Xt, _ := ct.FitTransform(X, nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+	fmt.Println("remainder columns:", ct.RemainderColumns, "output indices:", ct.OutputIndices)
+
+	// remainder is dropped by default, columns may also be selected by index
+	ct = NewColumnTransformer(NamedTransformer{Name: "minmax", Transformer: preprocessing.NewMinMaxScaler([]float64{0, 1}), Columns: []int{0, 1}})
+	ct.TransformerWeights = map[string]float64{"minmax": 2}
+	Xt, _ = ct.FitTransform(X, nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+	// Output:
+	// ⎡ -1.464    1.000    0.000    0.000  160.000⎤
+	// ⎢ -0.878    0.000    1.000    0.000  170.000⎥
+	// ⎢ -0.293    0.000    0.000    1.000  180.000⎥
+	// ⎢  0.293    1.000    0.000    0.000  165.000⎥
+	// ⎢  0.878    0.000    1.000    0.000  175.000⎥
+	// ⎣  1.464    0.000    0.000    1.000  185.000⎦
+	// remainder columns: [1] output indices: map[onehot:[1 4] remainder:[4 5] scaler:[0 1]]
+	// ⎡0.000  0.000⎤
+	// ⎢0.400  0.800⎥
+	// ⎢0.800  1.600⎥
+	// ⎢1.200  0.400⎥
+	// ⎢1.600  1.200⎥
+	// ⎣2.000  2.000⎦
+}
+
+func ExampleColumnTransformer_pipeline() {
+	// price depends linearly on surface and on district (a categorical column)
+	X := mat.NewDense(8, 2, []float64{
+		50, 1,
+		70, 2,
+		90, 3,
+		60, 1,
+		80, 2,
+		100, 3,
+		40, 2,
+		120, 1,
+	})
+	districtPremium := map[float64]float64{1: 100, 2: 50, 3: 0}
+	Y := mat.NewDense(8, 1, nil)
+	for i := 0; i < 8; i++ {
+		Y.Set(i, 0, 3*X.At(i, 0)+districtPremium[X.At(i, 1)])
+	}
+	ct := NewColumnTransformer(
+		NamedTransformer{Name: "surface", Transformer: "passthrough", Columns: 0},
+		NamedTransformer{Name: "district", Transformer: preprocessing.NewOneHotEncoder(), Columns: 1},
+	)
+	pl := pipeline.NewPipeline(pipeline.NamedStep{Name: "columns", Fiter: ct}, pipeline.NamedStep{Name: "regression", Fiter: linearmodel.NewLinearRegression()})
+	// a ColumnTransformer is clonable like any base.Transformer
+	pl = pl.PredicterClone().(*pipeline.Pipeline)
+	pl.Fit(X, Y)
+	Xtest := mat.NewDense(2, 2, []float64{75, 1, 75, 3})
+	fmt.Printf("predictions: %.1f\n", mat.Formatted(pl.Predict(Xtest, nil).T()))
+	// Output:
+	// predictions: [325.0  225.0]
+}
+
+func ExampleColumnTransformer_GetFeatureNamesOut() {
+	X := mat.NewDense(3, 3, []float64{
+		20, 160, 1,
+		30, 170, 2,
+		40, 180, 1,
+	})
+	ct := NewColumnTransformer(
+		NamedTransformer{Name: "scaler", Transformer: preprocessing.NewStandardScaler(), Columns: []string{"age"}},
+		NamedTransformer{Name: "onehot", Transformer: preprocessing.NewOneHotEncoder(), Columns: "city"},
+	)
+	ct.FeatureNames = []string{"age", "height", "city"}
+	ct.Remainder = "passthrough"
+	ct.Fit(X, nil)
+	fmt.Println(ct.GetFeatureNamesOut(nil))
+	// Output:
+	// [scaler__age onehot__city_1 onehot__city_2 remainder__height]
+}
+
+func ExampleColumnTransformer_table() {
+	data := `city,rooms,surface
+Paris,3,65
+London,2,50
+Paris,4,95
+Berlin,1,30
+`
+	t, err := table.ReadCSV(strings.NewReader(data), nil)
+	if err != nil {
+		panic(err)
+	}
+	// transformers are routed by column kind and receive tables, so encoders see the string categories
+	ct := NewColumnTransformer(
+		NamedTransformer{Name: "onehot", Transformer: preprocessing.NewOneHotEncoder(), Columns: table.KindSelector(table.Categorical)},
+		NamedTransformer{Name: "scaler", Transformer: preprocessing.NewMinMaxScaler([]float64{0, 1}), Columns: table.KindSelector(table.Int, table.Float)},
+	)
+	Xt, _ := ct.FitTransform(t, nil)
+	fmt.Println(ct.GetFeatureNamesOut(nil))
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+	// Output:
+	// [onehot__city_Berlin onehot__city_London onehot__city_Paris scaler__rooms scaler__surface]
+	// ⎡0.000  0.000  1.000  0.667  0.538⎤
+	// ⎢0.000  1.000  0.000  0.333  0.308⎥
+	// ⎢0.000  0.000  1.000  1.000  1.000⎥
+	// ⎣1.000  0.000  0.000  0.000  0.000⎦
+}
+
+func ExampleColumnTransformer_weightedState() {
+	// a transformer returning its own state, like the Embedding of Isomap
+	X := mat.NewDense(2, 2, []float64{0, 1, 2, 3})
+	state := mat.NewDense(2, 1, []float64{1, 2})
+	stateful := preprocessing.NewFunctionTransformer(
+		func(X, Y *mat.Dense) (*mat.Dense, *mat.Dense) { return state, Y },
+		nil,
+	)
+	ct := NewColumnTransformer(NamedTransformer{Name: "state", Transformer: stateful, Columns: []int{0}})
+	ct.TransformerWeights = map[string]float64{"state": 10}
+	for fit := 0; fit < 2; fit++ {
+		Xt, _ := ct.FitTransform(X, nil)
+		fmt.Printf("%g state: %g\n", mat.Formatted(Xt.T()), mat.Formatted(state.T()))
+	}
+	// Output:
+	// [10  20] state: [1  2]
+	// [10  20] state: [1  2]
+}
@@ -0,0 +1,2 @@
+// Package compose has meta-estimators building composite models from transformers, such as ColumnTransformer.
+package compose
@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/RobinRCM/sklearn/table"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -22,6 +24,8 @@ type MLDataset struct {
 	DESCR        string      `json:"DESCR,omitempty"`
 	FeatureNames []string    `json:"feature_names,omitempty"`
 	X, Y         *mat.Dense
+	// Table holds the features with their original types when the dataset is built by NewMLDatasetFromTable
+	Table *table.Table `json:"-"`
 }
 
 // fix data path for travis
@@ -0,0 +1,38 @@
+package datasets
+
+import (
+	"github.com/RobinRCM/sklearn/table"
+)
+
+// NewMLDatasetFromTable returns a dataset whose features are the columns of t except target.
+// X holds the features, categorical ones being coded by their index in their sorted categories (see table.Table), and
+// Table the feature columns with their original types, to be given to encoders or a compose.ColumnTransformer.
+// a categorical target is coded the same way and its categories are the TargetNames
+func NewMLDatasetFromTable(t *table.Table, target string) *MLDataset {
+	targetColumn := t.Column(target)
+	features := t.Drop(target)
+	nSamples, nFeatures := features.Dims()
+	ds := &MLDataset{FeatureNames: features.Names(), Table: features}
+	ds.Data = make([][]float64, nSamples)
+	ds.Target = make([]float64, nSamples)
+	for i := range ds.Data {
+		ds.Data[i] = make([]float64, nFeatures)
+		for j := range ds.Data[i] {
+			ds.Data[i][j] = features.At(i, j)
+		}
+		ds.Target[i] = targetColumn.Float(i)
+	}
+	if targetColumn.Kind == table.Categorical {
+		ds.TargetNames = append([]string(nil), targetColumn.Categories()...)
+	}
+	ds.X, ds.Y = ds.GetXY()
+	return ds
+}
+
+// ToTable returns the features of the dataset as a table: Table if it is set, else X with FeatureNames
+func (ds *MLDataset) ToTable() *table.Table {
+	if ds.Table != nil {
+		return ds.Table
+	}
+	return table.FromDense(ds.X, ds.FeatureNames)
+}
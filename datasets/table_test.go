@@ -0,0 +1,30 @@
+package datasets
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/table"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleNewMLDatasetFromTable() {
+	t := table.New(
+		table.NewCategoricalColumn("outlook", []string{"sunny", "rain", "overcast", "rain"}, nil),
+		table.NewFloatColumn("temperature", []float64{30, 18, 22, 15}),
+		table.NewCategoricalColumn("play", []string{"no", "yes", "yes", "no"}, nil),
+	)
+	ds := NewMLDatasetFromTable(t, "play")
+	fmt.Println(ds.FeatureNames, ds.TargetNames, ds.Table.Names())
+	fmt.Printf("%g\n", mat.Formatted(ds.X))
+	fmt.Printf("%g\n", mat.Formatted(ds.Y.T()))
+	fmt.Println(LoadIris().ToTable().Names())
+	// Output:
+	// [outlook temperature] [no yes] [outlook temperature]
+	// ⎡ 2  30⎤
+	// ⎢ 1  18⎥
+	// ⎢ 0  22⎥
+	// ⎣ 1  15⎦
+	// [0  1  1  0]
+	// [sepal length (cm) sepal width (cm) petal length (cm) petal width (cm)]
+}
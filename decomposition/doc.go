@@ -0,0 +1,2 @@
+// Package decomposition includes matrix decomposition algorithms such as TruncatedSVD, KernelPCA, NMF, FastICA and FactorAnalysis.
+package decomposition
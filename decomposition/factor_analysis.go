@@ -0,0 +1,311 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// FactorAnalysis is a linear generative model with gaussian latent variables and heteroscedastic gaussian noise:
+// x = Components' * h + Mean + noise where h ~ N(0, I) and noise ~ N(0, diag(NoiseVariance)).
+// parameters are estimated with an SVD based EM algorithm.
+// SVDMethod is "lapack" (full SVD, default) or "randomized" with IteratedPower power iterations.
+// Rotation is "", "varimax" or "quartimax".
+// NoiseVarianceInit is the initial noise variance of each feature (ones if nil)
+// see D. Barber, Bayesian Reasoning and Machine Learning, Algorithm 21.1
+type FactorAnalysis struct {
+	NComponents       int
+	Tol               float64
+	MaxIter           int
+	NoiseVarianceInit []float64
+	SVDMethod         string
+	IteratedPower     int
+	Rotation          string
+	RandomState       base.RandomState
+
+	Components          *mat.Dense
+	Mean, NoiseVariance []float64
+	LogLike             []float64
+	NIter               int
+}
+
+// NewFactorAnalysis returns a *FactorAnalysis
+func NewFactorAnalysis(NComponents int) *FactorAnalysis {
+	return &FactorAnalysis{NComponents: NComponents, Tol: 1e-2, MaxIter: 1000, SVDMethod: "lapack", IteratedPower: 3}
+}
+
+// TransformerClone ...
+func (m *FactorAnalysis) TransformerClone() base.Transformer {
+	clone := *m
+	if sourceCloner, ok := clone.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
+	return &clone
+}
+
+// Fit estimates Components and NoiseVariance from X
+func (m *FactorAnalysis) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	nComponents := m.NComponents
+	if nComponents <= 0 || nComponents > nFeatures {
+		nComponents = nFeatures
+	}
+	if nComponents > nSamples {
+		nComponents = nSamples
+	}
+	m.Mean = make([]float64, nFeatures)
+	for i := 0; i < nSamples; i++ {
+		floats.Add(m.Mean, X.RawRowView(i))
+	}
+	floats.Scale(1/float64(nSamples), m.Mean)
+	for i := 0; i < nSamples; i++ {
+		floats.Sub(X.RawRowView(i), m.Mean)
+	}
+	variance := make([]float64, nFeatures)
+	for i := 0; i < nSamples; i++ {
+		for j, v := range X.RawRowView(i) {
+			variance[j] += v * v / float64(nSamples)
+		}
+	}
+	nsqrt := math.Sqrt(float64(nSamples))
+	llconst := float64(nFeatures)*math.Log(2*math.Pi) + float64(nComponents)
+	psi := make([]float64, nFeatures)
+	if m.NoiseVarianceInit != nil {
+		if len(m.NoiseVarianceInit) != nFeatures {
+			panic(fmt.Errorf("FactorAnalysis: NoiseVarianceInit dimension %d does not match number of features %d", len(m.NoiseVarianceInit), nFeatures))
+		}
+		copy(psi, m.NoiseVarianceInit)
+	} else {
+		fill(psi, 1)
+	}
+	tol, maxIter := m.Tol, m.MaxIter
+	if maxIter <= 0 {
+		maxIter = 1000
+	}
+	const small = 1e-12
+	totalSquaredNorm := func(A *mat.Dense) (s float64) {
+		r, _ := A.Dims()
+		for i := 0; i < r; i++ {
+			s += floats.Dot(A.RawRowView(i), A.RawRowView(i))
+		}
+		return
+	}
+	// mySVD returns the squared singular values, right singular vectors and the unexplained variance of A
+	mySVD := func(A *mat.Dense) (s []float64, VT *mat.Dense, unexpVar float64) {
+		switch m.SVDMethod {
+		case "randomized":
+			_, s, VT = base.RandomizedSVD(A, nComponents, 10, m.IteratedPower, m.RandomState)
+			for i := range s {
+				s[i] *= s[i]
+			}
+			unexpVar = totalSquaredNorm(A) - floats.Sum(s)
+		case "", "lapack":
+			var svd mat.SVD
+			if !svd.Factorize(A, mat.SVDThin) {
+				panic("FactorAnalysis: svd failed")
+			}
+			V := &mat.Dense{}
+			svd.VTo(V)
+			s = svd.Values(nil)
+			for i := range s {
+				s[i] *= s[i]
+			}
+			unexpVar = floats.Sum(s[nComponents:])
+			s = s[:nComponents]
+			VT = mat.DenseCopyOf(V.Slice(0, nFeatures, 0, nComponents).T())
+		default:
+			panic(fmt.Errorf("FactorAnalysis: unknown SVDMethod %s", m.SVDMethod))
+		}
+		return
+	}
+
+	W := mat.NewDense(nComponents, nFeatures, nil)
+	Xs := mat.NewDense(nSamples, nFeatures, nil)
+	sqrtPsi := make([]float64, nFeatures)
+	oldLL := math.Inf(-1)
+	m.LogLike = m.LogLike[:0]
+	for m.NIter = 1; m.NIter <= maxIter; m.NIter++ {
+		for j := range sqrtPsi {
+			sqrtPsi[j] = math.Sqrt(psi[j]) + small
+		}
+		for i := 0; i < nSamples; i++ {
+			floats.DivTo(Xs.RawRowView(i), X.RawRowView(i), sqrtPsi)
+			floats.Scale(1/nsqrt, Xs.RawRowView(i))
+		}
+		s, VT, unexpVar := mySVD(Xs)
+		for c := 0; c < nComponents; c++ {
+			scale := math.Sqrt(math.Max(s[c]-1, 0))
+			floats.MulTo(W.RawRowView(c), VT.RawRowView(c), sqrtPsi)
+			floats.Scale(scale, W.RawRowView(c))
+		}
+		ll := llconst
+		for _, v := range s {
+			ll += math.Log(v)
+		}
+		ll += unexpVar
+		for _, v := range psi {
+			ll += math.Log(v)
+		}
+		ll *= -float64(nSamples) / 2
+		m.LogLike = append(m.LogLike, ll)
+		if ll-oldLL < tol {
+			break
+		}
+		oldLL = ll
+		for j := range psi {
+			w2 := 0.
+			for c := 0; c < nComponents; c++ {
+				w2 += W.At(c, j) * W.At(c, j)
+			}
+			psi[j] = math.Max(variance[j]-w2, small)
+		}
+	}
+	if m.NIter > maxIter {
+		m.NIter = maxIter
+	}
+	switch m.Rotation {
+	case "":
+	case "varimax", "quartimax":
+		W = orthoRotation(transposed(W), m.Rotation, 1e-6, 100)
+	default:
+		panic(fmt.Errorf("FactorAnalysis: unknown rotation %s", m.Rotation))
+	}
+	m.Components = W
+	m.NoiseVariance = psi
+	return m
+}
+
+// Transform returns the expected mean of the latent variables for X
+func (m *FactorAnalysis) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, _ := X.Dims()
+	nComponents, _ := m.Components.Dims()
+	for i := 0; i < nSamples; i++ {
+		floats.Sub(X.RawRowView(i), m.Mean)
+	}
+	Wpsi := mat.DenseCopyOf(m.Components)
+	for c := 0; c < nComponents; c++ {
+		floats.Div(Wpsi.RawRowView(c), m.NoiseVariance)
+	}
+	covZ := &mat.Dense{}
+	covZ.Mul(Wpsi, m.Components.T())
+	for c := 0; c < nComponents; c++ {
+		covZ.Set(c, c, covZ.At(c, c)+1)
+	}
+	if err := covZ.Inverse(covZ); err != nil {
+		panic(err)
+	}
+	tmp := &mat.Dense{}
+	tmp.Mul(X, Wpsi.T())
+	Xout = &mat.Dense{}
+	Xout.Mul(tmp, covZ)
+	return Xout, base.ToDense(Ymatrix)
+}
+
+// FitTransform fit to data, then transform it
+func (m *FactorAnalysis) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns factoranalysis0, factoranalysis1... one name per component. inputNames are unused
+func (m *FactorAnalysis) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("factoranalysis", m.Components.RawMatrix().Rows)
+}
+
+// GetCovariance computes data covariance with the generative model: Components' * Components + diag(NoiseVariance)
+func (m *FactorAnalysis) GetCovariance() *mat.Dense {
+	cov := &mat.Dense{}
+	cov.Mul(m.Components.T(), m.Components)
+	for j, v := range m.NoiseVariance {
+		cov.Set(j, j, cov.At(j, j)+v)
+	}
+	return cov
+}
+
+// GetPrecision computes data precision matrix (inverse of covariance) with the generative model.
+func (m *FactorAnalysis) GetPrecision() *mat.Dense {
+	precision := &mat.Dense{}
+	if err := precision.Inverse(m.GetCovariance()); err != nil {
+		panic(err)
+	}
+	return precision
+}
+
+// ScoreSamples returns the log-likelihood of each sample under the model
+func (m *FactorAnalysis) ScoreSamples(X mat.Matrix) []float64 {
+	nSamples, nFeatures := X.Dims()
+	Xc := mat.DenseCopyOf(X)
+	for i := 0; i < nSamples; i++ {
+		floats.Sub(Xc.RawRowView(i), m.Mean)
+	}
+	precision := m.GetPrecision()
+	logDetPrecision, _ := mat.LogDet(precision)
+	XP := &mat.Dense{}
+	XP.Mul(Xc, precision)
+	logLike := make([]float64, nSamples)
+	for i := range logLike {
+		logLike[i] = -.5*floats.Dot(XP.RawRowView(i), Xc.RawRowView(i)) - .5*(float64(nFeatures)*math.Log(2*math.Pi)-logDetPrecision)
+	}
+	return logLike
+}
+
+// Score returns the average log-likelihood of all samples. Y is unused
+func (m *FactorAnalysis) Score(X, Y mat.Matrix) float64 {
+	logLike := m.ScoreSamples(X)
+	return floats.Sum(logLike) / float64(len(logLike))
+}
+
+// orthoRotation returns the rotation of components (nFeatures,nComponents) maximizing the varimax or quartimax criterion,
+// transposed to (nComponents,nFeatures)
+func orthoRotation(components *mat.Dense, method string, tol float64, maxIter int) *mat.Dense {
+	nRows, nCols := components.Dims()
+	rotation := mat.NewDense(nCols, nCols, nil)
+	for c := 0; c < nCols; c++ {
+		rotation.Set(c, c, 1)
+	}
+	compRot, target, A := &mat.Dense{}, mat.NewDense(nRows, nCols, nil), &mat.Dense{}
+	colMeans := make([]float64, nCols)
+	variance := 0.
+	for iter := 0; iter < maxIter; iter++ {
+		compRot.Mul(components, rotation)
+		for c := range colMeans {
+			colMeans[c] = 0
+		}
+		if method == "varimax" {
+			for i := 0; i < nRows; i++ {
+				for c, v := range compRot.RawRowView(i) {
+					colMeans[c] += v * v / float64(nRows)
+				}
+			}
+		}
+		for i := 0; i < nRows; i++ {
+			row, t := compRot.RawRowView(i), target.RawRowView(i)
+			for c, v := range row {
+				t[c] = v*v*v - v*colMeans[c]
+			}
+		}
+		A.Mul(components.T(), target)
+		var svd mat.SVD
+		if !svd.Factorize(A, mat.SVDThin) {
+			panic("orthoRotation: svd failed")
+		}
+		U, V := &mat.Dense{}, &mat.Dense{}
+		svd.UTo(U)
+		svd.VTo(V)
+		rotation.Mul(U, V.T())
+		newVariance := floats.Sum(svd.Values(nil))
+		if variance != 0 && newVariance < variance*(1+tol) {
+			break
+		}
+		variance = newVariance
+	}
+	res := &mat.Dense{}
+	res.Mul(components, rotation)
+	return transposed(res)
+}
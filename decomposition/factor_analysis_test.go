@@ -0,0 +1,67 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleFactorAnalysis() {
+	// 6 noisy sensors driven by 2 latent factors
+	rng := rand.New(base.NewSource(7))
+	nSamples := 400
+	loadings := mat.NewDense(2, 6, []float64{
+		1, .9, .8, 0, 0, .1,
+		0, .1, 0, 1, .8, .9,
+	})
+	noise := []float64{.1, .2, .1, .3, .1, .2}
+	X := mat.NewDense(nSamples, 6, nil)
+	for i := 0; i < nSamples; i++ {
+		h := []float64{rng.NormFloat64(), rng.NormFloat64()}
+		for j := 0; j < 6; j++ {
+			X.Set(i, j, 10+h[0]*loadings.At(0, j)+h[1]*loadings.At(1, j)+noise[j]*rng.NormFloat64())
+		}
+	}
+	for _, rotation := range []string{"", "varimax", "quartimax"} {
+		fa := NewFactorAnalysis(2)
+		fa.Rotation = rotation
+		Xt, _ := fa.FitTransform(X, nil)
+		_, nComponents := Xt.Dims()
+		fmt.Printf("rotation %-9q components: %d score: %.3f\n", rotation, nComponents, fa.Score(X, nil))
+		if rotation == "varimax" {
+			fmt.Printf("noise std: %.2f\n", sqrts(fa.NoiseVariance))
+			fmt.Printf("loadings:\n%.1f\n", mat.Formatted(absDense(fa.Components)))
+		}
+	}
+	fa := NewFactorAnalysis(2)
+	fa.SVDMethod = "randomized"
+	fa.RandomState = base.NewSource(0)
+	fa.Fit(X, nil)
+	fmt.Printf("randomized score: %.3f\n", fa.Score(X, nil))
+	// Output:
+	// rotation ""        components: 2 score: -1.864
+	// rotation "varimax" components: 2 score: -1.864
+	// noise std: [0.09 0.19 0.10 0.30 0.11 0.17]
+	// loadings:
+	// ⎡1.0  0.9  0.8  0.0  0.0  0.1⎤
+	// ⎣0.0  0.1  0.0  0.9  0.8  0.8⎦
+	// rotation "quartimax" components: 2 score: -1.864
+	// randomized score: -1.864
+}
+
+func sqrts(x []float64) []float64 {
+	res := make([]float64, len(x))
+	for i, v := range x {
+		res[i] = math.Sqrt(v)
+	}
+	return res
+}
+
+func absDense(A mat.Matrix) *mat.Dense {
+	res := mat.DenseCopyOf(A)
+	res.Apply(func(i, j int, v float64) float64 { return math.Abs(v) }, res)
+	return res
+}
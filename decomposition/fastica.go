@@ -0,0 +1,379 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// FastICA is a fast algorithm for Independent Component Analysis.
+// Algorithm is "parallel" (default) or "deflation".
+// Fun is the G function used to approximate neg-entropy: "logcosh" (default), "exp", "cube", or a
+// func(x float64) (g, gprime float64). FunAlpha is the alpha parameter of "logcosh" (1 by default).
+// Whiten is "unit-variance" (default), "arbitrary-variance" or "" if data is already whitened.
+// WInit is the initial unmixing matrix (NComponents,NComponents), drawn from a normal distribution if nil.
+// Components is the unmixing matrix, Mixing its pseudo-inverse and Whitening the pre-whitening matrix
+// see A. Hyvarinen and E. Oja, Independent Component Analysis: Algorithms and Applications, Neural Networks, 13(4-5), 2000, pp. 411-430
+type FastICA struct {
+	NComponents int
+	Algorithm   string
+	Whiten      string
+	Fun         interface{}
+	FunAlpha    float64
+	MaxIter     int
+	Tol         float64
+	WInit       *mat.Dense
+	RandomState base.RandomState
+
+	Components, Mixing, Whitening *mat.Dense
+	Mean                          []float64
+	NIter                         int
+}
+
+var _ preprocessing.InverseTransformer = &FastICA{}
+
+// NewFastICA returns a *FastICA
+func NewFastICA(NComponents int) *FastICA {
+	return &FastICA{NComponents: NComponents, Algorithm: "parallel", Whiten: "unit-variance", Fun: "logcosh", FunAlpha: 1, MaxIter: 200, Tol: 1e-4}
+}
+
+// TransformerClone ...
+func (m *FastICA) TransformerClone() base.Transformer {
+	clone := *m
+	if sourceCloner, ok := clone.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
+	return &clone
+}
+
+// Fit computes Components and Mixing from X
+func (m *FastICA) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform fits the model and returns the estimated sources
+func (m *FastICA) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	nComponents := m.NComponents
+	if nComponents <= 0 || nComponents > nFeatures || nComponents > nSamples {
+		nComponents = nFeatures
+		if nSamples < nComponents {
+			nComponents = nSamples
+		}
+	}
+	g := m.getFun()
+	maxIter, tol := m.MaxIter, m.Tol
+	if maxIter <= 0 {
+		maxIter = 200
+	}
+	// X1 (nComponents,nSamples) holds whitened samples as columns
+	var X1, K *mat.Dense
+	switch m.Whiten {
+	case "":
+		if nComponents != nFeatures {
+			panic(fmt.Errorf("FastICA: NComponents must be NFeatures when Whiten is not set"))
+		}
+		m.Mean = nil
+		X1 = transposed(X)
+	case "unit-variance", "arbitrary-variance":
+		m.Mean = make([]float64, nFeatures)
+		for i := 0; i < nSamples; i++ {
+			floats.Add(m.Mean, X.RawRowView(i))
+		}
+		floats.Scale(1/float64(nSamples), m.Mean)
+		for i := 0; i < nSamples; i++ {
+			floats.Sub(X.RawRowView(i), m.Mean)
+		}
+		var svd mat.SVD
+		if !svd.Factorize(X.T(), mat.SVDThin) {
+			panic("FastICA: svd failed")
+		}
+		U := &mat.Dense{}
+		svd.UTo(U)
+		d := svd.Values(nil)
+		K = mat.NewDense(nComponents, nFeatures, nil)
+		for c := 0; c < nComponents; c++ {
+			// make the first element of each singular vector positive
+			sign := 1.
+			if U.At(0, c) < 0 {
+				sign = -1
+			}
+			for j := 0; j < nFeatures; j++ {
+				K.Set(c, j, sign*U.At(j, c)/d[c])
+			}
+		}
+		X1 = &mat.Dense{}
+		X1.Mul(K, X.T())
+		X1.Scale(math.Sqrt(float64(nSamples)), X1)
+	default:
+		panic(fmt.Errorf("FastICA: unknown whiten %s", m.Whiten))
+	}
+
+	wInit := m.WInit
+	if wInit == nil {
+		normFloat64 := normFloat64Func(m.RandomState)
+		wInit = mat.NewDense(nComponents, nComponents, nil)
+		data := wInit.RawMatrix().Data
+		for i := range data {
+			data[i] = normFloat64()
+		}
+	} else if r, c := wInit.Dims(); r != nComponents || c != nComponents {
+		panic(fmt.Errorf("FastICA: WInit has invalid shape %dx%d, expected %dx%d", r, c, nComponents, nComponents))
+	}
+	var W *mat.Dense
+	switch m.Algorithm {
+	case "", "parallel":
+		W, m.NIter = icaParallel(X1, wInit, g, tol, maxIter)
+	case "deflation":
+		W, m.NIter = icaDeflation(X1, wInit, g, tol, maxIter)
+	default:
+		panic(fmt.Errorf("FastICA: unknown algorithm %s", m.Algorithm))
+	}
+
+	if K != nil {
+		m.Components = &mat.Dense{}
+		m.Components.Mul(W, K)
+		m.Whitening = K
+	} else {
+		m.Components = W
+		m.Whitening = nil
+	}
+	// S (nSamples,nComponents) estimated sources
+	S := &mat.Dense{}
+	S.Mul(X, m.Components.T())
+	if m.Whiten == "unit-variance" {
+		col := make([]float64, nSamples)
+		for c := 0; c < nComponents; c++ {
+			mat.Col(col, c, S)
+			std := math.Sqrt(popVariance(col))
+			if std == 0 {
+				continue
+			}
+			floats.Scale(1/std, col)
+			S.SetCol(c, col)
+			floats.Scale(1/std, m.Components.RawRowView(c))
+		}
+	}
+	m.Mixing = pinv(m.Components)
+	return S, base.ToDense(Ymatrix)
+}
+
+// GetFeatureNamesOut returns fastica0, fastica1... one name per component. inputNames are unused
+func (m *FastICA) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("fastica", m.Components.RawMatrix().Rows)
+}
+
+// Transform recovers the sources from X
+func (m *FastICA) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	if m.Mean != nil {
+		nSamples, _ := X.Dims()
+		for i := 0; i < nSamples; i++ {
+			floats.Sub(X.RawRowView(i), m.Mean)
+		}
+	}
+	Xout = &mat.Dense{}
+	Xout.Mul(X, m.Components.T())
+	return Xout, base.ToDense(Ymatrix)
+}
+
+// InverseTransform mixes sources X back into the original space
+func (m *FastICA) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	Xout = &mat.Dense{}
+	Xout.Mul(X, m.Mixing.T())
+	if m.Mean != nil {
+		nSamples, _ := Xout.Dims()
+		for i := 0; i < nSamples; i++ {
+			floats.Add(Xout.RawRowView(i), m.Mean)
+		}
+	}
+	return Xout, Y
+}
+
+// getFun returns the non-linearity g and its derivative g'
+func (m *FastICA) getFun() func(x float64) (g, gprime float64) {
+	alpha := m.FunAlpha
+	if alpha <= 0 {
+		alpha = 1
+	}
+	switch v := m.Fun.(type) {
+	case func(x float64) (g, gprime float64):
+		return v
+	case nil:
+	case string:
+		switch v {
+		case "", "logcosh":
+		case "exp":
+			return func(x float64) (float64, float64) {
+				e := math.Exp(-x * x / 2)
+				return x * e, (1 - x*x) * e
+			}
+		case "cube":
+			return func(x float64) (float64, float64) { return x * x * x, 3 * x * x }
+		default:
+			panic(fmt.Errorf("FastICA: unknown fun %s", v))
+		}
+	default:
+		panic(fmt.Errorf("FastICA: unknown fun %#v", v))
+	}
+	return func(x float64) (float64, float64) {
+		t := math.Tanh(alpha * x)
+		return t, alpha * (1 - t*t)
+	}
+}
+
+// icaParallel estimates all components simultaneously using symmetric decorrelation
+func icaParallel(X, wInit *mat.Dense, g func(float64) (float64, float64), tol float64, maxIter int) (W *mat.Dense, nIter int) {
+	nComponents, nSamples := X.Dims()
+	W = symDecorrelation(wInit)
+	WX, gWX := &mat.Dense{}, mat.NewDense(nComponents, nSamples, nil)
+	gprimeMean := make([]float64, nComponents)
+	W1 := &mat.Dense{}
+	for nIter = 1; nIter <= maxIter; nIter++ {
+		WX.Mul(W, X)
+		for c := 0; c < nComponents; c++ {
+			wx, gwx := WX.RawRowView(c), gWX.RawRowView(c)
+			gprimeMean[c] = 0
+			for i, v := range wx {
+				var gp float64
+				gwx[i], gp = g(v)
+				gprimeMean[c] += gp
+			}
+			gprimeMean[c] /= float64(nSamples)
+		}
+		W1.Mul(gWX, X.T())
+		W1.Scale(1/float64(nSamples), W1)
+		for c := 0; c < nComponents; c++ {
+			floats.AddScaled(W1.RawRowView(c), -gprimeMean[c], W.RawRowView(c))
+		}
+		W1 = symDecorrelation(W1)
+		lim := 0.
+		for c := 0; c < nComponents; c++ {
+			lim = math.Max(lim, math.Abs(math.Abs(floats.Dot(W1.RawRowView(c), W.RawRowView(c)))-1))
+		}
+		W, W1 = W1, W
+		if lim < tol {
+			break
+		}
+	}
+	if nIter > maxIter {
+		nIter = maxIter
+	}
+	return
+}
+
+// icaDeflation estimates components one by one using Gram-Schmidt decorrelation
+func icaDeflation(X, wInit *mat.Dense, g func(float64) (float64, float64), tol float64, maxIter int) (W *mat.Dense, nIter int) {
+	nComponents, nSamples := X.Dims()
+	W = mat.NewDense(nComponents, nComponents, nil)
+	wx := mat.NewVecDense(nSamples, nil)
+	gwx := make([]float64, nSamples)
+	for j := 0; j < nComponents; j++ {
+		w := append([]float64{}, wInit.RawRowView(j)...)
+		floats.Scale(1/floats.Norm(w, 2), w)
+		w1 := mat.NewVecDense(nComponents, nil)
+		var i int
+		for i = 1; i <= maxIter; i++ {
+			wx.MulVec(X.T(), mat.NewVecDense(nComponents, w))
+			gprimeMean := 0.
+			for k, v := range wx.RawVector().Data {
+				var gp float64
+				gwx[k], gp = g(v)
+				gprimeMean += gp
+			}
+			gprimeMean /= float64(nSamples)
+			w1.MulVec(X, mat.NewVecDense(nSamples, gwx))
+			w1data := w1.RawVector().Data
+			floats.Scale(1/float64(nSamples), w1data)
+			floats.AddScaled(w1data, -gprimeMean, w)
+			// decorrelate against previously found components
+			for k := 0; k < j; k++ {
+				floats.AddScaled(w1data, -floats.Dot(w1data, W.RawRowView(k)), W.RawRowView(k))
+			}
+			floats.Scale(1/floats.Norm(w1data, 2), w1data)
+			lim := math.Abs(math.Abs(floats.Dot(w1data, w)) - 1)
+			copy(w, w1data)
+			if lim < tol {
+				break
+			}
+		}
+		if i > maxIter {
+			i = maxIter
+		}
+		if i > nIter {
+			nIter = i
+		}
+		copy(W.RawRowView(j), w)
+	}
+	return
+}
+
+// symDecorrelation returns (W * W')^{-1/2} * W
+func symDecorrelation(W *mat.Dense) *mat.Dense {
+	n, _ := W.Dims()
+	WWt := mat.NewSymDense(n, nil)
+	WWt.SymOuterK(1, W)
+	var eig mat.EigenSym
+	if !eig.Factorize(WWt, true) {
+		panic("FastICA: eigen decomposition failed")
+	}
+	s := eig.Values(nil)
+	u := &mat.Dense{}
+	eig.VectorsTo(u)
+	scaled := mat.DenseCopyOf(u)
+	tiny := math.SmallestNonzeroFloat64
+	for c := 0; c < n; c++ {
+		d := 1 / math.Sqrt(math.Max(s[c], tiny))
+		for i := 0; i < n; i++ {
+			scaled.Set(i, c, scaled.At(i, c)*d)
+		}
+	}
+	M := &mat.Dense{}
+	M.Mul(scaled, u.T())
+	res := &mat.Dense{}
+	res.Mul(M, W)
+	return res
+}
+
+// pinv returns the Moore-Penrose pseudo-inverse of A
+func pinv(A mat.Matrix) *mat.Dense {
+	r, c := A.Dims()
+	var svd mat.SVD
+	if !svd.Factorize(A, mat.SVDThin) {
+		panic("pinv: svd failed")
+	}
+	U, V := &mat.Dense{}, &mat.Dense{}
+	svd.UTo(U)
+	svd.VTo(V)
+	s := svd.Values(nil)
+	cutoff := 1e-15 * float64(maxInt(r, c)) * floats.Max(s)
+	for k, v := range s {
+		inv := 0.
+		if v > cutoff {
+			inv = 1 / v
+		}
+		for i := 0; i < c; i++ {
+			V.Set(i, k, V.At(i, k)*inv)
+		}
+	}
+	res := mat.NewDense(c, r, nil)
+	res.Mul(V, U.T())
+	return res
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
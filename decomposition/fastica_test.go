@@ -0,0 +1,59 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func ExampleFastICA() {
+	// two independent sources: a sinusoidal and a square signal, observed through a mixing matrix
+	nSamples := 500
+	S := mat.NewDense(nSamples, 2, nil)
+	for i := 0; i < nSamples; i++ {
+		t := 8 * float64(i) / float64(nSamples)
+		S.Set(i, 0, math.Sin(2*t))
+		S.Set(i, 1, math.Copysign(1, math.Sin(3*t)))
+	}
+	A := mat.NewDense(2, 2, []float64{1, 1, .5, 2})
+	X := &mat.Dense{}
+	X.Mul(S, A.T())
+
+	for _, algorithm := range []string{"parallel", "deflation"} {
+		for _, fun := range []string{"logcosh", "exp", "cube"} {
+			ica := NewFastICA(2)
+			ica.Algorithm = algorithm
+			ica.Fun = fun
+			ica.RandomState = base.NewSource(0)
+			Sest, _ := ica.FitTransform(X, nil)
+			Xr, _ := ica.InverseTransform(Sest, nil)
+			fmt.Printf("%-9s %-7s correlation with sources: %.3f reconstruction error: %.3f\n", algorithm, fun, bestAbsCorrelations(S, Sest), maxAbsDiff(X, Xr))
+		}
+	}
+	// Output:
+	// parallel  logcosh correlation with sources: [0.998 1.000] reconstruction error: 0.000
+	// parallel  exp     correlation with sources: [0.998 1.000] reconstruction error: 0.000
+	// parallel  cube    correlation with sources: [0.999 0.999] reconstruction error: 0.000
+	// deflation logcosh correlation with sources: [0.996 1.000] reconstruction error: 0.000
+	// deflation exp     correlation with sources: [0.996 1.000] reconstruction error: 0.000
+	// deflation cube    correlation with sources: [0.996 1.000] reconstruction error: 0.000
+}
+
+// bestAbsCorrelations returns, for each column of S, the largest absolute correlation with a column of Sest
+func bestAbsCorrelations(S, Sest mat.Matrix) []float64 {
+	nSamples, nSources := S.Dims()
+	_, nEst := Sest.Dims()
+	res := make([]float64, nSources)
+	a, b := make([]float64, nSamples), make([]float64, nSamples)
+	for i := range res {
+		mat.Col(a, i, S)
+		for j := 0; j < nEst; j++ {
+			mat.Col(b, j, Sest)
+			res[i] = math.Max(res[i], math.Abs(stat.Correlation(a, b, nil)))
+		}
+	}
+	return res
+}
@@ -0,0 +1,234 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/preprocessing"
+	"github.com/RobinRCM/sklearn/svm"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// KernelPCA is a non-linear dimensionality reduction through the use of kernels.
+// Kernel is "linear","poly","rbf","sigmoid","cosine","precomputed", a svm.Kernel or a func(a, b []float64) float64.
+// with "precomputed", X passed to Fit is the (nSamples,nSamples) kernel matrix and X passed to Transform is the
+// kernel between new samples and fit samples.
+// if Gamma<=0 it will be changed to 1/NFeatures.
+// if NComponents<=0, all components with non-zero eigenvalues are kept. RemoveZeroEig also drops zero eigenvalues
+// when NComponents>0.
+// if FitInverseTransform is true, a kernel ridge regression with regularization Alpha is learned from the projections
+// to the original samples, so that InverseTransform can find pre-images
+type KernelPCA struct {
+	NComponents         int
+	Kernel              interface{}
+	Gamma, Degree       float64
+	Coef0               float64
+	Alpha               float64
+	FitInverseTransform bool
+	RemoveZeroEig       bool
+
+	Eigenvalues                     []float64
+	Eigenvectors                    *mat.Dense
+	XFit, XTransformedFit, DualCoef *mat.Dense
+	Centerer                        *preprocessing.KernelCenterer
+	kernel                          func(a, b []float64) float64
+}
+
+var _ preprocessing.InverseTransformer = &KernelPCA{}
+
+// NewKernelPCA returns a *KernelPCA with a linear kernel
+func NewKernelPCA() *KernelPCA {
+	return &KernelPCA{Kernel: "linear", Degree: 3, Coef0: 1, Alpha: 1}
+}
+
+// TransformerClone ...
+func (m *KernelPCA) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit computes the eigen decomposition of the centered kernel matrix of X
+func (m *KernelPCA) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform fits the model with X and returns the projections of X
+func (m *KernelPCA) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.XFit = mat.DenseCopyOf(Xmatrix)
+	nSamples, nFeatures := m.XFit.Dims()
+	if m.Gamma <= 0 {
+		m.Gamma = 1. / float64(nFeatures)
+	}
+	m.kernel = m.getKernel()
+	K := m.XFit
+	if m.kernel != nil {
+		K = pairwiseKernel(m.XFit, m.XFit, m.kernel)
+	} else if m.FitInverseTransform {
+		panic(fmt.Errorf("KernelPCA: FitInverseTransform is not supported with a precomputed kernel"))
+	}
+	m.Centerer = preprocessing.NewKernelCenterer()
+	m.Centerer.Fit(K, nil)
+	Kc, _ := m.Centerer.Transform(K, nil)
+	Ksym := mat.NewSymDense(nSamples, nil)
+	for i := 0; i < nSamples; i++ {
+		for j := i; j < nSamples; j++ {
+			Ksym.SetSym(i, j, (Kc.At(i, j)+Kc.At(j, i))/2)
+		}
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(Ksym, true) {
+		panic("KernelPCA: eigen decomposition failed")
+	}
+	values := eig.Values(nil)
+	vectors := &mat.Dense{}
+	eig.VectorsTo(vectors)
+	// sort eigenvalues in decreasing order, clipping tiny negative values due to rounding errors
+	order := make([]int, nSamples)
+	for i := range order {
+		order[i] = i
+		if values[i] < 0 {
+			values[i] = 0
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] > values[order[j]] })
+	nComponents := m.NComponents
+	if nComponents <= 0 || nComponents > nSamples {
+		nComponents = nSamples
+	}
+	if m.RemoveZeroEig || m.NComponents <= 0 {
+		for nComponents > 0 && values[order[nComponents-1]] <= 1e-12*values[order[0]] {
+			nComponents--
+		}
+	}
+	m.Eigenvalues = make([]float64, nComponents)
+	m.Eigenvectors = mat.NewDense(nSamples, nComponents, nil)
+	col := make([]float64, nSamples)
+	for c := 0; c < nComponents; c++ {
+		m.Eigenvalues[c] = values[order[c]]
+		mat.Col(col, order[c], vectors)
+		m.Eigenvectors.SetCol(c, col)
+	}
+	// make eigenvectors deterministic: largest absolute value of each is positive
+	base.SVDFlip(m.Eigenvectors, mat.NewDense(nComponents, 1, nil), true)
+
+	Xout = mat.NewDense(nSamples, nComponents, nil)
+	for i := 0; i < nSamples; i++ {
+		for c, ev := range m.Eigenvalues {
+			Xout.Set(i, c, m.Eigenvectors.At(i, c)*math.Sqrt(ev))
+		}
+	}
+	m.XTransformedFit, m.DualCoef = nil, nil
+	if m.FitInverseTransform {
+		m.fitInverseTransform(Xout)
+	}
+	Yout = base.ToDense(Ymatrix)
+	return
+}
+
+// GetFeatureNamesOut returns kernelpca0, kernelpca1... one name per component. inputNames are unused
+func (m *KernelPCA) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("kernelpca", m.Eigenvectors.RawMatrix().Cols)
+}
+
+// Transform projects X on the principal components of the kernel space
+func (m *KernelPCA) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	nSamples, _ := X.Dims()
+	var K *mat.Dense
+	if m.kernel != nil {
+		K = pairwiseKernel(mat.DenseCopyOf(X), m.XFit, m.kernel)
+	} else {
+		K = mat.DenseCopyOf(X)
+	}
+	Kc, _ := m.Centerer.Transform(K, nil)
+	nFit, nComponents := m.Eigenvectors.Dims()
+	scaled := mat.NewDense(nFit, nComponents, nil)
+	for c, ev := range m.Eigenvalues {
+		if ev == 0 {
+			continue
+		}
+		for i := 0; i < nFit; i++ {
+			scaled.Set(i, c, m.Eigenvectors.At(i, c)/math.Sqrt(ev))
+		}
+	}
+	Xout = mat.NewDense(nSamples, nComponents, nil)
+	Xout.Mul(Kc, scaled)
+	Yout = base.ToDense(Y)
+	return
+}
+
+// InverseTransform finds pre-images of X in original space. FitInverseTransform must be set before Fit
+// see Bakir G., Weston J., Scholkopf B., Learning to Find Pre-Images, NIPS 2004
+func (m *KernelPCA) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	if m.DualCoef == nil {
+		panic(fmt.Errorf("KernelPCA: InverseTransform needs FitInverseTransform to be set before Fit"))
+	}
+	nSamples, _ := X.Dims()
+	_, nFeatures := m.DualCoef.Dims()
+	K := pairwiseKernel(X, m.XTransformedFit, m.kernel)
+	Xout = mat.NewDense(nSamples, nFeatures, nil)
+	Xout.Mul(K, m.DualCoef)
+	Yout = Y
+	return
+}
+
+// fitInverseTransform learns DualCoef by kernel ridge regression of XFit on XTransformed
+func (m *KernelPCA) fitInverseTransform(XTransformed *mat.Dense) {
+	nSamples, _ := XTransformed.Dims()
+	K := pairwiseKernel(XTransformed, XTransformed, m.kernel)
+	for i := 0; i < nSamples; i++ {
+		K.Set(i, i, K.At(i, i)+m.Alpha)
+	}
+	m.DualCoef = &mat.Dense{}
+	if err := m.DualCoef.Solve(K, m.XFit); err != nil {
+		panic(fmt.Errorf("KernelPCA: %s", err))
+	}
+	m.XTransformedFit = XTransformed
+}
+
+func (m *KernelPCA) getKernel() func(a, b []float64) float64 {
+	switch v := m.Kernel.(type) {
+	case func(a, b []float64) float64:
+		return v
+	case string:
+		switch v {
+		case "", "linear":
+			return (svm.LinearKernel{}).Func
+		case "poly", "polynomial":
+			return (svm.PolynomialKernel{Gamma: m.Gamma, Coef0: m.Coef0, Degree: m.Degree}).Func
+		case "rbf":
+			return (svm.RBFKernel{Gamma: m.Gamma}).Func
+		case "sigmoid":
+			return (svm.SigmoidKernel{Gamma: m.Gamma, Coef0: m.Coef0}).Func
+		case "cosine":
+			return (svm.CosineKernel{}).Func
+		case "precomputed":
+			return nil
+		}
+	case svm.Kernel:
+		return v.Func
+	}
+	panic(fmt.Errorf("KernelPCA: unknown kernel %#v", m.Kernel))
+}
+
+// pairwiseKernel returns the matrix K(X[i],Y[j])
+func pairwiseKernel(X, Y *mat.Dense, kernel func(a, b []float64) float64) *mat.Dense {
+	nx, _ := X.Dims()
+	ny, _ := Y.Dims()
+	K := mat.NewDense(nx, ny, nil)
+	base.Parallelize(-1, nx, func(th, start, end int) {
+		for i := start; i < end; i++ {
+			x, row := X.RawRowView(i), K.RawRowView(i)
+			for j := range row {
+				row[j] = kernel(x, Y.RawRowView(j))
+			}
+		}
+	})
+	return K
+}
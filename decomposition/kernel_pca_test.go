@@ -0,0 +1,63 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/preprocessing"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleKernelPCA() {
+	X := mat.NewDense(6, 2, []float64{-1., -1., -2., -1., -3., -2., 1., 1., 2., 1., 3., 2.})
+	// with a linear kernel, KernelPCA projections are those of PCA, up to sign
+	kpca := NewKernelPCA()
+	kpca.NComponents = 2
+	Xk, _ := kpca.FitTransform(X, nil)
+	pca := preprocessing.NewPCA()
+	Xp, _ := pca.FitTransform(X, nil)
+	same := true
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 2; j++ {
+			same = same && math.Abs(math.Abs(Xk.At(i, j))-math.Abs(Xp.At(i, j))) < 1e-10
+		}
+	}
+	fmt.Printf("eigenvalues: %.3f\n", kpca.Eigenvalues)
+	fmt.Printf("same as PCA: %t\n", same)
+	// Output:
+	// eigenvalues: [39.698 0.302]
+	// same as PCA: true
+}
+
+func ExampleKernelPCA_InverseTransform() {
+	// points on two concentric circles
+	X := mat.NewDense(16, 2, nil)
+	for i := 0; i < 16; i++ {
+		r := 1.
+		if i%2 == 1 {
+			r = 3
+		}
+		theta := float64(i) * math.Pi / 8
+		X.Set(i, 0, r*math.Cos(theta))
+		X.Set(i, 1, r*math.Sin(theta))
+	}
+	for _, kernel := range []string{"rbf", "poly", "sigmoid", "cosine"} {
+		kpca := NewKernelPCA()
+		kpca.Kernel = kernel
+		kpca.NComponents = 4
+		kpca.FitInverseTransform = true
+		kpca.Alpha = .001
+		kpca.RemoveZeroEig = true
+		Xt, _ := kpca.FitTransform(X, nil)
+		Xt2, _ := kpca.Transform(X, nil)
+		Xr, _ := kpca.InverseTransform(Xt, nil)
+		diff := &mat.Dense{}
+		diff.Sub(X, Xr)
+		fmt.Printf("%-8s components: %d same transform: %t reconstruction error: %.3f\n", kernel, len(kpca.Eigenvalues), mat.EqualApprox(Xt, Xt2, 1e-8), mat.Norm(diff, 2)/mat.Norm(X, 2))
+	}
+	// Output:
+	// rbf      components: 4 same transform: true reconstruction error: 0.018
+	// poly     components: 4 same transform: true reconstruction error: 0.000
+	// sigmoid  components: 4 same transform: true reconstruction error: 0.028
+	// cosine   components: 2 same transform: true reconstruction error: 0.447
+}
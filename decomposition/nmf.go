@@ -0,0 +1,447 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// NMF finds two non-negative matrices W (nSamples,NComponents) and H (NComponents,nFeatures) whose product approximates X.
+// the objective is BetaDivergence(X, W*H) + regularization on W and H where the divergence is
+// BetaLoss "frobenius" (0.5*||X-WH||²) or "kullback-leibler".
+// Solver is "cd" (coordinate descent, frobenius only) or "mu" (multiplicative update).
+// Init is "random", "nndsvd", "nndsvda" (zeros filled with the average of X) or "nndsvdar" (zeros filled with small random values).
+// default init is "nndsvda" when NComponents<=min(nSamples,nFeatures), else "random".
+// AlphaW and AlphaH are the regularization strengths of W and H, L1Ratio mixes L1 (1) and L2 (0) penalties.
+// regularization terms are scaled by nFeatures for W and by nSamples for H.
+// Components is H. Transform computes W for new rows with H fixed
+type NMF struct {
+	NComponents    int
+	Init           string
+	Solver         string
+	BetaLoss       string
+	Tol            float64
+	MaxIter        int
+	RandomState    base.RandomState
+	AlphaW, AlphaH float64
+	L1Ratio        float64
+	Shuffle        bool
+
+	Components        *mat.Dense
+	ReconstructionErr float64
+	NIter             int
+}
+
+var _ preprocessing.InverseTransformer = &NMF{}
+
+// NewNMF returns a *NMF
+func NewNMF(NComponents int) *NMF {
+	return &NMF{NComponents: NComponents, Solver: "cd", BetaLoss: "frobenius", Tol: 1e-4, MaxIter: 200}
+}
+
+// TransformerClone ...
+func (m *NMF) TransformerClone() base.Transformer {
+	clone := *m
+	if sourceCloner, ok := clone.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
+	return &clone
+}
+
+// Fit learns Components from X
+func (m *NMF) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform learns Components from X and returns W
+func (m *NMF) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := m.checkNonNegative(Xmatrix)
+	Xout, m.Components, m.NIter = m.fitTransform(X, nil, true)
+	m.ReconstructionErr = betaDivergence(X, Xout, m.Components, m.beta(), true)
+	Yout = base.ToDense(Ymatrix)
+	return
+}
+
+// GetFeatureNamesOut returns nmf0, nmf1... one name per component. inputNames are unused
+func (m *NMF) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("nmf", m.Components.RawMatrix().Rows)
+}
+
+// Transform returns W for X with Components fixed
+func (m *NMF) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := m.checkNonNegative(Xmatrix)
+	Xout, _, _ = m.fitTransform(X, m.Components, false)
+	Yout = base.ToDense(Ymatrix)
+	return
+}
+
+// InverseTransform returns W*Components
+func (m *NMF) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	Xout = &mat.Dense{}
+	Xout.Mul(X, m.Components)
+	Yout = Y
+	return
+}
+
+func (m *NMF) checkNonNegative(Xmatrix mat.Matrix) *mat.Dense {
+	X := base.ToDense(Xmatrix)
+	r, c := X.Dims()
+	for i := 0; i < r; i++ {
+		for _, v := range X.RawRowView(i)[:c] {
+			if v < 0 || math.IsNaN(v) {
+				panic(fmt.Errorf("NMF: negative or NaN values in X"))
+			}
+		}
+	}
+	return X
+}
+
+func (m *NMF) beta() float64 {
+	switch m.BetaLoss {
+	case "", "frobenius":
+		return 2
+	case "kullback-leibler":
+		return 1
+	}
+	panic(fmt.Errorf("NMF: unsupported BetaLoss %s", m.BetaLoss))
+}
+
+// fitTransform computes W and H (H is only updated if updateH is true)
+func (m *NMF) fitTransform(X, H *mat.Dense, updateH bool) (W, Hout *mat.Dense, nIter int) {
+	nSamples, nFeatures := X.Dims()
+	nComponents := m.NComponents
+	if nComponents <= 0 {
+		nComponents = nFeatures
+	}
+	solver := m.Solver
+	if solver == "" {
+		solver = "cd"
+	}
+	beta := m.beta()
+	if solver == "cd" && beta != 2 {
+		panic(fmt.Errorf("NMF: solver cd only supports frobenius BetaLoss"))
+	}
+	tol, maxIter := m.Tol, m.MaxIter
+	if maxIter <= 0 {
+		maxIter = 200
+	}
+	if updateH {
+		W, H = m.initialize(X, nComponents)
+	} else {
+		H = mat.DenseCopyOf(H)
+		W = mat.NewDense(nSamples, nComponents, nil)
+		if solver == "mu" {
+			avg := math.Sqrt(denseMean(X) / float64(nComponents))
+			fill(W.RawMatrix().Data, avg)
+		}
+	}
+	XT := transposed(X)
+	l1W := float64(nFeatures) * m.AlphaW * m.L1Ratio
+	l2W := float64(nFeatures) * m.AlphaW * (1 - m.L1Ratio)
+	l1H := float64(nSamples) * m.AlphaH * m.L1Ratio
+	l2H := float64(nSamples) * m.AlphaH * (1 - m.L1Ratio)
+
+	switch solver {
+	case "cd":
+		var perm func(int) []int
+		if m.Shuffle {
+			perm = permFunc(m.RandomState)
+		}
+		Ht := mat.DenseCopyOf(H.T())
+		var violationInit float64
+		for nIter = 1; nIter <= maxIter; nIter++ {
+			violation := updateCoordinateDescent(X, W, Ht, l1W, l2W, perm)
+			if updateH {
+				violation += updateCoordinateDescent(XT, Ht, W, l1H, l2H, perm)
+			}
+			if nIter == 1 {
+				violationInit = violation
+			}
+			if violationInit == 0 || violation/violationInit <= tol {
+				break
+			}
+		}
+		if nIter > maxIter {
+			nIter = maxIter
+		}
+		H = mat.DenseCopyOf(Ht.T())
+	case "mu":
+		errorAtInit := betaDivergence(X, W, H, beta, true)
+		previousError := errorAtInit
+		for nIter = 1; nIter <= maxIter; nIter++ {
+			multiplicativeUpdateW(X, W, H, beta, l1W, l2W)
+			if updateH {
+				Ht := transposed(H)
+				multiplicativeUpdateW(XT, Ht, transposed(W), beta, l1H, l2H)
+				H = transposed(Ht)
+			}
+			if tol > 0 && nIter%10 == 0 {
+				errorNow := betaDivergence(X, W, H, beta, true)
+				if (previousError-errorNow)/errorAtInit < tol {
+					break
+				}
+				previousError = errorNow
+			}
+		}
+		if nIter > maxIter {
+			nIter = maxIter
+		}
+	default:
+		panic(fmt.Errorf("NMF: unknown solver %s", solver))
+	}
+	return W, H, nIter
+}
+
+// initialize computes initial W and H according to Init
+// see C. Boutsidis, E. Gallopoulos: SVD based initialization: A head start for nonnegative matrix factorization - Pattern Recognition, 2008
+func (m *NMF) initialize(X *mat.Dense, nComponents int) (W, H *mat.Dense) {
+	nSamples, nFeatures := X.Dims()
+	init := m.Init
+	if init == "" {
+		init = "random"
+		if nComponents <= nSamples && nComponents <= nFeatures {
+			init = "nndsvda"
+		}
+	}
+	avg := denseMean(X)
+	W, H = mat.NewDense(nSamples, nComponents, nil), mat.NewDense(nComponents, nFeatures, nil)
+	normFloat64 := normFloat64Func(m.RandomState)
+	if init == "random" {
+		scale := math.Sqrt(avg / float64(nComponents))
+		for _, data := range [][]float64{H.RawMatrix().Data, W.RawMatrix().Data} {
+			for i := range data {
+				data[i] = scale * math.Abs(normFloat64())
+			}
+		}
+		return
+	}
+	if init != "nndsvd" && init != "nndsvda" && init != "nndsvdar" {
+		panic(fmt.Errorf("NMF: unknown init %s", init))
+	}
+	U, S, VT := base.RandomizedSVD(X, nComponents, 10, -1, m.RandomState)
+	x, y := make([]float64, nSamples), make([]float64, nFeatures)
+	xp, xn, yp, yn := make([]float64, nSamples), make([]float64, nSamples), make([]float64, nFeatures), make([]float64, nFeatures)
+	for j := 0; j < nComponents; j++ {
+		mat.Col(x, j, U)
+		copy(y, VT.RawRowView(j))
+		if j == 0 {
+			// the leading singular vectors are non-negative up to sign
+			for i := range x {
+				W.Set(i, 0, math.Sqrt(S[0])*math.Abs(x[i]))
+			}
+			for i := range y {
+				H.Set(0, i, math.Sqrt(S[0])*math.Abs(y[i]))
+			}
+			continue
+		}
+		for i, v := range x {
+			xp[i], xn[i] = math.Max(v, 0), math.Max(-v, 0)
+		}
+		for i, v := range y {
+			yp[i], yn[i] = math.Max(v, 0), math.Max(-v, 0)
+		}
+		xpNorm, ypNorm := floats.Norm(xp, 2), floats.Norm(yp, 2)
+		xnNorm, ynNorm := floats.Norm(xn, 2), floats.Norm(yn, 2)
+		mp, mn := xpNorm*ypNorm, xnNorm*ynNorm
+		u, v, sigma, uNorm, vNorm := xp, yp, mp, xpNorm, ypNorm
+		if mp <= mn {
+			u, v, sigma, uNorm, vNorm = xn, yn, mn, xnNorm, ynNorm
+		}
+		if uNorm == 0 || vNorm == 0 {
+			continue
+		}
+		lbd := math.Sqrt(S[j] * sigma)
+		for i := range u {
+			W.Set(i, j, lbd*u[i]/uNorm)
+		}
+		for i := range v {
+			H.Set(j, i, lbd*v[i]/vNorm)
+		}
+	}
+	const eps = 1e-6
+	for _, data := range [][]float64{W.RawMatrix().Data, H.RawMatrix().Data} {
+		for i := range data {
+			if data[i] < eps {
+				switch init {
+				case "nndsvd":
+					data[i] = 0
+				case "nndsvda":
+					data[i] = avg
+				case "nndsvdar":
+					data[i] = math.Abs(avg * normFloat64() / 100)
+				}
+			}
+		}
+	}
+	return
+}
+
+// updateCoordinateDescent updates W in place for the problem ||X-W*Ht'||² with Ht fixed and returns the sum of projected gradients
+// see A. Cichocki, A. Phan: Fast local algorithms for large scale nonnegative matrix and tensor factorizations, IEICE 2009
+func updateCoordinateDescent(X, W, Ht *mat.Dense, l1, l2 float64, perm func(int) []int) (violation float64) {
+	nSamples, nComponents := W.Dims()
+	HHt := &mat.Dense{}
+	HHt.Mul(Ht.T(), Ht)
+	XHt := &mat.Dense{}
+	XHt.Mul(X, Ht)
+	for t := 0; t < nComponents; t++ {
+		HHt.Set(t, t, HHt.At(t, t)+l2)
+	}
+	if l1 != 0 {
+		data := XHt.RawMatrix().Data
+		for i := range data {
+			data[i] -= l1
+		}
+	}
+	var order []int
+	if perm != nil {
+		order = perm(nComponents)
+	} else {
+		order = make([]int, nComponents)
+		for i := range order {
+			order[i] = i
+		}
+	}
+	for _, t := range order {
+		hess := HHt.At(t, t)
+		hht := HHt.RawRowView(t)
+		for i := 0; i < nSamples; i++ {
+			w := W.RawRowView(i)
+			grad := -XHt.At(i, t) + floats.Dot(hht, w)
+			pg := grad
+			if w[t] == 0 && grad > 0 {
+				pg = 0
+			}
+			violation += math.Abs(pg)
+			if hess != 0 {
+				w[t] = math.Max(w[t]-grad/hess, 0)
+			}
+		}
+	}
+	return
+}
+
+// multiplicativeUpdateW updates W in place for the beta divergence between X and W*H with H fixed
+// see D. Lee, H. Seung: Algorithms for non-negative matrix factorization, NIPS 2001
+func multiplicativeUpdateW(X, W, H *mat.Dense, beta, l1, l2 float64) {
+	const eps = 1e-16
+	nSamples, nComponents := W.Dims()
+	numerator, denominator := &mat.Dense{}, mat.NewDense(nSamples, nComponents, nil)
+	if beta == 2 {
+		numerator.Mul(X, H.T())
+		HHt := &mat.Dense{}
+		HHt.Mul(H, H.T())
+		denominator.Mul(W, HHt)
+	} else {
+		// kullback-leibler: numerator is (X/WH)*H'
+		WH := &mat.Dense{}
+		WH.Mul(W, H)
+		XdivWH := mat.DenseCopyOf(X)
+		xr := XdivWH.RawMatrix()
+		whr := WH.RawMatrix()
+		for i := 0; i < xr.Rows; i++ {
+			xrow, whrow := xr.Data[i*xr.Stride:i*xr.Stride+xr.Cols], whr.Data[i*whr.Stride:i*whr.Stride+whr.Cols]
+			for j, v := range xrow {
+				if v != 0 {
+					xrow[j] = v / math.Max(whrow[j], eps)
+				}
+			}
+		}
+		numerator.Mul(XdivWH, H.T())
+		Hsum := make([]float64, nComponents)
+		for c := range Hsum {
+			Hsum[c] = floats.Sum(H.RawRowView(c))
+		}
+		for i := 0; i < nSamples; i++ {
+			copy(denominator.RawRowView(i), Hsum)
+		}
+	}
+	for i := 0; i < nSamples; i++ {
+		w, num, den := W.RawRowView(i), numerator.RawRowView(i), denominator.RawRowView(i)
+		for c := range w {
+			d := den[c] + l1 + l2*w[c]
+			if d == 0 {
+				d = eps
+			}
+			w[c] *= num[c] / d
+		}
+	}
+}
+
+// betaDivergence computes the beta divergence between X and W*H. if squareRoot is true, sqrt(2*divergence) is returned
+func betaDivergence(X, W, H *mat.Dense, beta float64, squareRoot bool) (res float64) {
+	const eps = 1e-16
+	WH := &mat.Dense{}
+	WH.Mul(W, H)
+	nSamples, nFeatures := X.Dims()
+	for i := 0; i < nSamples; i++ {
+		x, wh := X.RawRowView(i)[:nFeatures], WH.RawRowView(i)
+		for j, v := range x {
+			if beta == 2 {
+				res += (v - wh[j]) * (v - wh[j]) / 2
+				continue
+			}
+			// kullback-leibler
+			res += wh[j]
+			if v != 0 {
+				res += v*math.Log(v/math.Max(wh[j], eps)) - v
+			}
+		}
+	}
+	if squareRoot {
+		res = math.Sqrt(2 * math.Max(res, 0))
+	}
+	return
+}
+
+// transposed returns a dense copy of A'
+func transposed(A *mat.Dense) *mat.Dense {
+	return mat.DenseCopyOf(A.T())
+}
+
+func denseMean(X *mat.Dense) float64 {
+	r, c := X.Dims()
+	s := 0.
+	for i := 0; i < r; i++ {
+		s += floats.Sum(X.RawRowView(i)[:c])
+	}
+	return s / float64(r*c)
+}
+
+func fill(data []float64, v float64) {
+	for i := range data {
+		data[i] = v
+	}
+}
+
+// normFloat64Func returns a standard normal generator from RandomState, or the global one if it is nil
+func normFloat64Func(RandomState base.Source) func() float64 {
+	if RandomState == base.Source(nil) {
+		return rand.NormFloat64
+	}
+	if normFloat64er, ok := RandomState.(base.NormFloat64er); ok {
+		return normFloat64er.NormFloat64
+	}
+	return rand.New(RandomState).NormFloat64
+}
+
+// permFunc returns a permutation generator from RandomState, or the global one if it is nil
+func permFunc(RandomState base.Source) func(int) []int {
+	if RandomState == base.Source(nil) {
+		return rand.Perm
+	}
+	if permer, ok := RandomState.(base.Permer); ok {
+		return permer.Perm
+	}
+	return rand.New(RandomState).Perm
+}
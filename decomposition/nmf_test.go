@@ -0,0 +1,98 @@
+package decomposition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+	"github.com/RobinRCM/sklearn/pipeline"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleNMF() {
+	X := mat.NewDense(6, 2, []float64{1, 1, 2, 1, 3, 1.2, 4, 1, 5, 0.8, 6, 1})
+	for _, solver := range []string{"cd", "mu"} {
+		nmf := NewNMF(2)
+		nmf.Init = "random"
+		nmf.Solver = solver
+		nmf.RandomState = base.NewSource(0)
+		nmf.MaxIter = 1000
+		W, _ := nmf.FitTransform(X, nil)
+		Xr, _ := nmf.InverseTransform(W, nil)
+		W2, _ := nmf.Transform(X, nil)
+		fmt.Printf("%s reconstruction err: %.3f max abs error: %.3f transform matches: %t\n", solver, nmf.ReconstructionErr, maxAbsDiff(X, Xr), mat.EqualApprox(W, W2, 1e-2))
+	}
+	// Output:
+	// cd reconstruction err: 0.001 max abs error: 0.001 transform matches: true
+	// mu reconstruction err: 0.001 max abs error: 0.002 transform matches: true
+}
+
+func ExampleNMF_kullbackLeibler() {
+	// a usage matrix with two groups of users and two groups of items
+	X := mat.NewDense(6, 6, []float64{
+		5, 4, 6, 0, 1, 0,
+		4, 5, 5, 1, 0, 0,
+		6, 5, 4, 0, 0, 1,
+		0, 1, 0, 3, 4, 3,
+		1, 0, 0, 4, 3, 4,
+		0, 0, 1, 3, 3, 4,
+	})
+	for _, init := range []string{"nndsvd", "nndsvda", "nndsvdar"} {
+		nmf := NewNMF(2)
+		nmf.Init = init
+		nmf.Solver = "mu"
+		nmf.BetaLoss = "kullback-leibler"
+		nmf.MaxIter = 500
+		nmf.RandomState = base.NewSource(0)
+		nmf.AlphaW, nmf.AlphaH, nmf.L1Ratio = .001, .001, .5
+		W, _ := nmf.FitTransform(X, nil)
+		fmt.Printf("%-8s err: %.2f user topics: %v item topics: %v\n", init, nmf.ReconstructionErr, argmaxRows(W), argmaxRows(transposed(nmf.Components)))
+	}
+	// Output:
+	// nndsvd   err: 3.82 user topics: [0 0 0 1 1 1] item topics: [0 0 0 1 1 1]
+	// nndsvda  err: 3.76 user topics: [0 0 0 1 1 1] item topics: [0 0 0 1 1 1]
+	// nndsvdar err: 3.77 user topics: [0 0 0 1 1 1] item topics: [0 0 0 1 1 1]
+}
+
+func ExampleNMF_pipeline() {
+	// usage of 3 products by 6 users, spending depends on the usage profile of each user
+	X := mat.NewDense(6, 3, []float64{
+		1, 0, 2,
+		2, 0, 4,
+		3, 0, 6,
+		0, 3, 1,
+		0, 6, 2,
+		1, 9, 3,
+	})
+	Y := mat.NewDense(6, 1, []float64{10, 20, 30, 3, 6, 9})
+	nmf := NewNMF(2)
+	nmf.RandomState = base.NewSource(0)
+	pl := pipeline.NewPipeline(
+		pipeline.NamedStep{Name: "nmf", Fiter: nmf},
+		pipeline.NamedStep{Name: "regression", Fiter: linearmodel.NewLinearRegression()},
+	)
+	pl.Fit(X, Y)
+	fmt.Printf("R2>0.99 ? %t\n", pl.Score(X, Y) > .99)
+	// Output:
+	// R2>0.99 ? true
+}
+
+func maxAbsDiff(A, B mat.Matrix) float64 {
+	diff := &mat.Dense{}
+	diff.Sub(A, B)
+	return mat.Norm(diff, math.Inf(1))
+}
+
+func argmaxRows(A mat.Matrix) []int {
+	r, c := A.Dims()
+	res := make([]int, r)
+	for i := range res {
+		for j := 1; j < c; j++ {
+			if A.At(i, j) > A.At(i, res[i]) {
+				res[i] = j
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,233 @@
+package decomposition
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// TruncatedSVD is a dimensionality reduction using truncated SVD (aka LSA).
+// contrary to PCA, data is not centered so it is suitable for term-document matrices.
+// Algorithm is "randomized" (default) or "arpack" which uses Lanczos bidiagonalization with full reorthogonalization.
+// NIter is the number of power iterations of the randomized solver, NOversamples the number of additional random vectors
+// NCV is the number of Lanczos vectors for "arpack" (default max(2*NComponents+1,20))
+type TruncatedSVD struct {
+	NComponents  int
+	Algorithm    string
+	NIter        int
+	NOversamples int
+	NCV          int
+	RandomState  base.RandomState
+
+	Components                                                *mat.Dense
+	SingularValues, ExplainedVariance, ExplainedVarianceRatio []float64
+}
+
+var _ preprocessing.InverseTransformer = &TruncatedSVD{}
+
+// NewTruncatedSVD returns a *TruncatedSVD
+func NewTruncatedSVD() *TruncatedSVD {
+	return &TruncatedSVD{NComponents: 2, Algorithm: "randomized", NIter: 5, NOversamples: 10}
+}
+
+// TransformerClone ...
+func (m *TruncatedSVD) TransformerClone() base.Transformer {
+	clone := *m
+	if sourceCloner, ok := clone.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
+	return &clone
+}
+
+// Fit computes Components of X
+func (m *TruncatedSVD) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform fits the model with X and returns U*Sigma
+func (m *TruncatedSVD) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := base.ToDense(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	nComponents := m.NComponents
+	if nComponents <= 0 {
+		nComponents = 2
+	}
+	if nComponents > nFeatures {
+		panic(fmt.Errorf("TruncatedSVD: NComponents=%d must be <= NFeatures=%d", nComponents, nFeatures))
+	}
+	var U, VT *mat.Dense
+	var S []float64
+	switch m.Algorithm {
+	case "arpack":
+		U, S, VT = lanczosSVD(X, nComponents, m.NCV, m.RandomState)
+	case "", "randomized":
+		U, S, VT = base.RandomizedSVD(X, nComponents, m.NOversamples, m.NIter, m.RandomState)
+	default:
+		panic(fmt.Errorf("TruncatedSVD: unknown algorithm %s", m.Algorithm))
+	}
+	m.Components, m.SingularValues = VT, S
+
+	Xout = mat.NewDense(nSamples, nComponents, nil)
+	for i := 0; i < nSamples; i++ {
+		floats.MulTo(Xout.RawRowView(i), U.RawRowView(i), S)
+	}
+	m.ExplainedVariance = make([]float64, nComponents)
+	col := make([]float64, nSamples)
+	for c := range m.ExplainedVariance {
+		mat.Col(col, c, Xout)
+		m.ExplainedVariance[c] = popVariance(col)
+	}
+	totalVariance := 0.
+	col = make([]float64, nSamples)
+	for j := 0; j < nFeatures; j++ {
+		mat.Col(col, j, X)
+		totalVariance += popVariance(col)
+	}
+	m.ExplainedVarianceRatio = make([]float64, nComponents)
+	floats.ScaleTo(m.ExplainedVarianceRatio, 1/totalVariance, m.ExplainedVariance)
+	Yout = base.ToDense(Ymatrix)
+	return
+}
+
+// GetFeatureNamesOut returns truncatedsvd0, truncatedsvd1... one name per component. inputNames are unused
+func (m *TruncatedSVD) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("truncatedsvd", m.Components.RawMatrix().Rows)
+}
+
+// Transform projects X on Components
+func (m *TruncatedSVD) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	nSamples, _ := X.Dims()
+	nComponents, _ := m.Components.Dims()
+	Xout = mat.NewDense(nSamples, nComponents, nil)
+	Xout.Mul(X, m.Components.T())
+	Yout = base.ToDense(Y)
+	return
+}
+
+// InverseTransform put X into original space
+func (m *TruncatedSVD) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	nSamples, _ := X.Dims()
+	_, nFeatures := m.Components.Dims()
+	Xout = mat.NewDense(nSamples, nFeatures, nil)
+	Xout.Mul(X, m.Components)
+	Yout = Y
+	return
+}
+
+// popVariance returns the biased (ddof=0) variance of x
+func popVariance(x []float64) float64 {
+	_, variance := stat.MeanVariance(x, nil)
+	return variance * float64(len(x)-1) / float64(len(x))
+}
+
+// lanczosSVD computes the NComponents largest singular triplets of X using
+// Golub-Kahan-Lanczos bidiagonalization with full reorthogonalization on NCV vectors
+func lanczosSVD(X *mat.Dense, NComponents, NCV int, RandomState base.Source) (U *mat.Dense, S []float64, VT *mat.Dense) {
+	nSamples, nFeatures := X.Dims()
+	minDim := nSamples
+	if nFeatures < minDim {
+		minDim = nFeatures
+	}
+	if NCV <= 0 {
+		NCV = 2*NComponents + 1
+		if NCV < 20 {
+			NCV = 20
+		}
+	}
+	if NCV > minDim {
+		NCV = minDim
+	}
+	normFloat64 := normFloat64Func(RandomState)
+	// P (nSamples,NCV) and Q (nFeatures,NCV) bases are stored as rows for convenience
+	P, Q := make([][]float64, 0, NCV), make([][]float64, 0, NCV)
+	alphas, betas := make([]float64, 0, NCV), make([]float64, 0, NCV)
+	reorthogonalize := func(v []float64, basis [][]float64) float64 {
+		for pass := 0; pass < 2; pass++ {
+			for _, b := range basis {
+				floats.AddScaled(v, -floats.Dot(b, v), b)
+			}
+		}
+		return floats.Norm(v, 2)
+	}
+	const eps = 1e-12
+	q := make([]float64, nFeatures)
+	for i := range q {
+		q[i] = normFloat64()
+	}
+	floats.Scale(1/floats.Norm(q, 2), q)
+	p := make([]float64, nSamples)
+	qvec, pvec := mat.NewVecDense(nFeatures, nil), mat.NewVecDense(nSamples, nil)
+	for j := 0; j < NCV; j++ {
+		Q = append(Q, q)
+		// p = X q - beta p
+		pvec.MulVec(X, mat.NewVecDense(nFeatures, q))
+		pnew := append([]float64{}, pvec.RawVector().Data...)
+		if j > 0 {
+			floats.AddScaled(pnew, -betas[j-1], p)
+		}
+		alpha := reorthogonalize(pnew, P)
+		if alpha < eps {
+			Q = Q[:j]
+			break
+		}
+		floats.Scale(1/alpha, pnew)
+		p = pnew
+		P = append(P, p)
+		alphas = append(alphas, alpha)
+		if j == NCV-1 {
+			break
+		}
+		// q = X' p - alpha q
+		qvec.MulVec(X.T(), mat.NewVecDense(nSamples, p))
+		qnew := append([]float64{}, qvec.RawVector().Data...)
+		floats.AddScaled(qnew, -alpha, q)
+		beta := reorthogonalize(qnew, Q)
+		if beta < eps {
+			break
+		}
+		floats.Scale(1/beta, qnew)
+		q = qnew
+		betas = append(betas, beta)
+	}
+	k := len(alphas)
+	if k < NComponents {
+		panic(fmt.Errorf("lanczosSVD: only %d singular values could be computed", k))
+	}
+	// X Q = P B where B is upper bidiagonal
+	B := mat.NewDense(k, k, nil)
+	for j := 0; j < k; j++ {
+		B.Set(j, j, alphas[j])
+		if j+1 < k {
+			B.Set(j, j+1, betas[j])
+		}
+	}
+	var svd mat.SVD
+	if !svd.Factorize(B, mat.SVDThin) {
+		panic("lanczosSVD: svd failed")
+	}
+	Ub, Vb := &mat.Dense{}, &mat.Dense{}
+	svd.UTo(Ub)
+	svd.VTo(Vb)
+	S = svd.Values(nil)[:NComponents]
+	Pm, Qm := mat.NewDense(nSamples, k, nil), mat.NewDense(nFeatures, k, nil)
+	for j := 0; j < k; j++ {
+		Pm.SetCol(j, P[j])
+		Qm.SetCol(j, Q[j])
+	}
+	U = mat.NewDense(nSamples, NComponents, nil)
+	U.Mul(Pm, Ub.Slice(0, k, 0, NComponents))
+	V := mat.NewDense(nFeatures, NComponents, nil)
+	V.Mul(Qm, Vb.Slice(0, k, 0, NComponents))
+	VT = mat.DenseCopyOf(V.T())
+	base.SVDFlip(U, VT, true)
+	return
+}
@@ -0,0 +1,34 @@
+package decomposition
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleTruncatedSVD() {
+	// a small term-document matrix
+	X := mat.NewDense(6, 5, []float64{
+		2, 1, 0, 0, 0,
+		1, 2, 1, 0, 0,
+		0, 1, 3, 0, 1,
+		0, 0, 0, 2, 1,
+		0, 0, 1, 1, 2,
+		1, 0, 0, 0, 0,
+	})
+	for _, algorithm := range []string{"randomized", "arpack"} {
+		svd := NewTruncatedSVD()
+		svd.Algorithm = algorithm
+		svd.RandomState = base.NewSource(7)
+		Xt, _ := svd.FitTransform(X, nil)
+		Xt2, _ := svd.Transform(X, nil)
+		fmt.Printf("%-10s singular values: %.4f ratio: %.4f\n", algorithm, svd.SingularValues, svd.ExplainedVarianceRatio)
+		fmt.Printf("%-10s same transform: %t components: %.4f\n", algorithm, mat.EqualApprox(Xt, Xt2, 1e-10), mat.Row(nil, 0, svd.Components))
+	}
+	// Output:
+	// randomized singular values: [4.1753 3.0472] ratio: [0.2524 0.4533]
+	// randomized same transform: true components: [0.2165 0.4350 0.7357 0.1971 0.4287]
+	// arpack     singular values: [4.1753 3.0472] ratio: [0.2524 0.4533]
+	// arpack     same transform: true components: [0.2165 0.4350 0.7357 0.1971 0.4287]
+}
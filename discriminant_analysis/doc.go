@@ -0,0 +1,2 @@
+// Package discriminantanalysis includes Linear and Quadratic Discriminant Analysis classifiers.
+package discriminantanalysis
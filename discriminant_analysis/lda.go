@@ -0,0 +1,616 @@
+package discriminantanalysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/metrics"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ base.Predicter = &LinearDiscriminantAnalysis{}
+var _ base.Transformer = &LinearDiscriminantAnalysis{}
+
+// LinearDiscriminantAnalysis is a classifier with a linear decision boundary, generated by fitting class conditional
+// densities to the data and using Bayes' rule. all classes share the same covariance matrix.
+// Solver is "svd" (default, no covariance computed, recommended for data with a large number of features),
+// "lsqr" (least squares, no Transform) or "eigen" (eigenvalue decomposition).
+// Shrinkage is nil, "auto" (Ledoit-Wolf lemma) or a float64 in [0,1]. it is not supported by "svd" solver.
+// Priors are the class prior probabilities, inferred from data if nil.
+// NComponents is the number of discriminant axes kept by Transform (<= min(nClasses-1,nFeatures), 0 for all).
+// Tol is the threshold used by "svd" solver to estimate the rank of X
+type LinearDiscriminantAnalysis struct {
+	Solver          string
+	Shrinkage       interface{}
+	Priors          []float64
+	NComponents     int
+	StoreCovariance bool
+	Tol             float64
+
+	Classes, ClassPrior, XBar, Intercept, ExplainedVarianceRatio []float64
+	Means, Covariance, Scalings, Coef                            *mat.Dense
+	maxComponents                                                int
+}
+
+// NewLinearDiscriminantAnalysis returns a *LinearDiscriminantAnalysis with "svd" solver
+func NewLinearDiscriminantAnalysis() *LinearDiscriminantAnalysis {
+	return &LinearDiscriminantAnalysis{Solver: "svd", Tol: 1e-4}
+}
+
+// IsClassifier returns true for LinearDiscriminantAnalysis
+func (m *LinearDiscriminantAnalysis) IsClassifier() bool { return true }
+
+// GetNOutputs returns 1
+func (m *LinearDiscriminantAnalysis) GetNOutputs() int { return 1 }
+
+// PredicterClone ...
+func (m *LinearDiscriminantAnalysis) PredicterClone() base.Predicter {
+	clone := *m
+	return &clone
+}
+
+// TransformerClone ...
+func (m *LinearDiscriminantAnalysis) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit fits the model according to X and class labels in Y's first column
+func (m *LinearDiscriminantAnalysis) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := base.ToDense(Xmatrix)
+	_, nFeatures := X.Dims()
+	var y []int
+	m.Classes, y = encodeClasses(Ymatrix)
+	nClasses := len(m.Classes)
+	if nClasses < 2 {
+		panic(fmt.Errorf("LinearDiscriminantAnalysis: the number of classes has to be greater than one; got %d class", nClasses))
+	}
+	m.ClassPrior = checkPriors(m.Priors, y, nClasses)
+	m.maxComponents = minInt(nClasses-1, nFeatures)
+	if m.NComponents > 0 {
+		if m.NComponents > m.maxComponents {
+			panic(fmt.Errorf("LinearDiscriminantAnalysis: NComponents=%d cannot be larger than min(nFeatures, nClasses - 1)=%d", m.NComponents, m.maxComponents))
+		}
+		m.maxComponents = m.NComponents
+	}
+	m.Means = classMeans(X, y, nClasses)
+	m.Covariance = nil
+	switch m.Solver {
+	case "", "svd":
+		if m.Shrinkage != nil {
+			panic("LinearDiscriminantAnalysis: shrinkage not supported with svd solver")
+		}
+		m.solveSVD(X, y)
+	case "lsqr":
+		m.solveLsqr(X, y)
+	case "eigen":
+		m.solveEigen(X, y)
+	default:
+		panic(fmt.Errorf("LinearDiscriminantAnalysis: unknown solver %s", m.Solver))
+	}
+	if nClasses == 2 {
+		// keep only the decision function of the second class
+		coef := mat.NewDense(1, nFeatures, nil)
+		floats.SubTo(coef.RawRowView(0), m.Coef.RawRowView(1), m.Coef.RawRowView(0))
+		m.Coef = coef
+		m.Intercept = []float64{m.Intercept[1] - m.Intercept[0]}
+	}
+	return m
+}
+
+// solveLsqr solves the least squares problem Covariance*Coef' = Means'
+func (m *LinearDiscriminantAnalysis) solveLsqr(X *mat.Dense, y []int) {
+	m.Covariance = classCov(X, y, m.ClassPrior, m.Shrinkage)
+	m.Coef = &mat.Dense{}
+	if err := m.Coef.Solve(m.Covariance, m.Means.T()); err != nil {
+		panic(fmt.Errorf("LinearDiscriminantAnalysis: %s", err))
+	}
+	m.Coef = mat.DenseCopyOf(m.Coef.T())
+	m.Intercept = m.linearIntercept()
+}
+
+// solveEigen finds the directions maximizing the ratio of between class scatter to within class scatter
+func (m *LinearDiscriminantAnalysis) solveEigen(X *mat.Dense, y []int) {
+	_, nFeatures := X.Dims()
+	m.Covariance = classCov(X, y, m.ClassPrior, m.Shrinkage)
+	Sw := m.Covariance
+	St := covariance(X, m.Shrinkage)
+	Sb := &mat.Dense{}
+	Sb.Sub(St, Sw)
+	evals, evecs := generalizedEigenSym(Sb, Sw)
+	order := make([]int, nFeatures)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return evals[order[i]] > evals[order[j]] })
+	sum := floats.Sum(evals)
+	m.ExplainedVarianceRatio = make([]float64, m.maxComponents)
+	m.Scalings = mat.NewDense(nFeatures, nFeatures, nil)
+	col := make([]float64, nFeatures)
+	for c, o := range order {
+		if c < m.maxComponents {
+			m.ExplainedVarianceRatio[c] = evals[o] / sum
+		}
+		mat.Col(col, o, evecs)
+		m.Scalings.SetCol(c, col)
+	}
+	tmp := &mat.Dense{}
+	tmp.Mul(m.Means, m.Scalings)
+	m.Coef = &mat.Dense{}
+	m.Coef.Mul(tmp, m.Scalings.T())
+	m.Intercept = m.linearIntercept()
+}
+
+// solveSVD computes Scalings from the SVD of the within class scaled data and the one of the scaled class centers
+func (m *LinearDiscriminantAnalysis) solveSVD(X *mat.Dense, y []int) {
+	nSamples, nFeatures := X.Dims()
+	nClasses := len(m.Classes)
+	if m.StoreCovariance {
+		m.Covariance = classCov(X, y, m.ClassPrior, nil)
+	}
+	m.XBar = make([]float64, nFeatures)
+	for k, p := range m.ClassPrior {
+		floats.AddScaled(m.XBar, p, m.Means.RawRowView(k))
+	}
+	Xc := mat.NewDense(nSamples, nFeatures, nil)
+	for i := 0; i < nSamples; i++ {
+		floats.SubTo(Xc.RawRowView(i), X.RawRowView(i), m.Means.RawRowView(y[i]))
+	}
+	std := make([]float64, nFeatures)
+	for i := 0; i < nSamples; i++ {
+		for j, v := range Xc.RawRowView(i) {
+			std[j] += v * v
+		}
+	}
+	// Xc columns have a null mean within each class
+	for j := range std {
+		std[j] = math.Sqrt(std[j] / float64(nSamples))
+		if std[j] == 0 {
+			std[j] = 1
+		}
+	}
+	fac := math.Sqrt(1 / float64(nSamples-nClasses))
+	for i := 0; i < nSamples; i++ {
+		row := Xc.RawRowView(i)
+		floats.Div(row, std)
+		floats.Scale(fac, row)
+	}
+	var svd mat.SVD
+	if !svd.Factorize(Xc, mat.SVDThin) {
+		panic("LinearDiscriminantAnalysis: svd failed")
+	}
+	V := &mat.Dense{}
+	svd.VTo(V)
+	S := svd.Values(nil)
+	rank := 0
+	for _, s := range S {
+		if s > m.Tol {
+			rank++
+		}
+	}
+	// scalings (nFeatures,rank) = (Vt[:rank]/std).T / S[:rank]
+	scalings := mat.NewDense(nFeatures, rank, nil)
+	for j := 0; j < nFeatures; j++ {
+		for r := 0; r < rank; r++ {
+			scalings.Set(j, r, V.At(j, r)/std[j]/S[r])
+		}
+	}
+	// between class scatter
+	fac = 1 / float64(nClasses-1)
+	centers := mat.NewDense(nClasses, nFeatures, nil)
+	for k := 0; k < nClasses; k++ {
+		floats.SubTo(centers.RawRowView(k), m.Means.RawRowView(k), m.XBar)
+		floats.Scale(math.Sqrt(float64(nSamples)*m.ClassPrior[k]*fac), centers.RawRowView(k))
+	}
+	Xb := &mat.Dense{}
+	Xb.Mul(centers, scalings)
+	var svd2 mat.SVD
+	if !svd2.Factorize(Xb, mat.SVDThin) {
+		panic("LinearDiscriminantAnalysis: svd failed")
+	}
+	V2 := &mat.Dense{}
+	svd2.VTo(V2)
+	S2 := svd2.Values(nil)
+	sumS2 := 0.
+	for _, s := range S2 {
+		sumS2 += s * s
+	}
+	m.ExplainedVarianceRatio = make([]float64, minInt(m.maxComponents, len(S2)))
+	for c := range m.ExplainedVarianceRatio {
+		m.ExplainedVarianceRatio[c] = S2[c] * S2[c] / sumS2
+	}
+	rank = 0
+	for _, s := range S2 {
+		if s > m.Tol*S2[0] {
+			rank++
+		}
+	}
+	m.Scalings = &mat.Dense{}
+	m.Scalings.Mul(scalings, V2.Slice(0, V2.RawMatrix().Rows, 0, rank))
+
+	// coef = (means - xbar) @ scalings; intercept = -0.5*sum(coef**2, axis=1) + log(priors)
+	coef := &mat.Dense{}
+	for k := 0; k < nClasses; k++ {
+		floats.SubTo(centers.RawRowView(k), m.Means.RawRowView(k), m.XBar)
+	}
+	coef.Mul(centers, m.Scalings)
+	m.Intercept = make([]float64, nClasses)
+	for k := range m.Intercept {
+		row := coef.RawRowView(k)
+		m.Intercept[k] = -.5*floats.Dot(row, row) + math.Log(m.ClassPrior[k])
+	}
+	m.Coef = &mat.Dense{}
+	m.Coef.Mul(coef, m.Scalings.T())
+	for k := range m.Intercept {
+		m.Intercept[k] -= floats.Dot(m.XBar, m.Coef.RawRowView(k))
+	}
+}
+
+// linearIntercept returns -0.5*diag(Means*Coef') + log(ClassPrior)
+func (m *LinearDiscriminantAnalysis) linearIntercept() []float64 {
+	nClasses, _ := m.Means.Dims()
+	intercept := make([]float64, nClasses)
+	for k := range intercept {
+		intercept[k] = -.5*floats.Dot(m.Means.RawRowView(k), m.Coef.RawRowView(k)) + math.Log(m.ClassPrior[k])
+	}
+	return intercept
+}
+
+// DecisionFunction returns X*Coef'+Intercept. for binary problems, it has a single column, positive for the second class
+func (m *LinearDiscriminantAnalysis) DecisionFunction(X mat.Matrix) *mat.Dense {
+	nSamples, _ := X.Dims()
+	nOut, _ := m.Coef.Dims()
+	scores := mat.NewDense(nSamples, nOut, nil)
+	scores.Mul(X, m.Coef.T())
+	for i := 0; i < nSamples; i++ {
+		floats.Add(scores.RawRowView(i), m.Intercept)
+	}
+	return scores
+}
+
+// Predict returns the class labels of X
+func (m *LinearDiscriminantAnalysis) Predict(X mat.Matrix, Ymutable mat.Mutable) *mat.Dense {
+	return predictFromScores(m.DecisionFunction(X), m.Classes, Ymutable)
+}
+
+// PredictProba returns the class probabilities of X
+func (m *LinearDiscriminantAnalysis) PredictProba(X mat.Matrix) *mat.Dense {
+	scores := m.DecisionFunction(X)
+	nSamples, nOut := scores.Dims()
+	if nOut > 1 {
+		softmax(scores)
+		return scores
+	}
+	proba := mat.NewDense(nSamples, 2, nil)
+	for i := 0; i < nSamples; i++ {
+		p := 1 / (1 + math.Exp(-scores.At(i, 0)))
+		proba.Set(i, 0, 1-p)
+		proba.Set(i, 1, p)
+	}
+	return proba
+}
+
+// PredictLogProba returns the log of class probabilities of X
+func (m *LinearDiscriminantAnalysis) PredictLogProba(X mat.Matrix) *mat.Dense {
+	proba := m.PredictProba(X)
+	proba.Apply(func(_, _ int, v float64) float64 { return math.Log(v) }, proba)
+	return proba
+}
+
+// Score returns the mean accuracy on X,Y
+func (m *LinearDiscriminantAnalysis) Score(X, Y mat.Matrix) float64 {
+	Ypred := m.Predict(X, nil)
+	return metrics.AccuracyScore(Y, Ypred, true, nil)
+}
+
+// Transform projects X on the discriminant axes to maximize class separation
+func (m *LinearDiscriminantAnalysis) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, _ := X.Dims()
+	switch m.Solver {
+	case "lsqr":
+		panic("LinearDiscriminantAnalysis: Transform not implemented for lsqr solver (use svd or eigen)")
+	case "", "svd":
+		for i := 0; i < nSamples; i++ {
+			floats.Sub(X.RawRowView(i), m.XBar)
+		}
+	}
+	_, nScalings := m.Scalings.Dims()
+	nComponents := minInt(m.maxComponents, nScalings)
+	Xout = mat.NewDense(nSamples, nComponents, nil)
+	Xout.Mul(X, m.Scalings.Slice(0, m.Scalings.RawMatrix().Rows, 0, nComponents))
+	return Xout, base.ToDense(Ymatrix)
+}
+
+// FitTransform fit to data, then transform it
+func (m *LinearDiscriminantAnalysis) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns lineardiscriminantanalysis0, lineardiscriminantanalysis1... one name per component. inputNames are unused
+func (m *LinearDiscriminantAnalysis) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("lineardiscriminantanalysis", minInt(m.maxComponents, m.Scalings.RawMatrix().Cols))
+}
+
+// encodeClasses returns the sorted distinct values of Y's first column and the class index of each sample
+func encodeClasses(Y mat.Matrix) (classes []float64, y []int) {
+	nSamples, _ := Y.Dims()
+	index := map[float64]int{}
+	for i := 0; i < nSamples; i++ {
+		index[Y.At(i, 0)] = 0
+	}
+	for cl := range index {
+		classes = append(classes, cl)
+	}
+	sort.Float64s(classes)
+	for k, cl := range classes {
+		index[cl] = k
+	}
+	y = make([]int, nSamples)
+	for i := range y {
+		y[i] = index[Y.At(i, 0)]
+	}
+	return
+}
+
+// checkPriors returns a copy of priors or class frequencies if priors is nil
+func checkPriors(priors []float64, y []int, nClasses int) []float64 {
+	res := make([]float64, nClasses)
+	if priors == nil {
+		for _, k := range y {
+			res[k]++
+		}
+		floats.Scale(1/float64(len(y)), res)
+		return res
+	}
+	if len(priors) != nClasses {
+		panic("Number of priors must match number of classes.")
+	}
+	if floats.Min(priors) < 0 {
+		panic("Priors must be non-negative.")
+	}
+	copy(res, priors)
+	if s := floats.Sum(res); math.Abs(s-1) > 1e-5 {
+		// renormalize like sklearn does with a warning
+		floats.Scale(1/s, res)
+	}
+	return res
+}
+
+// classMeans returns the mean of each class (nClasses,nFeatures)
+func classMeans(X *mat.Dense, y []int, nClasses int) *mat.Dense {
+	_, nFeatures := X.Dims()
+	means := mat.NewDense(nClasses, nFeatures, nil)
+	counts := make([]float64, nClasses)
+	for i, k := range y {
+		floats.Add(means.RawRowView(k), X.RawRowView(i))
+		counts[k]++
+	}
+	for k, cnt := range counts {
+		floats.Scale(1/cnt, means.RawRowView(k))
+	}
+	return means
+}
+
+// classCov returns the within class covariance: the weighted sum of class covariances
+func classCov(X *mat.Dense, y []int, priors []float64, shrinkage interface{}) *mat.Dense {
+	_, nFeatures := X.Dims()
+	cov := mat.NewDense(nFeatures, nFeatures, nil)
+	for k, p := range priors {
+		var rows []int
+		for i, yi := range y {
+			if yi == k {
+				rows = append(rows, i)
+			}
+		}
+		Xk := mat.NewDense(len(rows), nFeatures, nil)
+		for i, row := range rows {
+			copy(Xk.RawRowView(i), X.RawRowView(row))
+		}
+		covk := covariance(Xk, shrinkage)
+		covk.Scale(p, covk)
+		cov.Add(cov, covk)
+	}
+	return cov
+}
+
+// covariance returns the (possibly shrunk) maximum likelihood covariance of X.
+// shrinkage is nil, "auto" for Ledoit-Wolf or a float64 in [0,1]
+func covariance(X *mat.Dense, shrinkage interface{}) *mat.Dense {
+	switch v := shrinkage.(type) {
+	case nil:
+		return empiricalCovariance(X)
+	case float64:
+		if v < 0 || v > 1 {
+			panic("shrinkage parameter must be between 0 and 1")
+		}
+		return shrunkCovariance(empiricalCovariance(X), v)
+	case string:
+		if v != "auto" {
+			panic(fmt.Errorf("unknown shrinkage %s", v))
+		}
+		// standardize features, compute Ledoit-Wolf covariance then rescale it
+		nSamples, nFeatures := X.Dims()
+		Xs := mat.DenseCopyOf(X)
+		col := make([]float64, nSamples)
+		scale := make([]float64, nFeatures)
+		for j := 0; j < nFeatures; j++ {
+			mat.Col(col, j, Xs)
+			mean := floats.Sum(col) / float64(nSamples)
+			floats.AddConst(-mean, col)
+			scale[j] = floats.Norm(col, 2) / math.Sqrt(float64(nSamples))
+			if scale[j] == 0 {
+				scale[j] = 1
+			}
+			floats.Scale(1/scale[j], col)
+			Xs.SetCol(j, col)
+		}
+		cov, _ := LedoitWolf(Xs)
+		for i := 0; i < nFeatures; i++ {
+			for j := 0; j < nFeatures; j++ {
+				cov.Set(i, j, cov.At(i, j)*scale[i]*scale[j])
+			}
+		}
+		return cov
+	}
+	panic(fmt.Errorf("unknown shrinkage %#v", shrinkage))
+}
+
+// empiricalCovariance returns the maximum likelihood covariance of X (normalized by nSamples)
+func empiricalCovariance(X *mat.Dense) *mat.Dense {
+	nSamples, nFeatures := X.Dims()
+	mean := make([]float64, nFeatures)
+	for i := 0; i < nSamples; i++ {
+		floats.Add(mean, X.RawRowView(i))
+	}
+	floats.Scale(1/float64(nSamples), mean)
+	Xc := mat.NewDense(nSamples, nFeatures, nil)
+	for i := 0; i < nSamples; i++ {
+		floats.SubTo(Xc.RawRowView(i), X.RawRowView(i), mean)
+	}
+	cov := &mat.Dense{}
+	cov.Mul(Xc.T(), Xc)
+	cov.Scale(1/float64(nSamples), cov)
+	return cov
+}
+
+// shrunkCovariance returns (1-shrinkage)*cov + shrinkage*trace(cov)/nFeatures*I
+func shrunkCovariance(cov *mat.Dense, shrinkage float64) *mat.Dense {
+	nFeatures, _ := cov.Dims()
+	mu := mat.Trace(cov) / float64(nFeatures)
+	res := &mat.Dense{}
+	res.Scale(1-shrinkage, cov)
+	for j := 0; j < nFeatures; j++ {
+		res.Set(j, j, res.At(j, j)+shrinkage*mu)
+	}
+	return res
+}
+
+// LedoitWolf returns the Ledoit-Wolf shrunk covariance of X and the shrinkage coefficient
+// see O. Ledoit and M. Wolf, A Well-Conditioned Estimator for Large-Dimensional Covariance Matrices,
+// Journal of Multivariate Analysis, Volume 88, Issue 2, February 2004, pages 365-411.
+func LedoitWolf(X *mat.Dense) (cov *mat.Dense, shrinkage float64) {
+	nSamples, nFeatures := X.Dims()
+	empCov := empiricalCovariance(X)
+	if nFeatures == 1 {
+		return empCov, 0
+	}
+	mean := make([]float64, nFeatures)
+	for i := 0; i < nSamples; i++ {
+		floats.Add(mean, X.RawRowView(i))
+	}
+	floats.Scale(1/float64(nSamples), mean)
+	Xc := mat.NewDense(nSamples, nFeatures, nil)
+	X2 := mat.NewDense(nSamples, nFeatures, nil)
+	for i := 0; i < nSamples; i++ {
+		floats.SubTo(Xc.RawRowView(i), X.RawRowView(i), mean)
+		floats.MulTo(X2.RawRowView(i), Xc.RawRowView(i), Xc.RawRowView(i))
+	}
+	n := float64(nSamples)
+	empCovTrace := 0.
+	for i := 0; i < nSamples; i++ {
+		empCovTrace += floats.Sum(X2.RawRowView(i)) / n
+	}
+	mu := empCovTrace / float64(nFeatures)
+	tmp := &mat.Dense{}
+	tmp.Mul(X2.T(), X2)
+	betaSum := mat.Sum(tmp)
+	tmp.Mul(Xc.T(), Xc)
+	tmp.MulElem(tmp, tmp)
+	deltaSum := mat.Sum(tmp) / (n * n)
+	beta := 1 / (float64(nFeatures) * n) * (betaSum/n - deltaSum)
+	delta := (deltaSum - 2*mu*empCovTrace + float64(nFeatures)*mu*mu) / float64(nFeatures)
+	beta = math.Min(beta, delta)
+	if beta != 0 {
+		shrinkage = beta / delta
+	}
+	return shrunkCovariance(empCov, shrinkage), shrinkage
+}
+
+// generalizedEigenSym solves A x = lambda B x for symmetric A and symmetric positive definite B.
+// eigenvectors are normalized so that x' B x = 1
+func generalizedEigenSym(A, B *mat.Dense) (values []float64, vectors *mat.Dense) {
+	n, _ := A.Dims()
+	Bsym := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			Bsym.SetSym(i, j, (B.At(i, j)+B.At(j, i))/2)
+		}
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(Bsym) {
+		panic("within class covariance is not positive definite, use shrinkage")
+	}
+	L := &mat.TriDense{}
+	chol.LTo(L)
+	// C = L^-1 A L^-T
+	Linv := &mat.TriDense{}
+	if err := Linv.InverseTri(L); err != nil {
+		panic(err)
+	}
+	tmp, C := &mat.Dense{}, &mat.Dense{}
+	tmp.Mul(Linv, A)
+	C.Mul(tmp, Linv.T())
+	Csym := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			Csym.SetSym(i, j, (C.At(i, j)+C.At(j, i))/2)
+		}
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(Csym, true) {
+		panic("eigen decomposition failed")
+	}
+	values = eig.Values(nil)
+	Y := &mat.Dense{}
+	eig.VectorsTo(Y)
+	vectors = &mat.Dense{}
+	vectors.Mul(Linv.T(), Y)
+	return
+}
+
+// predictFromScores returns the classes with the highest score. a single column of scores is a binary decision function
+func predictFromScores(scores *mat.Dense, classes []float64, Ymutable mat.Mutable) *mat.Dense {
+	nSamples, nOut := scores.Dims()
+	Ypred := base.ToDense(Ymutable)
+	if Ymutable == mat.Mutable(nil) {
+		Ypred = mat.NewDense(nSamples, 1, nil)
+	}
+	for i := 0; i < nSamples; i++ {
+		row := scores.RawRowView(i)
+		k := 0
+		if nOut == 1 {
+			if row[0] > 0 {
+				k = 1
+			}
+		} else {
+			k = floats.MaxIdx(row)
+		}
+		Ypred.Set(i, 0, classes[k])
+	}
+	return base.FromDense(Ymutable, Ypred)
+}
+
+// softmax replaces each row of scores with its softmax
+func softmax(scores *mat.Dense) {
+	nSamples, _ := scores.Dims()
+	for i := 0; i < nSamples; i++ {
+		row := scores.RawRowView(i)
+		floats.AddConst(-floats.LogSumExp(row), row)
+		for j, v := range row {
+			row[j] = math.Exp(v)
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,61 @@
+package discriminantanalysis
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/datasets"
+	naivebayes "github.com/RobinRCM/sklearn/naive_bayes"
+	"github.com/RobinRCM/sklearn/pipeline"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleLinearDiscriminantAnalysis() {
+	X := mat.NewDense(6, 2, []float64{-1, -1, -2, -1, -3, -2, 1, 1, 2, 1, 3, 2})
+	Y := mat.NewDense(6, 1, []float64{1, 1, 1, 2, 2, 2})
+	clf := NewLinearDiscriminantAnalysis()
+	clf.Fit(X, Y)
+	fmt.Println(clf.Predict(mat.NewDense(1, 2, []float64{-0.8, -1}), nil).RawMatrix().Data)
+	fmt.Printf("proba: %.4f\n", clf.PredictProba(mat.NewDense(1, 2, []float64{-0.8, -1})).RawRowView(0))
+
+	ds := datasets.LoadIris()
+	for _, solver := range []string{"svd", "lsqr", "eigen"} {
+		for _, shrinkage := range []interface{}{nil, "auto", .2} {
+			if solver == "svd" && shrinkage != nil {
+				continue
+			}
+			lda := NewLinearDiscriminantAnalysis()
+			lda.Solver = solver
+			lda.Shrinkage = shrinkage
+			lda.Fit(ds.X, ds.Y)
+			fmt.Printf("%-5s shrinkage %-4v accuracy: %.3f", solver, shrinkage, lda.Score(ds.X, ds.Y))
+			if solver != "lsqr" {
+				Xt, _ := lda.Transform(ds.X, nil)
+				_, nComponents := Xt.Dims()
+				fmt.Printf(" components: %d explained variance ratio: %.4f", nComponents, lda.ExplainedVarianceRatio)
+			}
+			fmt.Println()
+		}
+	}
+	// Output:
+	// [1]
+	// proba: [0.9997 0.0003]
+	// svd   shrinkage <nil> accuracy: 0.980 components: 2 explained variance ratio: [0.9915 0.0085]
+	// lsqr  shrinkage <nil> accuracy: 0.980
+	// lsqr  shrinkage auto accuracy: 0.980
+	// lsqr  shrinkage 0.2  accuracy: 0.980
+	// eigen shrinkage <nil> accuracy: 0.980 components: 2 explained variance ratio: [0.9915 0.0085]
+	// eigen shrinkage auto accuracy: 0.980 components: 2 explained variance ratio: [0.9788 0.0175]
+	// eigen shrinkage 0.2  accuracy: 0.980 components: 2 explained variance ratio: [0.7565 0.1328]
+}
+
+func ExampleLinearDiscriminantAnalysis_pipeline() {
+	// LDA as a supervised dimensionality reduction step
+	ds := datasets.LoadIris()
+	lda := NewLinearDiscriminantAnalysis()
+	lda.NComponents = 1
+	pl := pipeline.MakePipeline(lda, naivebayes.NewGaussianNB(nil, 1e-9))
+	pl.Fit(ds.X, ds.Y)
+	fmt.Printf("accuracy: %.3f\n", pl.Score(ds.X, ds.Y))
+	// Output:
+	// accuracy: 0.987
+}
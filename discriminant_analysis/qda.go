@@ -0,0 +1,189 @@
+package discriminantanalysis
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/metrics"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ base.Predicter = &QuadraticDiscriminantAnalysis{}
+
+// QuadraticDiscriminantAnalysis is a classifier with a quadratic decision boundary, generated by fitting class
+// conditional gaussian densities to the data and using Bayes' rule. each class has its own covariance matrix.
+// RegParam regularizes per-class covariance estimates: S2 = (1-RegParam)*S2 + RegParam where S2 are the eigenvalues
+// of the class covariance.
+// Priors are the class prior probabilities, inferred from data if nil.
+// Tol is the threshold on the singular values of the centered samples of a class used to estimate its rank: a warning
+// is logged when a class has collinear variables, as its covariance is then singular unless RegParam>0.
+// Rotations and Scalings hold, for each class, the eigenvectors (nFeatures,rank) and eigenvalues of its covariance
+type QuadraticDiscriminantAnalysis struct {
+	Priors          []float64
+	RegParam        float64
+	StoreCovariance bool
+	Tol             float64
+
+	Classes, ClassPrior []float64
+	Means               *mat.Dense
+	Covariance          []*mat.Dense
+	Rotations           []*mat.Dense
+	Scalings            [][]float64
+}
+
+// NewQuadraticDiscriminantAnalysis returns a *QuadraticDiscriminantAnalysis
+func NewQuadraticDiscriminantAnalysis() *QuadraticDiscriminantAnalysis {
+	return &QuadraticDiscriminantAnalysis{Tol: 1e-4}
+}
+
+// IsClassifier returns true for QuadraticDiscriminantAnalysis
+func (m *QuadraticDiscriminantAnalysis) IsClassifier() bool { return true }
+
+// GetNOutputs returns 1
+func (m *QuadraticDiscriminantAnalysis) GetNOutputs() int { return 1 }
+
+// PredicterClone ...
+func (m *QuadraticDiscriminantAnalysis) PredicterClone() base.Predicter {
+	clone := *m
+	return &clone
+}
+
+// Fit fits the model according to X and class labels in Y's first column
+func (m *QuadraticDiscriminantAnalysis) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := base.ToDense(Xmatrix)
+	_, nFeatures := X.Dims()
+	var y []int
+	m.Classes, y = encodeClasses(Ymatrix)
+	nClasses := len(m.Classes)
+	if nClasses < 2 {
+		panic(fmt.Errorf("QuadraticDiscriminantAnalysis: the number of classes has to be greater than one; got %d class", nClasses))
+	}
+	m.ClassPrior = checkPriors(m.Priors, y, nClasses)
+	m.Means = classMeans(X, y, nClasses)
+	m.Covariance, m.Rotations, m.Scalings = nil, make([]*mat.Dense, nClasses), make([][]float64, nClasses)
+	for k := 0; k < nClasses; k++ {
+		var rows []int
+		for i, yi := range y {
+			if yi == k {
+				rows = append(rows, i)
+			}
+		}
+		if len(rows) == 1 {
+			panic(fmt.Errorf("QuadraticDiscriminantAnalysis: class %g has a single sample, covariance is ill defined", m.Classes[k]))
+		}
+		Xk := mat.NewDense(len(rows), nFeatures, nil)
+		for i, row := range rows {
+			floats.SubTo(Xk.RawRowView(i), X.RawRowView(row), m.Means.RawRowView(k))
+		}
+		var svd mat.SVD
+		if !svd.Factorize(Xk, mat.SVDThin) {
+			panic("QuadraticDiscriminantAnalysis: svd failed")
+		}
+		V := &mat.Dense{}
+		svd.VTo(V)
+		S := svd.Values(nil)
+		rank := 0
+		for _, s := range S {
+			if s > m.Tol {
+				rank++
+			}
+		}
+		if rank < nFeatures {
+			log.Printf("QuadraticDiscriminantAnalysis: variables are collinear in class %g", m.Classes[k])
+		}
+		S2 := make([]float64, len(S))
+		for i, s := range S {
+			S2[i] = (1-m.RegParam)*s*s/float64(len(rows)-1) + m.RegParam
+		}
+		m.Scalings[k] = S2
+		m.Rotations[k] = V
+		if m.StoreCovariance {
+			scaled := mat.DenseCopyOf(V)
+			for j := 0; j < nFeatures; j++ {
+				floats.Mul(scaled.RawRowView(j), S2)
+			}
+			cov := &mat.Dense{}
+			cov.Mul(scaled, V.T())
+			m.Covariance = append(m.Covariance, cov)
+		}
+	}
+	return m
+}
+
+// DecisionFunction returns the log posterior of each class, up to a constant: -0.5*(log(det(cov))+mahalanobis²)+log(prior).
+// for binary problems, it returns a single column: the log likelihood ratio of the second class
+func (m *QuadraticDiscriminantAnalysis) DecisionFunction(X mat.Matrix) *mat.Dense {
+	scores := m.jointLogLikelihood(X)
+	nSamples, nClasses := scores.Dims()
+	if nClasses == 2 {
+		dec := mat.NewDense(nSamples, 1, nil)
+		for i := 0; i < nSamples; i++ {
+			dec.Set(i, 0, scores.At(i, 1)-scores.At(i, 0))
+		}
+		return dec
+	}
+	return scores
+}
+
+func (m *QuadraticDiscriminantAnalysis) jointLogLikelihood(Xmatrix mat.Matrix) *mat.Dense {
+	X := base.ToDense(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	nClasses := len(m.Classes)
+	scores := mat.NewDense(nSamples, nClasses, nil)
+	Xm := mat.NewDense(nSamples, nFeatures, nil)
+	X2 := &mat.Dense{}
+	for k := 0; k < nClasses; k++ {
+		for i := 0; i < nSamples; i++ {
+			floats.SubTo(Xm.RawRowView(i), X.RawRowView(i), m.Means.RawRowView(k))
+		}
+		S := m.Scalings[k]
+		R := mat.DenseCopyOf(m.Rotations[k])
+		r, _ := R.Dims()
+		u := 0.
+		for _, s := range S {
+			u += math.Log(s)
+		}
+		for j := 0; j < r; j++ {
+			row := R.RawRowView(j)
+			for c := range row {
+				row[c] /= math.Sqrt(S[c])
+			}
+		}
+		X2.Mul(Xm, R)
+		for i := 0; i < nSamples; i++ {
+			row := X2.RawRowView(i)
+			scores.Set(i, k, -.5*(u+floats.Dot(row, row))+math.Log(m.ClassPrior[k]))
+		}
+		X2.Reset()
+	}
+	return scores
+}
+
+// Predict returns the class labels of X
+func (m *QuadraticDiscriminantAnalysis) Predict(X mat.Matrix, Ymutable mat.Mutable) *mat.Dense {
+	return predictFromScores(m.jointLogLikelihood(X), m.Classes, Ymutable)
+}
+
+// PredictProba returns the class probabilities of X
+func (m *QuadraticDiscriminantAnalysis) PredictProba(X mat.Matrix) *mat.Dense {
+	scores := m.jointLogLikelihood(X)
+	softmax(scores)
+	return scores
+}
+
+// PredictLogProba returns the log of class probabilities of X
+func (m *QuadraticDiscriminantAnalysis) PredictLogProba(X mat.Matrix) *mat.Dense {
+	proba := m.PredictProba(X)
+	proba.Apply(func(_, _ int, v float64) float64 { return math.Log(v) }, proba)
+	return proba
+}
+
+// Score returns the mean accuracy on X,Y
+func (m *QuadraticDiscriminantAnalysis) Score(X, Y mat.Matrix) float64 {
+	Ypred := m.Predict(X, nil)
+	return metrics.AccuracyScore(Y, Ypred, true, nil)
+}
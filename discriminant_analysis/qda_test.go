@@ -0,0 +1,50 @@
+package discriminantanalysis
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/RobinRCM/sklearn/datasets"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleQuadraticDiscriminantAnalysis() {
+	X := mat.NewDense(6, 2, []float64{-1, -1, -2, -1, -3, -2, 1, 1, 2, 1, 3, 2})
+	Y := mat.NewDense(6, 1, []float64{1, 1, 1, 2, 2, 2})
+	clf := NewQuadraticDiscriminantAnalysis()
+	clf.Fit(X, Y)
+	fmt.Println(clf.Predict(mat.NewDense(1, 2, []float64{-0.8, -1}), nil).RawMatrix().Data)
+
+	ds := datasets.LoadIris()
+	for _, regParam := range []float64{0, .1, .5} {
+		qda := NewQuadraticDiscriminantAnalysis()
+		qda.RegParam = regParam
+		qda.StoreCovariance = true
+		qda.Fit(ds.X, ds.Y)
+		proba := qda.PredictProba(ds.X)
+		fmt.Printf("reg %.1f accuracy: %.3f proba sum: %.3f cov[0][0,0]: %.4f\n", regParam, qda.Score(ds.X, ds.Y), floats.Sum(proba.RawRowView(0)), qda.Covariance[0].At(0, 0))
+	}
+	// Output:
+	// [1]
+	// reg 0.0 accuracy: 0.980 proba sum: 1.000 cov[0][0,0]: 0.1242
+	// reg 0.1 accuracy: 0.973 proba sum: 1.000 cov[0][0,0]: 0.2118
+	// reg 0.5 accuracy: 0.947 proba sum: 1.000 cov[0][0,0]: 0.5621
+}
+
+func ExampleQuadraticDiscriminantAnalysis_collinear() {
+	log.SetOutput(os.Stdout)
+	log.SetFlags(0)
+	defer func() { log.SetOutput(os.Stderr); log.SetFlags(log.LstdFlags) }()
+	// the second feature of class 2 is twice the first one
+	X := mat.NewDense(6, 2, []float64{-1, -1, -2, -1, -3, -2, 1, 2, 2, 4, 3, 6})
+	Y := mat.NewDense(6, 1, []float64{1, 1, 1, 2, 2, 2})
+	qda := NewQuadraticDiscriminantAnalysis()
+	qda.RegParam = .1
+	qda.Fit(X, Y)
+	fmt.Println(qda.Predict(mat.NewDense(2, 2, []float64{-2, -1, 2, 4}), nil).RawMatrix().Data)
+	// Output:
+	// QuadraticDiscriminantAnalysis: variables are collinear in class 2
+	// [1 2]
+}
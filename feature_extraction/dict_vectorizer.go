@@ -0,0 +1,163 @@
+package featureextraction
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DictVectorizer converts records mapping feature names to values into a sparse matrix.
+// numeric values (including bools) are kept in a column named by the key. string values are one-hot encoded in a
+// column named key+Separator+value, as are the elements of []string values. feature names are sorted if Sort.
+// keys and values unseen during Fit are ignored by Transform
+type DictVectorizer struct {
+	Separator string
+	Sort      bool
+
+	FeatureNames []string
+	Vocabulary   map[string]int
+}
+
+// NewDictVectorizer returns a *DictVectorizer with separator "=" and sorted features
+func NewDictVectorizer() *DictVectorizer {
+	return &DictVectorizer{Separator: "=", Sort: true}
+}
+
+// Fit learns the feature names of records
+func (m *DictVectorizer) Fit(records []map[string]interface{}) *DictVectorizer {
+	m.FeatureNames, m.Vocabulary = nil, make(map[string]int)
+	for _, record := range records {
+		for _, key := range sortedKeys(record) {
+			visit(key, record[key], m.Separator, func(name string, value float64) {
+				if _, ok := m.Vocabulary[name]; !ok {
+					m.Vocabulary[name] = len(m.FeatureNames)
+					m.FeatureNames = append(m.FeatureNames, name)
+				}
+			})
+		}
+	}
+	if m.Sort {
+		sort.Strings(m.FeatureNames)
+		for j, name := range m.FeatureNames {
+			m.Vocabulary[name] = j
+		}
+	}
+	return m
+}
+
+// Transform returns the feature matrix of records
+func (m *DictVectorizer) Transform(records []map[string]interface{}) *base.CSRMatrix {
+	if m.Vocabulary == nil {
+		panic(fmt.Errorf("DictVectorizer: Transform called before Fit"))
+	}
+	X := base.NewCSRMatrix(len(records), len(m.FeatureNames))
+	for _, record := range records {
+		var indices []int
+		var values []float64
+		for key, value := range record {
+			visit(key, value, m.Separator, func(name string, value float64) {
+				if j, ok := m.Vocabulary[name]; ok {
+					indices = append(indices, j)
+					values = append(values, value)
+				}
+			})
+		}
+		X.AppendRow(indices, values)
+	}
+	return X
+}
+
+// FitTransform learns the feature names of records and returns their feature matrix
+func (m *DictVectorizer) FitTransform(records []map[string]interface{}) *base.CSRMatrix {
+	return m.Fit(records).Transform(records)
+}
+
+// InverseTransform returns for each row of X a map from feature names to non-zero values
+func (m *DictVectorizer) InverseTransform(X mat.Matrix) []map[string]float64 {
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != len(m.FeatureNames) {
+		panic(fmt.Errorf("DictVectorizer: X has %d features, expected %d", nFeatures, len(m.FeatureNames)))
+	}
+	records := make([]map[string]float64, nSamples)
+	for i := range records {
+		records[i] = make(map[string]float64)
+		for j, name := range m.FeatureNames {
+			if v := X.At(i, j); v != 0 {
+				records[i][name] = v
+			}
+		}
+	}
+	return records
+}
+
+// GetFeatureNamesOut returns the feature names in column order
+func (m *DictVectorizer) GetFeatureNamesOut() []string {
+	return append([]string(nil), m.FeatureNames...)
+}
+
+// visit calls f with the feature names and values of key and value, separator joining keys and string values
+func visit(key string, value interface{}, separator string, f func(name string, value float64)) {
+	switch v := value.(type) {
+	case string:
+		f(key+separator+v, 1)
+	case []string:
+		for _, s := range v {
+			f(key+separator+s, 1)
+		}
+	default:
+		x, ok := toFloat(value)
+		if !ok {
+			panic(fmt.Errorf("unsupported value type %T for key %s", value, key))
+		}
+		f(key, x)
+	}
+}
+
+// sortedKeys returns the keys of record in increasing order, to make feature order deterministic
+func sortedKeys(record map[string]interface{}) []string {
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toFloat converts numeric and bool values to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,37 @@
+package featureextraction
+
+import (
+	"fmt"
+
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleDictVectorizer() {
+	records := []map[string]interface{}{
+		{"foo": 1, "bar": 2},
+		{"foo": 3., "baz": 1},
+		{"city": "Paris", "temperature": 12, "tags": []string{"rain", "wind"}},
+		{"city": "London", "temperature": 9.5, "sunny": true},
+	}
+	v := NewDictVectorizer()
+	X := v.FitTransform(records)
+	fmt.Println(v.GetFeatureNamesOut())
+	fmt.Printf("%g\n", mat.Formatted(X))
+	fmt.Println(v.InverseTransform(v.Transform([]map[string]interface{}{{"city": "Paris", "foo": 4, "unseen": 1}})))
+
+	// the output is usable by any base.Predicter
+	Y := mat.NewDense(4, 1, []float64{1, 3, 12, 9.5})
+	regr := linearmodel.NewLinearRegression()
+	regr.Fit(X, Y)
+	fmt.Printf("%.3f\n", mat.Formatted(regr.Predict(X, nil).T()))
+	// Output:
+	// [bar baz city=London city=Paris foo sunny tags=rain tags=wind temperature]
+	// ⎡  2    0    0    0    1    0    0    0    0⎤
+	// ⎢  0    1    0    0    3    0    0    0    0⎥
+	// ⎢  0    0    0    1    0    0    1    1   12⎥
+	// ⎣  0    0    1    0    0    1    0    0  9.5⎦
+	// [map[city=Paris:1 foo:4]]
+	// [ 1.000   3.000  12.000   9.500]
+}
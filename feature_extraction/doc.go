@@ -0,0 +1,3 @@
+// Package featureextraction builds feature vectors from records given as maps: DictVectorizer and FeatureHasher.
+// the text subpackage handles text documents.
+package featureextraction
@@ -0,0 +1,48 @@
+package featureextraction
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+)
+
+// FeatureHasher converts records mapping feature names to values into a sparse matrix of NFeatures columns, using the
+// signed 32 bits MurmurHash3 of the feature names as column indices. it needs no fit and no memory for a vocabulary.
+// numeric values (including bools) are hashed using the key, string values using key=value with a value of 1, as are
+// the elements of []string values. if AlternateSign, the sign of the hash is used as the sign of the value, so that
+// collisions tend to cancel out
+type FeatureHasher struct {
+	NFeatures     int
+	AlternateSign bool
+}
+
+// NewFeatureHasher returns a *FeatureHasher with 2^20 features and alternate sign
+func NewFeatureHasher() *FeatureHasher {
+	return &FeatureHasher{NFeatures: 1 << 20, AlternateSign: true}
+}
+
+// Transform returns the hashed feature matrix of records
+func (m *FeatureHasher) Transform(records []map[string]interface{}) *base.CSRMatrix {
+	if m.NFeatures <= 0 {
+		panic(fmt.Errorf("FeatureHasher: NFeatures must be > 0, got %d", m.NFeatures))
+	}
+	X := base.NewCSRMatrix(len(records), m.NFeatures)
+	for _, record := range records {
+		var indices []int
+		var values []float64
+		for key, value := range record {
+			visit(key, value, "=", func(name string, value float64) {
+				j, sign := base.HashIndex(name, m.NFeatures, m.AlternateSign)
+				indices = append(indices, j)
+				values = append(values, sign*value)
+			})
+		}
+		X.AppendRow(indices, values)
+	}
+	return X
+}
+
+// FitTransform is Transform, as FeatureHasher is stateless
+func (m *FeatureHasher) FitTransform(records []map[string]interface{}) *base.CSRMatrix {
+	return m.Transform(records)
+}
@@ -0,0 +1,20 @@
+package featureextraction
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleFeatureHasher() {
+	h := NewFeatureHasher()
+	h.NFeatures = 10
+	X := h.Transform([]map[string]interface{}{
+		{"dog": 1, "cat": 2, "elephant": 4},
+		{"dog": 2, "run": 5},
+	})
+	fmt.Printf("%g\n", mat.Formatted(X))
+	// Output:
+	// ⎡ 0   0  -4  -1   0   0   0   0   0   2⎤
+	// ⎣ 0   0   0  -2  -5   0   0   0   0   0⎦
+}
@@ -0,0 +1,151 @@
+package text
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTokenPattern selects tokens of 2 or more alphanumeric characters, like scikit-learn's (?u)\b\w\w+\b
+const DefaultTokenPattern = `[\p{L}\p{N}_]{2,}`
+
+// AnalyzerOptions are the options of the vectorizers turning a document into a sequence of terms.
+// documents are transformed by Preprocessor (default: lower case if Lowercase), then split into terms by Analyzer:
+// "word" (default) produces word n-grams of the tokens returned by Tokenizer (default: matches of TokenPattern,
+// or of its first group if any), excluding StopWords. "char" produces character n-grams of the whole document and
+// "char_wb" character n-grams of words padded with spaces.
+// NGramRange is the range [min,max] of n-grams lengths, default [1,1]
+type AnalyzerOptions struct {
+	Lowercase    bool
+	Preprocessor func(string) string
+	Tokenizer    func(string) []string
+	TokenPattern string
+	StopWords    []string
+	Analyzer     string
+	NGramRange   [2]int
+}
+
+// NewAnalyzerOptions returns AnalyzerOptions for lower case word unigrams
+func NewAnalyzerOptions() AnalyzerOptions {
+	return AnalyzerOptions{Lowercase: true, TokenPattern: DefaultTokenPattern, Analyzer: "word", NGramRange: [2]int{1, 1}}
+}
+
+// BuildAnalyzer returns a function splitting a document into terms
+func (o *AnalyzerOptions) BuildAnalyzer() func(doc string) []string {
+	preprocess := o.Preprocessor
+	if preprocess == nil {
+		preprocess = func(doc string) string { return doc }
+		if o.Lowercase {
+			preprocess = strings.ToLower
+		}
+	}
+	minN, maxN := o.NGramRange[0], o.NGramRange[1]
+	if minN <= 0 && maxN <= 0 {
+		minN, maxN = 1, 1
+	}
+	if minN <= 0 || maxN < minN {
+		panic(fmt.Errorf("invalid NGramRange %v", o.NGramRange))
+	}
+	switch o.Analyzer {
+	case "", "word":
+		tokenize := o.Tokenizer
+		if tokenize == nil {
+			tokenize = tokenizer(o.TokenPattern)
+		}
+		stopWords := make(map[string]bool, len(o.StopWords))
+		for _, w := range o.StopWords {
+			stopWords[w] = true
+		}
+		return func(doc string) []string {
+			var tokens []string
+			for _, token := range tokenize(preprocess(doc)) {
+				if !stopWords[token] {
+					tokens = append(tokens, token)
+				}
+			}
+			return wordNGrams(tokens, minN, maxN)
+		}
+	case "char":
+		return func(doc string) []string { return charNGrams(preprocess(doc), minN, maxN) }
+	case "char_wb":
+		return func(doc string) []string { return charWBNGrams(preprocess(doc), minN, maxN) }
+	}
+	panic(fmt.Errorf("unknown Analyzer %s", o.Analyzer))
+}
+
+// tokenizer returns a function returning the matches of pattern, or of its first group if any
+func tokenizer(pattern string) func(string) []string {
+	if pattern == "" {
+		pattern = DefaultTokenPattern
+	}
+	re := regexp.MustCompile(pattern)
+	if re.NumSubexp() > 1 {
+		panic(fmt.Errorf("TokenPattern %s has more than one group", pattern))
+	}
+	return func(doc string) []string {
+		if re.NumSubexp() == 0 {
+			return re.FindAllString(doc, -1)
+		}
+		var tokens []string
+		for _, match := range re.FindAllStringSubmatch(doc, -1) {
+			tokens = append(tokens, match[1])
+		}
+		return tokens
+	}
+}
+
+// wordNGrams returns the n-grams of tokens for n in [minN,maxN], tokens being joined by a space
+func wordNGrams(tokens []string, minN, maxN int) []string {
+	if maxN == 1 {
+		return tokens
+	}
+	var ngrams []string
+	for n := minN; n <= maxN && n <= len(tokens); n++ {
+		for i := 0; i+n <= len(tokens); i++ {
+			ngrams = append(ngrams, strings.Join(tokens[i:i+n], " "))
+		}
+	}
+	return ngrams
+}
+
+var whiteSpaces = regexp.MustCompile(`\s\s+`)
+
+// charNGrams returns the character n-grams of doc for n in [minN,maxN] after white spaces normalization
+func charNGrams(doc string, minN, maxN int) []string {
+	runes := []rune(whiteSpaces.ReplaceAllString(doc, " "))
+	var ngrams []string
+	for n := minN; n <= maxN && n <= len(runes); n++ {
+		for i := 0; i+n <= len(runes); i++ {
+			ngrams = append(ngrams, string(runes[i:i+n]))
+		}
+	}
+	return ngrams
+}
+
+// charWBNGrams returns the character n-grams of the words of doc padded with spaces. words shorter than n produce
+// a single n-gram
+func charWBNGrams(doc string, minN, maxN int) []string {
+	var ngrams []string
+	for _, word := range strings.Fields(doc) {
+		w := []rune(" " + word + " ")
+		for n := minN; n <= maxN; n++ {
+			offset := 0
+			ngrams = append(ngrams, string(w[:min(n, len(w))]))
+			for offset+n < len(w) {
+				offset++
+				ngrams = append(ngrams, string(w[offset:offset+n]))
+			}
+			if offset == 0 {
+				break
+			}
+		}
+	}
+	return ngrams
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,19 @@
+package text
+
+import (
+	"fmt"
+)
+
+func ExampleAnalyzerOptions_BuildAnalyzer() {
+	options := NewAnalyzerOptions()
+	options.NGramRange = [2]int{1, 2}
+	options.StopWords = EnglishStopWords
+	fmt.Printf("%q\n", options.BuildAnalyzer()("Bi-grams are cool!"))
+	options = NewAnalyzerOptions()
+	options.Analyzer = "char_wb"
+	options.NGramRange = [2]int{2, 3}
+	fmt.Printf("%q\n", options.BuildAnalyzer()("Jumpy fox"))
+	// Output:
+	// ["bi" "grams" "cool" "bi grams" "grams cool"]
+	// [" j" "ju" "um" "mp" "py" "y " " ju" "jum" "ump" "mpy" "py " " f" "fo" "ox" "x " " fo" "fox" "ox "]
+}
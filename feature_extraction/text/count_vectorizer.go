@@ -0,0 +1,166 @@
+package text
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CountVectorizer converts documents to a sparse matrix of term counts.
+// terms are produced by the embedded AnalyzerOptions. terms found in more than MaxDF documents or in less than MinDF
+// documents are ignored (MaxDF <= 1 and MinDF < 1 are proportions of documents, counts otherwise). if MaxFeatures > 0,
+// only the MaxFeatures most frequent terms are kept. if FixedVocabulary is not nil, it is used as vocabulary instead of
+// the terms found by Fit. if Binary, counts are replaced by 1.
+// the vocabulary is sorted alphabetically: column j of the output is the count of GetFeatureNamesOut()[j]
+type CountVectorizer struct {
+	AnalyzerOptions
+	MaxDF, MinDF    float64
+	MaxFeatures     int
+	FixedVocabulary []string
+	Binary          bool
+
+	// Vocabulary maps terms to column indices. StopWordsRemoved are the terms ignored because of MaxDF, MinDF or MaxFeatures
+	Vocabulary       map[string]int
+	StopWordsRemoved []string
+}
+
+// NewCountVectorizer returns a *CountVectorizer counting lower case words and keeping all terms
+func NewCountVectorizer() *CountVectorizer {
+	return &CountVectorizer{AnalyzerOptions: NewAnalyzerOptions(), MaxDF: 1, MinDF: 1}
+}
+
+// Fit learns the vocabulary of docs
+func (m *CountVectorizer) Fit(docs []string) *CountVectorizer {
+	m.FitTransform(docs)
+	return m
+}
+
+// FitTransform learns the vocabulary of docs and returns their term counts
+func (m *CountVectorizer) FitTransform(docs []string) *base.CSRMatrix {
+	analyze := m.BuildAnalyzer()
+	m.StopWordsRemoved = nil
+	if m.FixedVocabulary != nil {
+		m.Vocabulary = make(map[string]int, len(m.FixedVocabulary))
+		for j, term := range m.FixedVocabulary {
+			if _, ok := m.Vocabulary[term]; ok {
+				panic(fmt.Errorf("CountVectorizer: duplicate term %q in FixedVocabulary", term))
+			}
+			m.Vocabulary[term] = j
+		}
+		return m.transform(docs, analyze)
+	}
+	nDocs := len(docs)
+	maxDF, minDF := m.MaxDF, m.MinDF
+	if maxDF <= 1 {
+		maxDF = math.Floor(maxDF * float64(nDocs))
+	}
+	if minDF < 1 {
+		minDF = math.Ceil(minDF * float64(nDocs))
+	}
+	if maxDF < minDF {
+		panic(fmt.Errorf("CountVectorizer: MaxDF corresponds to less documents than MinDF"))
+	}
+	df, tf := make(map[string]int), make(map[string]int)
+	for _, doc := range docs {
+		seen := make(map[string]bool)
+		for _, term := range analyze(doc) {
+			tf[term]++
+			if !seen[term] {
+				seen[term] = true
+				df[term]++
+			}
+		}
+	}
+	terms := make([]string, 0, len(df))
+	for term, count := range df {
+		if float64(count) > maxDF || float64(count) < minDF {
+			m.StopWordsRemoved = append(m.StopWordsRemoved, term)
+			continue
+		}
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	if m.MaxFeatures > 0 && len(terms) > m.MaxFeatures {
+		sort.SliceStable(terms, func(a, b int) bool { return tf[terms[a]] > tf[terms[b]] })
+		m.StopWordsRemoved = append(m.StopWordsRemoved, terms[m.MaxFeatures:]...)
+		terms = terms[:m.MaxFeatures]
+		sort.Strings(terms)
+	}
+	sort.Strings(m.StopWordsRemoved)
+	m.Vocabulary = make(map[string]int, len(terms))
+	for j, term := range terms {
+		m.Vocabulary[term] = j
+	}
+	return m.transform(docs, analyze)
+}
+
+// Transform returns the counts of the vocabulary terms in docs. terms out of the vocabulary are ignored
+func (m *CountVectorizer) Transform(docs []string) *base.CSRMatrix {
+	if m.Vocabulary == nil {
+		panic(fmt.Errorf("CountVectorizer: Transform called before Fit"))
+	}
+	return m.transform(docs, m.BuildAnalyzer())
+}
+
+func (m *CountVectorizer) transform(docs []string, analyze func(string) []string) *base.CSRMatrix {
+	X := base.NewCSRMatrix(len(docs), len(m.Vocabulary))
+	for _, doc := range docs {
+		var indices []int
+		var values []float64
+		for _, term := range analyze(doc) {
+			if j, ok := m.Vocabulary[term]; ok {
+				indices = append(indices, j)
+				values = append(values, 1)
+			}
+		}
+		X.AppendRow(indices, values)
+		if m.Binary {
+			_, counts := X.RowNonZeros(X.Rows - 1)
+			for k := range counts {
+				counts[k] = 1
+			}
+		}
+	}
+	return X
+}
+
+// GetFeatureNamesOut returns the vocabulary terms in column order
+func (m *CountVectorizer) GetFeatureNamesOut() []string {
+	names := make([]string, len(m.Vocabulary))
+	for term, j := range m.Vocabulary {
+		names[j] = term
+	}
+	return names
+}
+
+// InverseTransform returns the terms having a non-zero entry in each row of X
+func (m *CountVectorizer) InverseTransform(X mat.Matrix) [][]string {
+	names := m.GetFeatureNamesOut()
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != len(names) {
+		panic(fmt.Errorf("CountVectorizer: X has %d features, expected %d", nFeatures, len(names)))
+	}
+	terms := make([][]string, nSamples)
+	for i := range terms {
+		terms[i] = []string{}
+		if csr, ok := X.(*base.CSRMatrix); ok {
+			indices, values := csr.RowNonZeros(i)
+			for k, j := range indices {
+				if values[k] != 0 {
+					terms[i] = append(terms[i], names[j])
+				}
+			}
+			continue
+		}
+		for j, name := range names {
+			if X.At(i, j) != 0 {
+				terms[i] = append(terms[i], name)
+			}
+		}
+	}
+	return terms
+}
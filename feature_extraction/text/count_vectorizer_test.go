@@ -0,0 +1,61 @@
+package text
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var corpus = []string{
+	"This is the first document.",
+	"This document is the second document.",
+	"And this is the third one.",
+	"Is this the first document?",
+}
+
+func ExampleCountVectorizer() {
+	vectorizer := NewCountVectorizer()
+	X := vectorizer.FitTransform(corpus)
+	fmt.Println(vectorizer.GetFeatureNamesOut())
+	fmt.Printf("%g\n", mat.Formatted(X))
+	fmt.Println(vectorizer.InverseTransform(vectorizer.Transform([]string{"the second unknown document"})))
+
+	vectorizer2 := NewCountVectorizer()
+	vectorizer2.NGramRange = [2]int{2, 2}
+	X2 := vectorizer2.FitTransform(corpus)
+	fmt.Println(vectorizer2.GetFeatureNamesOut())
+	fmt.Printf("%g\n", mat.Formatted(X2))
+	// Output:
+	// [and document first is one second the third this]
+	// ⎡0  1  1  1  0  0  1  0  1⎤
+	// ⎢0  2  0  1  0  1  1  0  1⎥
+	// ⎢1  0  0  1  1  0  1  1  1⎥
+	// ⎣0  1  1  1  0  0  1  0  1⎦
+	// [[document second the]]
+	// [and this document is first document is the is this second document the first the second the third third one this document this is this the]
+	// ⎡0  0  1  1  0  0  1  0  0  0  0  1  0⎤
+	// ⎢0  1  0  1  0  1  0  1  0  0  1  0  0⎥
+	// ⎢1  0  0  1  0  0  0  0  1  1  0  1  0⎥
+	// ⎣0  0  1  0  1  0  1  0  0  0  0  0  1⎦
+}
+
+func ExampleCountVectorizer_df() {
+	vectorizer := NewCountVectorizer()
+	vectorizer.MaxDF = .8
+	vectorizer.MinDF = 2
+	vectorizer.Binary = true
+	X := vectorizer.FitTransform(corpus)
+	fmt.Println(vectorizer.GetFeatureNamesOut(), vectorizer.StopWordsRemoved)
+	fmt.Printf("%g\n", mat.Formatted(X))
+	vectorizer = NewCountVectorizer()
+	vectorizer.MaxFeatures = 3
+	vectorizer.Fit(corpus)
+	fmt.Println(vectorizer.GetFeatureNamesOut())
+	// Output:
+	// [document first] [and is one second the third this]
+	// ⎡1  1⎤
+	// ⎢1  0⎥
+	// ⎢0  0⎥
+	// ⎣1  1⎦
+	// [document is the]
+}
@@ -0,0 +1,4 @@
+// Package text has utilities to build feature vectors from text documents: CountVectorizer, TfidfTransformer,
+// TfidfVectorizer and HashingVectorizer. documents are tokenized and counted into *base.CSRMatrix matrices,
+// usable as input of any base.Predicter.
+package text
@@ -0,0 +1,62 @@
+package text
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+)
+
+// HashingVectorizer converts documents to a sparse matrix of hashed term counts. it needs no fit and no memory for a
+// vocabulary, at the cost of possible collisions.
+// terms are produced by the embedded AnalyzerOptions and mapped to one of NFeatures columns using the signed 32 bits
+// MurmurHash3 of the term. if AlternateSign, the sign of the hash is used as the sign of the count, so that
+// collisions tend to cancel out. if Binary, counts are replaced by 1 (or -1). rows are then normalized using Norm:
+// "l2" (default), "l1" or "" for none
+type HashingVectorizer struct {
+	AnalyzerOptions
+	NFeatures     int
+	AlternateSign bool
+	Binary        bool
+	Norm          string
+}
+
+// NewHashingVectorizer returns a *HashingVectorizer with 2^20 features, alternate sign and l2 normalization
+func NewHashingVectorizer() *HashingVectorizer {
+	return &HashingVectorizer{AnalyzerOptions: NewAnalyzerOptions(), NFeatures: 1 << 20, AlternateSign: true, Norm: "l2"}
+}
+
+// Transform returns the hashed term counts of docs
+func (m *HashingVectorizer) Transform(docs []string) *base.CSRMatrix {
+	if m.NFeatures <= 0 {
+		panic(fmt.Errorf("HashingVectorizer: NFeatures must be > 0, got %d", m.NFeatures))
+	}
+	analyze := m.BuildAnalyzer()
+	X := base.NewCSRMatrix(len(docs), m.NFeatures)
+	for _, doc := range docs {
+		var indices []int
+		var values []float64
+		for _, term := range analyze(doc) {
+			j, sign := base.HashIndex(term, m.NFeatures, m.AlternateSign)
+			indices = append(indices, j)
+			values = append(values, sign)
+		}
+		X.AppendRow(indices, values)
+		if m.Binary {
+			_, counts := X.RowNonZeros(X.Rows - 1)
+			for k, v := range counts {
+				if v > 0 {
+					counts[k] = 1
+				} else if v < 0 {
+					counts[k] = -1
+				}
+			}
+		}
+	}
+	normalizeRows(X, m.Norm)
+	return X
+}
+
+// FitTransform is Transform, as HashingVectorizer is stateless
+func (m *HashingVectorizer) FitTransform(docs []string) *base.CSRMatrix {
+	return m.Transform(docs)
+}
@@ -0,0 +1,30 @@
+package text
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleHashingVectorizer() {
+	vectorizer := NewHashingVectorizer()
+	vectorizer.NFeatures = 16
+	X := vectorizer.Transform(corpus)
+	fmt.Println(X.Dims())
+	fmt.Printf("%.3f\n", mat.Formatted(X))
+	// Output:
+	// 4 16
+	// ⎡-0.577   0.000   0.000   0.000   0.000   0.000   0.000   0.000  -0.577   0.000   0.000   0.000   0.000   0.577   0.000   0.000⎤
+	// ⎢-0.816   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.408   0.000   0.408   0.000   0.000⎥
+	// ⎢ 0.000   0.000   0.000   0.000  -0.707   0.707   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.000   0.000⎥
+	// ⎣-0.577   0.000   0.000   0.000   0.000   0.000   0.000   0.000  -0.577   0.000   0.000   0.000   0.000   0.577   0.000   0.000⎦
+}
+
+func ExampleHashingVectorizer_invalidNFeatures() {
+	defer func() { fmt.Println(recover()) }()
+	vectorizer := NewHashingVectorizer()
+	vectorizer.NFeatures = 0
+	vectorizer.Transform(corpus)
+	// Output:
+	// HashingVectorizer: NFeatures must be > 0, got 0
+}
@@ -0,0 +1,193 @@
+package text
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TfidfTransformer transforms a matrix of term counts to a normalized tf-idf representation.
+// if SublinearTF, tf is replaced by 1+log(tf). if UseIDF, tf is multiplied by idf=log(n/df)+1 where n is the number of
+// documents and df the number of documents containing the term (log((1+n)/(1+df))+1 if SmoothIDF).
+// rows are then normalized using Norm: "l2" (default), "l1" or "" for none
+type TfidfTransformer struct {
+	Norm        string
+	UseIDF      bool
+	SmoothIDF   bool
+	SublinearTF bool
+
+	IDF         []float64
+	NFeaturesIn int
+}
+
+// NewTfidfTransformer returns a *TfidfTransformer with l2 normalization and smoothed idf
+func NewTfidfTransformer() *TfidfTransformer {
+	return &TfidfTransformer{Norm: "l2", UseIDF: true, SmoothIDF: true}
+}
+
+// TransformerClone ...
+func (m *TfidfTransformer) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit computes the idf of each term of X (term counts, usually a *base.CSRMatrix)
+func (m *TfidfTransformer) Fit(X, Y mat.Matrix) base.Fiter {
+	nSamples, nFeatures := X.Dims()
+	m.IDF, m.NFeaturesIn = nil, nFeatures
+	if !m.UseIDF {
+		return m
+	}
+	df := make([]float64, nFeatures)
+	if csr, ok := X.(*base.CSRMatrix); ok {
+		for _, j := range csr.Indices {
+			df[j]++
+		}
+	} else {
+		for i := 0; i < nSamples; i++ {
+			for j := range df {
+				if X.At(i, j) != 0 {
+					df[j]++
+				}
+			}
+		}
+	}
+	n := float64(nSamples)
+	m.IDF = make([]float64, nFeatures)
+	for j := range df {
+		if m.SmoothIDF {
+			m.IDF[j] = math.Log((1+n)/(1+df[j])) + 1
+		} else {
+			m.IDF[j] = math.Log(n/df[j]) + 1
+		}
+	}
+	return m
+}
+
+// Transform returns the dense tf-idf matrix of X
+func (m *TfidfTransformer) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.TransformSparse(toCSR(X)).ToDense(), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *TfidfTransformer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *TfidfTransformer) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+}
+
+// TransformSparse returns the sparse tf-idf matrix of X
+func (m *TfidfTransformer) TransformSparse(X *base.CSRMatrix) *base.CSRMatrix {
+	if m.UseIDF && len(m.IDF) != X.Cols {
+		panic(fmt.Errorf("TfidfTransformer: X has %d features, expected %d", X.Cols, len(m.IDF)))
+	}
+	Xout := &base.CSRMatrix{
+		Rows: X.Rows, Cols: X.Cols,
+		Indptr:  append([]int(nil), X.Indptr...),
+		Indices: append([]int(nil), X.Indices...),
+		Data:    append([]float64(nil), X.Data...),
+	}
+	for k, j := range Xout.Indices {
+		if m.SublinearTF && Xout.Data[k] != 0 {
+			Xout.Data[k] = 1 + math.Log(Xout.Data[k])
+		}
+		if m.UseIDF {
+			Xout.Data[k] *= m.IDF[j]
+		}
+	}
+	normalizeRows(Xout, m.Norm)
+	return Xout
+}
+
+// TfidfVectorizer is a CountVectorizer followed by a TfidfTransformer
+type TfidfVectorizer struct {
+	*CountVectorizer
+	*TfidfTransformer
+}
+
+// NewTfidfVectorizer returns a *TfidfVectorizer with the defaults of NewCountVectorizer and NewTfidfTransformer
+func NewTfidfVectorizer() *TfidfVectorizer {
+	return &TfidfVectorizer{CountVectorizer: NewCountVectorizer(), TfidfTransformer: NewTfidfTransformer()}
+}
+
+// Fit learns the vocabulary and idf of docs
+func (m *TfidfVectorizer) Fit(docs []string) *TfidfVectorizer {
+	m.FitTransform(docs)
+	return m
+}
+
+// FitTransform learns the vocabulary and idf of docs and returns their tf-idf matrix
+func (m *TfidfVectorizer) FitTransform(docs []string) *base.CSRMatrix {
+	counts := m.CountVectorizer.FitTransform(docs)
+	m.TfidfTransformer.Fit(counts, nil)
+	return m.TfidfTransformer.TransformSparse(counts)
+}
+
+// Transform returns the tf-idf matrix of docs
+func (m *TfidfVectorizer) Transform(docs []string) *base.CSRMatrix {
+	return m.TfidfTransformer.TransformSparse(m.CountVectorizer.Transform(docs))
+}
+
+// GetFeatureNamesOut returns the vocabulary terms in column order
+func (m *TfidfVectorizer) GetFeatureNamesOut() []string {
+	return m.CountVectorizer.GetFeatureNamesOut()
+}
+
+// toCSR returns X if it is a *base.CSRMatrix, else a sparse copy of X
+func toCSR(X mat.Matrix) *base.CSRMatrix {
+	if csr, ok := X.(*base.CSRMatrix); ok {
+		return csr
+	}
+	nSamples, nFeatures := X.Dims()
+	csr := base.NewCSRMatrix(nSamples, nFeatures)
+	for i := 0; i < nSamples; i++ {
+		var indices []int
+		var values []float64
+		for j := 0; j < nFeatures; j++ {
+			if v := X.At(i, j); v != 0 {
+				indices = append(indices, j)
+				values = append(values, v)
+			}
+		}
+		csr.AppendRow(indices, values)
+	}
+	return csr
+}
+
+// normalizeRows scales in place each non-null row of X to unit norm: "l2", "l1" or "" for none
+func normalizeRows(X *base.CSRMatrix, norm string) {
+	switch norm {
+	case "":
+		return
+	case "l1", "l2":
+	default:
+		panic(fmt.Errorf("unknown Norm %s", norm))
+	}
+	for i := 0; i < X.Rows; i++ {
+		_, values := X.RowNonZeros(i)
+		var s float64
+		for _, v := range values {
+			if norm == "l1" {
+				s += math.Abs(v)
+			} else {
+				s += v * v
+			}
+		}
+		if norm == "l2" {
+			s = math.Sqrt(s)
+		}
+		if s == 0 {
+			continue
+		}
+		for k := range values {
+			values[k] /= s
+		}
+	}
+}
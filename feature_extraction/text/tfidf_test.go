@@ -0,0 +1,44 @@
+package text
+
+import (
+	"fmt"
+
+	naivebayes "github.com/RobinRCM/sklearn/naive_bayes"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleTfidfTransformer() {
+	counts := NewCountVectorizer().FitTransform(corpus)
+	tfidf := NewTfidfTransformer()
+	tfidf.Fit(counts, nil)
+	fmt.Printf("%.8f\n", tfidf.IDF)
+	fmt.Printf("%.3f\n", mat.Formatted(tfidf.TransformSparse(counts)))
+	// Output:
+	// [1.91629073 1.22314355 1.51082562 1.00000000 1.91629073 1.91629073 1.00000000 1.91629073 1.00000000]
+	// ⎡0.000  0.470  0.580  0.384  0.000  0.000  0.384  0.000  0.384⎤
+	// ⎢0.000  0.688  0.000  0.281  0.000  0.539  0.281  0.000  0.281⎥
+	// ⎢0.512  0.000  0.000  0.267  0.512  0.000  0.267  0.512  0.267⎥
+	// ⎣0.000  0.470  0.580  0.384  0.000  0.000  0.384  0.000  0.384⎦
+}
+
+func ExampleTfidfVectorizer() {
+	vectorizer := NewTfidfVectorizer()
+	vectorizer.SublinearTF = true
+	X := vectorizer.FitTransform(corpus)
+	fmt.Println(vectorizer.GetFeatureNamesOut())
+	fmt.Printf("%.3f\n", mat.Formatted(X))
+
+	// tf-idf features are usable by any classifier
+	Y := mat.NewDense(4, 1, []float64{0, 1, 0, 0})
+	clf := naivebayes.NewGaussianNB(nil, 1e-9)
+	clf.Fit(X, Y)
+	fmt.Println(mat.Formatted(clf.Predict(vectorizer.Transform([]string{"The second document"}), nil).T()))
+	// Output:
+	// [and document first is one second the third this]
+	// ⎡0.000  0.470  0.580  0.384  0.000  0.000  0.384  0.000  0.384⎤
+	// ⎢0.000  0.626  0.000  0.302  0.000  0.579  0.302  0.000  0.302⎥
+	// ⎢0.512  0.000  0.000  0.267  0.512  0.000  0.267  0.512  0.267⎥
+	// ⎣0.000  0.470  0.580  0.384  0.000  0.000  0.384  0.000  0.384⎦
+	// [1]
+}
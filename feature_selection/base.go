@@ -0,0 +1,84 @@
+package featureselection
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// selection is embedded in selectors. Support tells which input features are kept
+type selection struct {
+	Support []bool
+}
+
+// GetSupport returns a mask of the selected features
+func (m *selection) GetSupport() []bool {
+	return append([]bool(nil), m.Support...)
+}
+
+// GetSupportIndices returns the indices of the selected features
+func (m *selection) GetSupportIndices() []int {
+	indices := make([]int, 0, len(m.Support))
+	for j, selected := range m.Support {
+		if selected {
+			indices = append(indices, j)
+		}
+	}
+	return indices
+}
+
+// transform returns the selected columns of X
+func (m *selection) transform(X mat.Matrix) *mat.Dense {
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != len(m.Support) {
+		panic(fmt.Errorf("X has %d features, expected %d", nFeatures, len(m.Support)))
+	}
+	indices := m.GetSupportIndices()
+	Xout := mat.NewDense(nSamples, len(indices), nil)
+	for i := 0; i < nSamples; i++ {
+		row := Xout.RawRowView(i)
+		for c, j := range indices {
+			row[c] = X.At(i, j)
+		}
+	}
+	return Xout
+}
+
+// InverseTransform returns X with zero columns inserted in place of the removed features
+func (m *selection) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	nSamples, _ := X.Dims()
+	Xout = mat.NewDense(nSamples, len(m.Support), nil)
+	for c, j := range m.GetSupportIndices() {
+		for i := 0; i < nSamples; i++ {
+			Xout.Set(i, j, X.At(i, c))
+		}
+	}
+	return Xout, Y
+}
+
+// GetFeatureNamesOut returns the names of the selected features. inputNames default to x0, x1...
+func (m *selection) GetFeatureNamesOut(inputNames []string) []string {
+	inputNames = base.FeatureNamesIn(inputNames, len(m.Support))
+	names := make([]string, 0, len(inputNames))
+	for _, j := range m.GetSupportIndices() {
+		names = append(names, inputNames[j])
+	}
+	return names
+}
+
+// quantile returns the p quantile of sorted values, interpolating linearly like numpy.percentile
+func quantile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	i0 := int(math.Floor(pos))
+	if i0+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	t := pos - float64(i0)
+	return sorted[i0]*(1-t) + sorted[i0+1]*t
+}
@@ -0,0 +1,3 @@
+// Package featureselection selects subsets of the features of X: VarianceThreshold and univariate selectors like
+// SelectKBest using score functions like Chi2 or FClassif
+package featureselection
@@ -0,0 +1,170 @@
+package featureselection
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/RobinRCM/sklearn/base"
+	discriminantanalysis "github.com/RobinRCM/sklearn/discriminant_analysis"
+
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/mat"
+)
+
+// FeatureImportancer is implemented by estimators exposing the importance of each feature they were fitted on
+type FeatureImportancer interface {
+	FeatureImportances() []float64
+}
+
+// featureImportances returns the importance of each of the nFeatures features of a fitted estimator: its
+// FeatureImportances if it is a FeatureImportancer, else the l1 norm over outputs of its (nFeatures,nOutputs)
+// coefficients, given by a Coef method (like linear svm.SVC) or a Coef field of type *mat.Dense or blas64.General (like
+// linear models). the Coef of LinearDiscriminantAnalysis is (nClasses,nFeatures) as in scikit-learn, it is transposed
+func featureImportances(estimator base.Predicter, nFeatures int) []float64 {
+	if importancer, ok := estimator.(FeatureImportancer); ok {
+		return importancer.FeatureImportances()
+	}
+	var coef mat.Matrix
+	if lda, ok := estimator.(*discriminantanalysis.LinearDiscriminantAnalysis); ok {
+		coef = lda.Coef.T()
+	} else if coefer, ok := estimator.(interface{ Coef() *mat.Dense }); ok {
+		coef = coefer.Coef()
+	} else if v := reflect.Indirect(reflect.ValueOf(estimator)); v.Kind() == reflect.Struct {
+		if field := v.FieldByName("Coef"); field.IsValid() {
+			switch c := field.Interface().(type) {
+			case *mat.Dense:
+				coef = c
+			case blas64.General:
+				dense := &mat.Dense{}
+				dense.SetRawMatrix(c)
+				coef = dense
+			}
+		}
+	}
+	if coef == nil {
+		panic(fmt.Errorf("%T has neither FeatureImportances nor Coef", estimator))
+	}
+	r, c := coef.Dims()
+	if r != nFeatures {
+		panic(fmt.Errorf("Coef of %T is %dx%d, expected %d features", estimator, r, c, nFeatures))
+	}
+	importances := make([]float64, nFeatures)
+	for j := range importances {
+		for o := 0; o < c; o++ {
+			importances[j] += math.Abs(coef.At(j, o))
+		}
+	}
+	return importances
+}
+
+// parseThreshold returns the value of threshold for importances: "mean", "median", optionally preceded by a factor
+// like "1.25*mean", or a number
+func parseThreshold(threshold string, importances []float64) float64 {
+	threshold = strings.TrimSpace(threshold)
+	if v, err := strconv.ParseFloat(threshold, 64); err == nil {
+		return v
+	}
+	scale := 1.
+	if i := strings.Index(threshold, "*"); i >= 0 {
+		var err error
+		if scale, err = strconv.ParseFloat(strings.TrimSpace(threshold[:i]), 64); err != nil {
+			panic(fmt.Errorf("invalid threshold %q", threshold))
+		}
+		threshold = strings.TrimSpace(threshold[i+1:])
+	}
+	switch threshold {
+	case "mean":
+		var s float64
+		for _, v := range importances {
+			s += v
+		}
+		return scale * s / float64(len(importances))
+	case "median":
+		sorted := append([]float64(nil), importances...)
+		sort.Float64s(sorted)
+		return scale * quantile(sorted, .5)
+	}
+	panic(fmt.Errorf("invalid threshold %q", threshold))
+}
+
+// SelectFromModel selects the features whose importance in a fitted Estimator is at least Threshold.
+// Threshold is "mean" (default), "median", a scaled one like "1.25*median", or a number.
+// if MaxFeatures>0, at most MaxFeatures features with the highest importances are kept.
+// if Prefit, Fit does not fit Estimator, which must already be fitted.
+// ImportanceGetter, if not nil, returns the importances of the fitted Estimator, else they come from its
+// FeatureImportances or the absolute value of its Coef (summed over outputs)
+type SelectFromModel struct {
+	Estimator        base.Predicter
+	Threshold        string
+	MaxFeatures      int
+	Prefit           bool
+	ImportanceGetter func(estimator base.Predicter) []float64
+
+	Importances    []float64
+	ThresholdValue float64
+	selection
+}
+
+// NewSelectFromModel returns a *SelectFromModel with the "mean" threshold
+func NewSelectFromModel(estimator base.Predicter) *SelectFromModel {
+	return &SelectFromModel{Estimator: estimator, Threshold: "mean"}
+}
+
+// TransformerClone ...
+func (m *SelectFromModel) TransformerClone() base.Transformer {
+	clone := *m
+	if !m.Prefit {
+		clone.Estimator = m.Estimator.PredicterClone()
+	}
+	return &clone
+}
+
+// Fit fits Estimator (unless Prefit) and selects the features
+func (m *SelectFromModel) Fit(X, Y mat.Matrix) base.Fiter {
+	_, nFeatures := X.Dims()
+	if !m.Prefit {
+		m.Estimator.Fit(X, Y)
+	}
+	if m.ImportanceGetter != nil {
+		m.Importances = m.ImportanceGetter(m.Estimator)
+	} else {
+		m.Importances = featureImportances(m.Estimator, nFeatures)
+	}
+	if len(m.Importances) != nFeatures {
+		panic(fmt.Errorf("got %d importances for %d features", len(m.Importances), nFeatures))
+	}
+	threshold := m.Threshold
+	if threshold == "" {
+		threshold = "mean"
+	}
+	m.ThresholdValue = parseThreshold(threshold, m.Importances)
+	m.Support = make([]bool, nFeatures)
+	for j, v := range m.Importances {
+		m.Support[j] = v >= m.ThresholdValue
+	}
+	if m.MaxFeatures > 0 {
+		indices := m.GetSupportIndices()
+		if len(indices) > m.MaxFeatures {
+			sort.SliceStable(indices, func(a, b int) bool { return m.Importances[indices[a]] > m.Importances[indices[b]] })
+			for _, j := range indices[m.MaxFeatures:] {
+				m.Support[j] = false
+			}
+		}
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *SelectFromModel) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fits to data, then transforms it
+func (m *SelectFromModel) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
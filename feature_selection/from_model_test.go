@@ -0,0 +1,22 @@
+package featureselection
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/datasets"
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+)
+
+func ExampleSelectFromModel() {
+	ds := datasets.LoadDiabetes()
+	for _, threshold := range []string{"mean", "1.25*median"} {
+		selector := NewSelectFromModel(linearmodel.NewLinearRegression())
+		selector.Threshold = threshold
+		Xout, _ := selector.FitTransform(ds.X, ds.Y)
+		_, nFeatures := Xout.Dims()
+		fmt.Printf("%s: %.2f %d features %s\n", threshold, selector.ThresholdValue, nFeatures, selector.GetFeatureNamesOut(ds.FeatureNames))
+	}
+	// Output:
+	// mean: 346.00 4 features [bmi s1 s2 s5]
+	// 1.25*median: 352.63 4 features [bmi s1 s2 s5]
+}
@@ -0,0 +1,228 @@
+package featureselection
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/neighbors"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+)
+
+// MutualInfo estimates the mutual information between each feature of X and the target, in nats.
+// continuous variables use the k-nearest-neighbors estimators of Kraskov et al. and Ross, with NNeighbors neighbors.
+// DiscreteFeatures, if not nil, marks the discrete features (default: all features are continuous).
+// continuous variables are scaled to unit variance and a small noise drawn using RandomState is added to break ties
+type MutualInfo struct {
+	NNeighbors       int
+	DiscreteFeatures []bool
+	RandomState      base.Source
+}
+
+// NewMutualInfo returns a *MutualInfo with 3 neighbors and continuous features
+func NewMutualInfo() *MutualInfo {
+	return &MutualInfo{NNeighbors: 3}
+}
+
+// MutualInfoClassif is a ScoreFunc returning the mutual information between each continuous feature of X and the
+// classes in Y, using NewMutualInfo defaults. p-values are nil
+func MutualInfoClassif(X, Y mat.Matrix) (scores, pValues []float64) {
+	return NewMutualInfo().Classif(X, Y)
+}
+
+// MutualInfoRegression is a ScoreFunc returning the mutual information between each continuous feature of X and the
+// continuous target in Y, using NewMutualInfo defaults. p-values are nil
+func MutualInfoRegression(X, Y mat.Matrix) (scores, pValues []float64) {
+	return NewMutualInfo().Regression(X, Y)
+}
+
+// Classif returns the mutual information between each feature of X and the classes in the first column of Y.
+// its method value is a ScoreFunc
+func (m *MutualInfo) Classif(X, Y mat.Matrix) (scores, pValues []float64) {
+	return m.estimate(X, Y, true), nil
+}
+
+// Regression returns the mutual information between each feature of X and the continuous target in the first column
+// of Y. its method value is a ScoreFunc
+func (m *MutualInfo) Regression(X, Y mat.Matrix) (scores, pValues []float64) {
+	return m.estimate(X, Y, false), nil
+}
+
+func (m *MutualInfo) estimate(X, Y mat.Matrix, discreteTarget bool) []float64 {
+	nSamples, nFeatures := X.Dims()
+	if m.DiscreteFeatures != nil && len(m.DiscreteFeatures) != nFeatures {
+		panic(fmt.Errorf("DiscreteFeatures has %d elements, expected %d", len(m.DiscreteFeatures), nFeatures))
+	}
+	if m.NNeighbors < 1 {
+		panic(fmt.Errorf("NNeighbors must be positive, got %d", m.NNeighbors))
+	}
+	discrete := func(j int) bool { return m.DiscreteFeatures != nil && m.DiscreteFeatures[j] }
+	source := m.RandomState
+	if source == base.Source(nil) {
+		source = base.NewSource(0)
+	}
+	rnd := rand.New(source)
+	columns := make([][]float64, nFeatures)
+	for j := range columns {
+		columns[j] = mat.Col(nil, j, X)
+		if !discrete(j) {
+			scaleAndJitter(columns[j], rnd)
+		}
+	}
+	y := make([]float64, nSamples)
+	mat.Col(y, 0, Y)
+	if !discreteTarget {
+		scaleAndJitter(y, rnd)
+	}
+	scores := make([]float64, nFeatures)
+	base.Parallelize(-1, nFeatures, func(th, start, end int) {
+		for j := start; j < end; j++ {
+			switch {
+			case discrete(j) && discreteTarget:
+				scores[j] = miDD(columns[j], y)
+			case discrete(j):
+				scores[j] = miCD(y, columns[j], m.NNeighbors)
+			case discreteTarget:
+				scores[j] = miCD(columns[j], y, m.NNeighbors)
+			default:
+				scores[j] = miCC(columns[j], y, m.NNeighbors)
+			}
+		}
+	})
+	return scores
+}
+
+// scaleAndJitter divides x by its standard deviation, without centering, and adds a small gaussian noise
+func scaleAndJitter(x []float64, rnd *rand.Rand) {
+	var mean, meanAbs, variance float64
+	for _, v := range x {
+		mean += v
+	}
+	mean /= float64(len(x))
+	for _, v := range x {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(x)))
+	for i := range x {
+		if std > 0 {
+			x[i] /= std
+		}
+		meanAbs += math.Abs(x[i])
+	}
+	noise := 1e-10 * math.Max(1, meanAbs/float64(len(x)))
+	for i := range x {
+		x[i] += noise * rnd.NormFloat64()
+	}
+}
+
+// countWithin returns the number of values of x, including x[i], at most radius[i] away from each x[i]
+func countWithin(x []float64, radius []float64) []float64 {
+	X := mat.NewDense(len(x), 1, append([]float64(nil), x...))
+	tree := neighbors.NewKDTree(X, 20)
+	counts := make([]float64, len(x))
+	base.Parallelize(-1, len(x), func(th, start, end int) {
+		for i := start; i < end; i++ {
+			counts[i] = float64(len(tree.QueryBallPoint(X.Slice(i, i+1, 0, 1), radius[i], math.Inf(1))[0]))
+		}
+	})
+	return counts
+}
+
+// kthNeighborRadius returns, for each row of X, a radius slightly lower than its max-norm distance to its kth
+// neighbor, so that radius queries count strictly closer points
+func kthNeighborRadius(X mat.Matrix, k int) []float64 {
+	nSamples, _ := X.Dims()
+	dd, _ := neighbors.NewKDTree(X, 20).Query(X, k+1, 0, math.Inf(1), math.Inf(1))
+	radius := make([]float64, nSamples)
+	for i := range radius {
+		radius[i] = math.Nextafter(dd.At(i, k), 0)
+	}
+	return radius
+}
+
+// meanDigamma returns the mean of digamma(x)
+func meanDigamma(x []float64) float64 {
+	var s float64
+	for _, v := range x {
+		s += mathext.Digamma(v)
+	}
+	return s / float64(len(x))
+}
+
+// miCC returns the mutual information between continuous variables x and y (Kraskov et al. 2004, first estimator)
+func miCC(x, y []float64, k int) float64 {
+	n := len(x)
+	xy := mat.NewDense(n, 2, nil)
+	xy.SetCol(0, x)
+	xy.SetCol(1, y)
+	radius := kthNeighborRadius(xy, k)
+	nx, ny := countWithin(x, radius), countWithin(y, radius)
+	// counts include the point itself
+	mi := mathext.Digamma(float64(n)) + mathext.Digamma(float64(k)) - meanDigamma(nx) - meanDigamma(ny)
+	return math.Max(0, mi)
+}
+
+// miCD returns the mutual information between continuous c and discrete d (Ross 2014). samples whose label is unique
+// are ignored
+func miCD(c, d []float64, k int) float64 {
+	byLabel := make(map[float64][]int)
+	var labels []float64
+	for i, label := range d {
+		if _, ok := byLabel[label]; !ok {
+			labels = append(labels, label)
+		}
+		byLabel[label] = append(byLabel[label], i)
+	}
+	sort.Float64s(labels)
+	var kept, radius, kAll, labelCounts []float64
+	for _, label := range labels {
+		indices := byLabel[label]
+		count := len(indices)
+		if count < 2 {
+			continue
+		}
+		kk := k
+		if kk > count-1 {
+			kk = count - 1
+		}
+		cl := mat.NewDense(count, 1, nil)
+		for r, i := range indices {
+			cl.Set(r, 0, c[i])
+		}
+		for r, rad := range kthNeighborRadius(cl, kk) {
+			kept = append(kept, cl.At(r, 0))
+			radius = append(radius, rad)
+			kAll = append(kAll, float64(kk))
+			labelCounts = append(labelCounts, float64(count))
+		}
+	}
+	n := len(kept)
+	if n == 0 {
+		return 0
+	}
+	mAll := countWithin(kept, radius)
+	mi := mathext.Digamma(float64(n)) + meanDigamma(kAll) - meanDigamma(labelCounts) - meanDigamma(mAll)
+	return math.Max(0, mi)
+}
+
+// miDD returns the mutual information between discrete x and y from their contingency table
+func miDD(x, y []float64) float64 {
+	type pair struct{ x, y float64 }
+	joint := make(map[pair]float64)
+	px, py := make(map[float64]float64), make(map[float64]float64)
+	for i := range x {
+		joint[pair{x[i], y[i]}]++
+		px[x[i]]++
+		py[y[i]]++
+	}
+	n := float64(len(x))
+	var mi float64
+	for p, nxy := range joint {
+		mi += nxy / n * math.Log(nxy*n/(px[p.x]*py[p.y]))
+	}
+	return math.Max(0, mi)
+}
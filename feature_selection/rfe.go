@@ -0,0 +1,301 @@
+package featureselection
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/metrics"
+	modelselection "github.com/RobinRCM/sklearn/model_selection"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RFE is a recursive feature elimination: Estimator is fitted on the remaining features and the Step features with
+// the lowest importances are removed, until NFeaturesToSelect features remain (default: half of the features).
+// Step is a number of features if >= 1, or a fraction of the features in (0,1).
+// importances are given by ImportanceGetter if not nil, like for SelectFromModel.
+// Ranking is 1 for selected features, and increases with the earliness of the elimination.
+// Estimator is finally fitted on the selected features and used by Predict and Score
+type RFE struct {
+	Estimator         base.Predicter
+	NFeaturesToSelect int
+	Step              float64
+	ImportanceGetter  func(estimator base.Predicter) []float64
+
+	NFeatures int
+	Ranking   []int
+	selection
+}
+
+var (
+	_ base.Transformer = &RFE{}
+	_ base.Predicter   = &RFE{}
+)
+
+// NewRFE returns a *RFE removing one feature at a time
+func NewRFE(estimator base.Predicter, nFeaturesToSelect int) *RFE {
+	return &RFE{Estimator: estimator, NFeaturesToSelect: nFeaturesToSelect, Step: 1}
+}
+
+// TransformerClone ...
+func (m *RFE) TransformerClone() base.Transformer {
+	clone := *m
+	clone.Estimator = m.Estimator.PredicterClone()
+	return &clone
+}
+
+// PredicterClone ...
+func (m *RFE) PredicterClone() base.Predicter {
+	return m.TransformerClone().(*RFE)
+}
+
+// IsClassifier returns Estimator.IsClassifier
+func (m *RFE) IsClassifier() bool { return m.Estimator.IsClassifier() }
+
+// GetNOutputs returns Estimator.GetNOutputs
+func (m *RFE) GetNOutputs() int { return m.Estimator.GetNOutputs() }
+
+// stepSize returns the number of features removed at each iteration
+func stepSize(step float64, nFeatures int) int {
+	switch {
+	case step <= 0:
+		panic(fmt.Errorf("Step must be positive, got %g", step))
+	case step < 1:
+		if n := int(step * float64(nFeatures)); n > 1 {
+			return n
+		}
+		return 1
+	}
+	return int(step)
+}
+
+// Fit selects the features and fits Estimator on them
+func (m *RFE) Fit(X, Y mat.Matrix) base.Fiter {
+	_, nFeatures := X.Dims()
+	nSelect := m.NFeaturesToSelect
+	if nSelect <= 0 {
+		nSelect = nFeatures / 2
+	}
+	m.fit(X, Y, nSelect, nil)
+	return m
+}
+
+// fit eliminates features until nSelect remain. if stepScore is not nil, it is called with the estimator fitted on the
+// remaining features at each step, including the final fit of Estimator
+func (m *RFE) fit(Xmatrix, Ymatrix mat.Matrix, nSelect int, stepScore func(estimator base.Predicter)) {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	_, nFeatures := X.Dims()
+	if nSelect < 1 || nSelect > nFeatures {
+		panic(fmt.Errorf("cannot select %d features out of %d", nSelect, nFeatures))
+	}
+	step := stepSize(m.Step, nFeatures)
+	m.Support, m.Ranking = make([]bool, nFeatures), make([]int, nFeatures)
+	for j := range m.Support {
+		m.Support[j], m.Ranking[j] = true, 1
+	}
+	for count := nFeatures; count > nSelect; {
+		features := m.GetSupportIndices()
+		estimator := m.Estimator.PredicterClone()
+		estimator.Fit(m.transform(X), Y)
+		if stepScore != nil {
+			stepScore(estimator)
+		}
+		importances := m.importances(estimator, count)
+		order := make([]int, count)
+		for c := range order {
+			order[c] = c
+		}
+		sort.SliceStable(order, func(a, b int) bool { return importances[order[a]] < importances[order[b]] })
+		n := step
+		if n > count-nSelect {
+			n = count - nSelect
+		}
+		for _, c := range order[:n] {
+			m.Support[features[c]] = false
+		}
+		for j, selected := range m.Support {
+			if !selected {
+				m.Ranking[j]++
+			}
+		}
+		count -= n
+	}
+	m.NFeatures = nSelect
+	m.Estimator.Fit(m.transform(X), Y)
+	if stepScore != nil {
+		stepScore(m.Estimator)
+	}
+}
+
+func (m *RFE) importances(estimator base.Predicter, nFeatures int) []float64 {
+	var importances []float64
+	if m.ImportanceGetter != nil {
+		importances = m.ImportanceGetter(estimator)
+	} else {
+		importances = featureImportances(estimator, nFeatures)
+	}
+	if len(importances) != nFeatures {
+		panic(fmt.Errorf("got %d importances for %d features", len(importances), nFeatures))
+	}
+	return importances
+}
+
+// Transform returns the selected features of X
+func (m *RFE) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fits to data, then transforms it
+func (m *RFE) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// Predict predicts Y using Estimator on the selected features of X
+func (m *RFE) Predict(X mat.Matrix, Y mat.Mutable) *mat.Dense {
+	return m.Estimator.Predict(m.transform(X), Y)
+}
+
+// Score returns the Score of Estimator on the selected features of X
+func (m *RFE) Score(X, Y mat.Matrix) float64 {
+	return m.Estimator.Score(m.transform(X), Y)
+}
+
+// defaultScorer returns the accuracy for classifiers, and the r2 score for regressors
+func defaultScorer(estimator base.Predicter) func(Ytrue, Ypred mat.Matrix) float64 {
+	if estimator.IsClassifier() {
+		return func(Ytrue, Ypred mat.Matrix) float64 { return metrics.AccuracyScore(Ytrue, Ypred, true, nil) }
+	}
+	return func(Ytrue, Ypred mat.Matrix) float64 {
+		return metrics.R2Score(Ytrue, Ypred, nil, "variance_weighted").At(0, 0)
+	}
+}
+
+// defaultCV returns a KFold with 5 folds and a seeded RandomState, whose clones give the same splits
+func defaultCV() modelselection.Splitter {
+	return &modelselection.KFold{NSplits: 5, RandomState: base.NewSource(0)}
+}
+
+// RFECV is a RFE whose number of features is chosen by cross-validation among nFeatures, nFeatures-Step, ... down to
+// MinFeaturesToSelect (default 1). for each split of CV (default 5 folds of a KFold with a seeded RandomState), a
+// single elimination is run on the train set, and the estimator fitted at each step is scored on the test set with
+// Scorer (default accuracy for classifiers and r2 for regressors). the splits are processed in NJobs goroutines.
+// CVNFeatures holds the evaluated counts in increasing order and CVScores their mean test score. on ties, the lowest
+// count is chosen
+type RFECV struct {
+	RFE
+	MinFeaturesToSelect int
+	CV                  modelselection.Splitter
+	Scorer              func(Ytrue, Ypred mat.Matrix) float64
+	NJobs               int
+
+	CVNFeatures []int
+	CVScores    []float64
+}
+
+var (
+	_ base.Transformer = &RFECV{}
+	_ base.Predicter   = &RFECV{}
+)
+
+// NewRFECV returns a *RFECV removing one feature at a time
+func NewRFECV(estimator base.Predicter) *RFECV {
+	return &RFECV{RFE: RFE{Estimator: estimator, Step: 1}, MinFeaturesToSelect: 1}
+}
+
+// TransformerClone ...
+func (m *RFECV) TransformerClone() base.Transformer {
+	clone := *m
+	clone.Estimator = m.Estimator.PredicterClone()
+	if m.CV != modelselection.Splitter(nil) {
+		clone.CV = m.CV.SplitterClone()
+	}
+	return &clone
+}
+
+// PredicterClone ...
+func (m *RFECV) PredicterClone() base.Predicter {
+	return m.TransformerClone().(*RFECV)
+}
+
+// Fit evaluates each number of features, then selects the best number of features and fits Estimator on them
+func (m *RFECV) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	_, nFeatures := X.Dims()
+	minFeatures := m.MinFeaturesToSelect
+	if minFeatures < 1 {
+		minFeatures = 1
+	}
+	if minFeatures > nFeatures {
+		panic(fmt.Errorf("MinFeaturesToSelect=%d is greater than the number of features %d", minFeatures, nFeatures))
+	}
+	cv := m.CV
+	if cv == modelselection.Splitter(nil) {
+		cv = defaultCV()
+	}
+	scorer := m.Scorer
+	if scorer == nil {
+		scorer = defaultScorer(m.Estimator)
+	}
+	step := stepSize(m.Step, nFeatures)
+	// the counts reached by eliminating step features at a time from nFeatures
+	var counts []int
+	for count := nFeatures; count > minFeatures; count -= step {
+		counts = append(counts, count)
+	}
+	counts = append(counts, minFeatures)
+	m.CVNFeatures = make([]int, len(counts))
+	for c, count := range counts {
+		m.CVNFeatures[len(counts)-1-c] = count
+	}
+	var splits []modelselection.Split
+	for split := range cv.SplitterClone().Split(X, Y) {
+		splits = append(splits, split)
+	}
+	splitScores := make([][]float64, len(splits))
+	base.Parallelize(m.NJobs, len(splits), func(th, start, end int) {
+		for s := start; s < end; s++ {
+			Xtrain, Ytrain := rows(X, splits[s].TrainIndex), rows(Y, splits[s].TrainIndex)
+			Xtest, Ytest := rows(X, splits[s].TestIndex), rows(Y, splits[s].TestIndex)
+			rfe := &RFE{Estimator: m.Estimator.PredicterClone(), Step: m.Step, ImportanceGetter: m.ImportanceGetter}
+			// steps go from nFeatures down to minFeatures, while CVNFeatures is increasing
+			step := len(counts)
+			splitScores[s] = make([]float64, len(counts))
+			rfe.fit(Xtrain, Ytrain, minFeatures, func(estimator base.Predicter) {
+				step--
+				Ypred := mat.NewDense(len(splits[s].TestIndex), estimator.GetNOutputs(), nil)
+				estimator.Predict(rfe.transform(Xtest), Ypred)
+				splitScores[s][step] = scorer(Ytest, Ypred)
+			})
+		}
+	})
+	m.CVScores = make([]float64, len(m.CVNFeatures))
+	best := 0
+	for c := range m.CVScores {
+		for _, scores := range splitScores {
+			m.CVScores[c] += scores[c] / float64(len(splits))
+		}
+		if m.CVScores[c] > m.CVScores[best] {
+			best = c
+		}
+	}
+	m.RFE.fit(X, Y, m.CVNFeatures[best], nil)
+	return m
+}
+
+// rows returns the rows of X with given indices
+func rows(X *mat.Dense, indices []int) *mat.Dense {
+	_, nCols := X.Dims()
+	Xout := mat.NewDense(len(indices), nCols, nil)
+	for r, i := range indices {
+		Xout.SetRow(r, X.RawRowView(i))
+	}
+	return Xout
+}
+
+// FitTransform fits to data, then transforms it
+func (m *RFECV) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
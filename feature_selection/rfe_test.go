@@ -0,0 +1,102 @@
+package featureselection
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/datasets"
+	discriminantanalysis "github.com/RobinRCM/sklearn/discriminant_analysis"
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+	modelselection "github.com/RobinRCM/sklearn/model_selection"
+	"github.com/RobinRCM/sklearn/svm"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleRFE() {
+	ds := datasets.LoadIris()
+	rfe := NewRFE(discriminantanalysis.NewLinearDiscriminantAnalysis(), 2)
+	rfe.Fit(ds.X, ds.Y)
+	fmt.Println("ranking:", rfe.Ranking)
+	fmt.Println("selected:", rfe.GetFeatureNamesOut(ds.FeatureNames))
+	fmt.Printf("accuracy: %.3f\n", rfe.Score(ds.X, ds.Y))
+	// Output:
+	// ranking: [3 1 2 1]
+	// selected: [sepal width (cm) petal width (cm)]
+	// accuracy: 0.967
+}
+
+func ExampleRFE_svc() {
+	// the class depends on x0 and x2 only
+	X := mat.NewDense(16, 4, nil)
+	Y := mat.NewDense(16, 1, nil)
+	for i := 0; i < 16; i++ {
+		x0, x2 := float64(i%4)-1.5, float64(i/4)-1.5
+		X.SetRow(i, []float64{x0, float64((i*7)%16)/8 - 1, x2, float64((i*3+5)%16)/8 - 1})
+		if x0+x2 > 0 {
+			Y.Set(i, 0, 1)
+		} else {
+			Y.Set(i, 0, -1)
+		}
+	}
+	clf := svm.NewSVC()
+	clf.Kernel = "linear"
+	clf.MaxIter = 20
+	clf.RandomState = base.NewSource(7)
+	rfe := NewRFE(clf, 2)
+	rfe.Fit(X, Y)
+	fmt.Println("ranking:", rfe.Ranking)
+	// Output:
+	// ranking: [1 2 1 3]
+}
+
+func ExampleRFE_svr() {
+	// the target depends on x0 and x2 only
+	X := mat.NewDense(16, 4, nil)
+	Y := mat.NewDense(16, 1, nil)
+	for i := 0; i < 16; i++ {
+		x0, x2 := float64(i%4)-1.5, float64(i/4)-1.5
+		X.SetRow(i, []float64{x0, float64((i*7)%16)/8 - 1, x2, float64((i*3+5)%16)/8 - 1})
+		Y.Set(i, 0, (2*x0-x2)/4)
+	}
+	reg := svm.NewSVR()
+	reg.Kernel = "linear"
+	reg.MaxIter = 20
+	reg.RandomState = base.NewSource(7)
+	rfe := NewRFE(reg, 2)
+	rfe.Fit(X, Y)
+	fmt.Println("ranking:", rfe.Ranking)
+	fmt.Printf("coef: %.2f\n", mat.Formatted(reg.Coef().T()))
+	// Output:
+	// ranking: [1 3 1 2]
+	// coef: [ 0.50  -0.24]
+}
+
+func ExampleRFECV() {
+	ds := datasets.LoadDiabetes()
+	rfecv := NewRFECV(linearmodel.NewLinearRegression())
+	rfecv.Step = 2
+	rfecv.CV = &modelselection.KFold{NSplits: 5, Shuffle: true, RandomState: base.NewSource(7)}
+	rfecv.Fit(ds.X, ds.Y)
+	fmt.Println("number of features:", rfecv.CVNFeatures)
+	fmt.Printf("r2: %.3f\n", rfecv.CVScores)
+	fmt.Println("selected:", rfecv.NFeatures, rfecv.GetFeatureNamesOut(ds.FeatureNames))
+	// Output:
+	// number of features: [1 2 4 6 8 10]
+	// r2: [0.263 0.439 0.467 0.470 0.474 0.466]
+	// selected: 8 [sex bmi bp s1 s2 s3 s4 s5]
+}
+
+func ExampleRFECV_defaultCV() {
+	// the default CV gives the same splits at each fit
+	// with 3 features left, the (nClasses,nFeatures) Coef of LDA is square
+	ds := datasets.LoadIris()
+	for fit := 0; fit < 2; fit++ {
+		rfecv := NewRFECV(discriminantanalysis.NewLinearDiscriminantAnalysis())
+		rfecv.Fit(ds.X, ds.Y)
+		fmt.Printf("accuracy: %.3f selected: %d\n", rfecv.CVScores, rfecv.NFeatures)
+	}
+	// Output:
+	// accuracy: [0.927 0.920 0.947 0.947] selected: 3
+	// accuracy: [0.927 0.920 0.947 0.947] selected: 3
+}
@@ -0,0 +1,152 @@
+package featureselection
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	modelselection "github.com/RobinRCM/sklearn/model_selection"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SequentialFeatureSelector greedily adds (Direction "forward", default) or removes ("backward") the feature giving
+// the best cross-validated score of Estimator, which needs neither coefficients nor importances.
+// if NFeaturesToSelect>0, features are added or removed until NFeaturesToSelect remain. else, they are added or
+// removed while the score improves by at least Tol (Tol may be negative to remove features at a small cost).
+// scores are computed by modelselection.CrossValidate using clones of CV (default 5 folds of a KFold with a seeded
+// RandomState), so that all candidates are evaluated on the same splits if CV has a cloneable RandomState, and Scorer
+// (default accuracy for classifiers and r2 for regressors).
+// candidates are evaluated in NJobs goroutines (default runtime.NumCPU()).
+// CVScores holds the score reached after each added or removed feature
+type SequentialFeatureSelector struct {
+	Estimator         base.Predicter
+	NFeaturesToSelect int
+	Tol               float64
+	Direction         string
+	CV                modelselection.Splitter
+	Scorer            func(Ytrue, Ypred mat.Matrix) float64
+	NJobs             int
+
+	NFeatures int
+	CVScores  []float64
+	selection
+}
+
+// NewSequentialFeatureSelector returns a *SequentialFeatureSelector adding features until nFeaturesToSelect are
+// selected
+func NewSequentialFeatureSelector(estimator base.Predicter, nFeaturesToSelect int) *SequentialFeatureSelector {
+	return &SequentialFeatureSelector{Estimator: estimator, NFeaturesToSelect: nFeaturesToSelect, Direction: "forward"}
+}
+
+// TransformerClone ...
+func (m *SequentialFeatureSelector) TransformerClone() base.Transformer {
+	clone := *m
+	clone.Estimator = m.Estimator.PredicterClone()
+	if m.CV != modelselection.Splitter(nil) {
+		clone.CV = m.CV.SplitterClone()
+	}
+	return &clone
+}
+
+// Fit selects the features
+func (m *SequentialFeatureSelector) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	_, nFeatures := X.Dims()
+	var forward bool
+	switch m.Direction {
+	case "", "forward":
+		forward = true
+	case "backward":
+	default:
+		panic(fmt.Errorf("unknown Direction %s", m.Direction))
+	}
+	if m.NFeaturesToSelect >= nFeatures {
+		panic(fmt.Errorf("NFeaturesToSelect=%d must be lower than the number of features %d", m.NFeaturesToSelect, nFeatures))
+	}
+	auto := m.NFeaturesToSelect <= 0
+	nIterations := nFeatures - 1
+	if !auto && forward {
+		nIterations = m.NFeaturesToSelect
+	} else if !auto {
+		nIterations = nFeatures - m.NFeaturesToSelect
+	}
+	cv := m.CV
+	if cv == modelselection.Splitter(nil) {
+		cv = defaultCV()
+	}
+	scorer := m.Scorer
+	if scorer == nil {
+		scorer = defaultScorer(m.Estimator)
+	}
+
+	// changed marks the features added (forward) or removed (backward)
+	changed := make([]bool, nFeatures)
+	m.CVScores = nil
+	oldScore := math.Inf(-1)
+	for iter := 0; iter < nIterations; iter++ {
+		var candidates []int
+		for j, c := range changed {
+			if !c {
+				candidates = append(candidates, j)
+			}
+		}
+		scores := make([]float64, len(candidates))
+		base.Parallelize(m.NJobs, len(candidates), func(th, start, end int) {
+			for c := start; c < end; c++ {
+				var features []int
+				for j := range changed {
+					// forward: the added features and the candidate. backward: the remaining features but the candidate
+					if (changed[j] || j == candidates[c]) == forward {
+						features = append(features, j)
+					}
+				}
+				res := modelselection.CrossValidate(m.Estimator, columns(X, features), Y, nil, scorer, cv.SplitterClone(), 1)
+				for _, score := range res.TestScore {
+					scores[c] += score / float64(len(res.TestScore))
+				}
+			}
+		})
+		best := 0
+		for c, score := range scores {
+			if score > scores[best] {
+				best = c
+			}
+		}
+		if auto && scores[best]-oldScore < m.Tol {
+			break
+		}
+		oldScore = scores[best]
+		changed[candidates[best]] = true
+		m.CVScores = append(m.CVScores, scores[best])
+	}
+	m.Support = make([]bool, nFeatures)
+	for j, c := range changed {
+		m.Support[j] = c == forward
+	}
+	m.NFeatures = len(m.GetSupportIndices())
+	return m
+}
+
+// columns returns the columns of X with given indices
+func columns(X *mat.Dense, indices []int) *mat.Dense {
+	nSamples, _ := X.Dims()
+	Xout := mat.NewDense(nSamples, len(indices), nil)
+	for c, j := range indices {
+		for i := 0; i < nSamples; i++ {
+			Xout.Set(i, c, X.At(i, j))
+		}
+	}
+	return Xout
+}
+
+// Transform returns the selected features of X
+func (m *SequentialFeatureSelector) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fits to data, then transforms it
+func (m *SequentialFeatureSelector) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
@@ -0,0 +1,46 @@
+package featureselection
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/datasets"
+	modelselection "github.com/RobinRCM/sklearn/model_selection"
+	"github.com/RobinRCM/sklearn/neighbors"
+)
+
+var _ base.Transformer = &SequentialFeatureSelector{}
+
+func ExampleSequentialFeatureSelector() {
+	ds := datasets.LoadIris()
+	for _, direction := range []string{"forward", "backward"} {
+		sfs := NewSequentialFeatureSelector(neighbors.NewKNeighborsClassifier(3, "uniform"), 2)
+		sfs.Direction = direction
+		sfs.CV = &modelselection.KFold{NSplits: 5, Shuffle: true, RandomState: base.NewSource(7)}
+		sfs.Fit(ds.X, ds.Y)
+		fmt.Printf("%s: %s accuracy: %.3f\n", direction, sfs.GetFeatureNamesOut(ds.FeatureNames), sfs.CVScores)
+	}
+	// with NFeaturesToSelect=0, features are added while the accuracy improves by at least Tol
+	sfs := NewSequentialFeatureSelector(neighbors.NewKNeighborsClassifier(3, "uniform"), 0)
+	sfs.Tol = .01
+	sfs.CV = &modelselection.KFold{NSplits: 5, Shuffle: true, RandomState: base.NewSource(7)}
+	sfs.Fit(ds.X, ds.Y)
+	fmt.Printf("auto: %d features %s accuracy: %.3f\n", sfs.NFeatures, sfs.GetFeatureNamesOut(ds.FeatureNames), sfs.CVScores)
+	// Output:
+	// forward: [sepal length (cm) petal length (cm)] accuracy: [0.953 0.953]
+	// backward: [sepal length (cm) petal length (cm)] accuracy: [0.973 0.953]
+	// auto: 1 features [petal length (cm)] accuracy: [0.953]
+}
+
+func ExampleSequentialFeatureSelector_defaultCV() {
+	// the default CV gives the same splits for all candidates and at each fit
+	ds := datasets.LoadIris()
+	for fit := 0; fit < 2; fit++ {
+		sfs := NewSequentialFeatureSelector(neighbors.NewKNeighborsClassifier(3, "uniform"), 2)
+		sfs.Fit(ds.X, ds.Y)
+		fmt.Printf("%s accuracy: %.3f\n", sfs.GetFeatureNamesOut(ds.FeatureNames), sfs.CVScores)
+	}
+	// Output:
+	// [petal length (cm) petal width (cm)] accuracy: [0.933 0.960]
+	// [petal length (cm) petal width (cm)] accuracy: [0.933 0.960]
+}
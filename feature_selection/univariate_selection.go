@@ -0,0 +1,435 @@
+package featureselection
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ScoreFunc returns a score and a p-value for each feature of X given targets Y. higher scores are better
+type ScoreFunc func(X, Y mat.Matrix) (scores, pValues []float64)
+
+// classIndices returns the number of distinct values of the first column of Y and the index of the value of each
+// sample in the sorted distinct values
+func classIndices(Y mat.Matrix) (nClasses int, y []int) {
+	nSamples, _ := Y.Dims()
+	labels := make([]float64, nSamples)
+	mat.Col(labels, 0, Y)
+	classes := append([]float64(nil), labels...)
+	sort.Float64s(classes)
+	for _, c := range classes {
+		if nClasses == 0 || c != classes[nClasses-1] {
+			classes[nClasses] = c
+			nClasses++
+		}
+	}
+	classes = classes[:nClasses]
+	y = make([]int, nSamples)
+	for i, label := range labels {
+		y[i] = sort.SearchFloat64s(classes, label)
+	}
+	return
+}
+
+// Chi2 returns the chi-squared statistics between each non-negative feature of X (like counts or frequencies) and the
+// classes in Y, and their p-values
+func Chi2(X, Y mat.Matrix) (scores, pValues []float64) {
+	nSamples, nFeatures := X.Dims()
+	nClasses, y := classIndices(Y)
+	observed := mat.NewDense(nClasses, nFeatures, nil)
+	classCount := make([]float64, nClasses)
+	featureCount := make([]float64, nFeatures)
+	for i, c := range y {
+		classCount[c]++
+		row := observed.RawRowView(c)
+		for j := range row {
+			x := X.At(i, j)
+			if x < 0 {
+				panic(fmt.Errorf("Chi2: X must be non-negative, got %g", x))
+			}
+			row[j] += x
+			featureCount[j] += x
+		}
+	}
+	scores, pValues = make([]float64, nFeatures), make([]float64, nFeatures)
+	dist := distuv.ChiSquared{K: float64(nClasses - 1)}
+	for j := range scores {
+		for c := 0; c < nClasses; c++ {
+			expected := classCount[c] / float64(nSamples) * featureCount[j]
+			d := observed.At(c, j) - expected
+			scores[j] += d * d / expected
+		}
+		pValues[j] = dist.Survival(scores[j])
+	}
+	return
+}
+
+// FClassif returns the ANOVA F-value between each feature of X and the classes in Y, and their p-values
+func FClassif(X, Y mat.Matrix) (scores, pValues []float64) {
+	nSamples, nFeatures := X.Dims()
+	nClasses, y := classIndices(Y)
+	classCount := make([]float64, nClasses)
+	for _, c := range y {
+		classCount[c]++
+	}
+	dfBetween, dfWithin := float64(nClasses-1), float64(nSamples-nClasses)
+	scores, pValues = make([]float64, nFeatures), make([]float64, nFeatures)
+	classSum := make([]float64, nClasses)
+	for j := range scores {
+		var sum, sum2 float64
+		for c := range classSum {
+			classSum[c] = 0
+		}
+		for i, c := range y {
+			x := X.At(i, j)
+			sum += x
+			sum2 += x * x
+			classSum[c] += x
+		}
+		correction := sum * sum / float64(nSamples)
+		ssTotal := sum2 - correction
+		ssBetween := -correction
+		for c, s := range classSum {
+			ssBetween += s * s / classCount[c]
+		}
+		ssWithin := ssTotal - ssBetween
+		scores[j] = (ssBetween / dfBetween) / (ssWithin / dfWithin)
+		pValues[j] = fSurvival(scores[j], dfBetween, dfWithin)
+	}
+	return
+}
+
+// fSurvival returns the survival function of the F distribution with d1 and d2 degrees of freedom at x. it is computed
+// from the CDF of a Beta distribution since distuv.F.Survival, being 1-CDF, cannot return p-values below 1e-16
+func fSurvival(x, d1, d2 float64) float64 {
+	if math.IsNaN(x) {
+		return math.NaN()
+	}
+	return distuv.Beta{Alpha: d2 / 2, Beta: d1 / 2}.CDF(d2 / (d2 + d1*x))
+}
+
+// RRegression returns the Pearson correlation between each feature of X and the first column of Y, and the p-values of
+// the test of a null correlation
+func RRegression(X, Y mat.Matrix) (scores, pValues []float64) {
+	scores = correlations(X, Y)
+	_, pValues = fRegression(scores, X)
+	return
+}
+
+// FRegression returns the F-statistic of the univariate linear regression of the first column of Y on each feature of
+// X, and their p-values
+func FRegression(X, Y mat.Matrix) (scores, pValues []float64) {
+	return fRegression(correlations(X, Y), X)
+}
+
+func fRegression(corr []float64, X mat.Matrix) (scores, pValues []float64) {
+	nSamples, _ := X.Dims()
+	dof := float64(nSamples - 2)
+	scores, pValues = make([]float64, len(corr)), make([]float64, len(corr))
+	for j, r := range corr {
+		scores[j] = r * r / (1 - r*r) * dof
+		pValues[j] = fSurvival(scores[j], 1, dof)
+	}
+	return
+}
+
+func correlations(X, Y mat.Matrix) []float64 {
+	nSamples, nFeatures := X.Dims()
+	y := make([]float64, nSamples)
+	mat.Col(y, 0, Y)
+	var yMean, yNorm float64
+	for _, v := range y {
+		yMean += v / float64(nSamples)
+	}
+	for _, v := range y {
+		yNorm += (v - yMean) * (v - yMean)
+	}
+	corr := make([]float64, nFeatures)
+	x := make([]float64, nSamples)
+	for j := range corr {
+		mat.Col(x, j, X)
+		var xMean, xNorm, xy float64
+		for _, v := range x {
+			xMean += v / float64(nSamples)
+		}
+		for i, v := range x {
+			xNorm += (v - xMean) * (v - xMean)
+			xy += (v - xMean) * (y[i] - yMean)
+		}
+		corr[j] = xy / math.Sqrt(xNorm*yNorm)
+	}
+	return corr
+}
+
+// univariateFilter is embedded in univariate selectors
+type univariateFilter struct {
+	ScoreFunc       ScoreFunc
+	Scores, PValues []float64
+	selection
+}
+
+func (m *univariateFilter) fit(X, Y mat.Matrix) {
+	if m.ScoreFunc == nil {
+		panic(fmt.Errorf("ScoreFunc is nil"))
+	}
+	if Y == nil {
+		panic(fmt.Errorf("univariate feature selection needs Y"))
+	}
+	m.Scores, m.PValues = m.ScoreFunc(X, Y)
+	m.Support = make([]bool, len(m.Scores))
+}
+
+// cleanScores returns a copy of scores where NaN are replaced by the lowest float
+func cleanScores(scores []float64) []float64 {
+	clean := append([]float64(nil), scores...)
+	for j, s := range clean {
+		if math.IsNaN(s) {
+			clean[j] = -math.MaxFloat64
+		}
+	}
+	return clean
+}
+
+// SelectKBest selects the K features with the highest scores. all features are selected if K is negative
+type SelectKBest struct {
+	K int
+	univariateFilter
+}
+
+// NewSelectKBest returns a *SelectKBest selecting the k best features according to scoreFunc
+func NewSelectKBest(scoreFunc ScoreFunc, k int) *SelectKBest {
+	return &SelectKBest{K: k, univariateFilter: univariateFilter{ScoreFunc: scoreFunc}}
+}
+
+// TransformerClone ...
+func (m *SelectKBest) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit scores the features of X and selects the best ones
+func (m *SelectKBest) Fit(X, Y mat.Matrix) base.Fiter {
+	m.fit(X, Y)
+	nFeatures := len(m.Scores)
+	k := m.K
+	if k < 0 {
+		k = nFeatures
+	}
+	if k > nFeatures {
+		panic(fmt.Errorf("K=%d is greater than the number of features %d", k, nFeatures))
+	}
+	scores := cleanScores(m.Scores)
+	indices := make([]int, nFeatures)
+	for j := range indices {
+		indices[j] = j
+	}
+	// on ties, the last features are selected
+	sort.SliceStable(indices, func(a, b int) bool { return scores[indices[a]] < scores[indices[b]] })
+	for _, j := range indices[nFeatures-k:] {
+		m.Support[j] = true
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *SelectKBest) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *SelectKBest) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// SelectPercentile selects the Percentile percent of the features with the highest scores
+type SelectPercentile struct {
+	Percentile float64
+	univariateFilter
+}
+
+// NewSelectPercentile returns a *SelectPercentile selecting percentile percent of the features according to scoreFunc
+func NewSelectPercentile(scoreFunc ScoreFunc, percentile float64) *SelectPercentile {
+	return &SelectPercentile{Percentile: percentile, univariateFilter: univariateFilter{ScoreFunc: scoreFunc}}
+}
+
+// TransformerClone ...
+func (m *SelectPercentile) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit scores the features of X and selects the best ones
+func (m *SelectPercentile) Fit(X, Y mat.Matrix) base.Fiter {
+	if m.Percentile < 0 || m.Percentile > 100 {
+		panic(fmt.Errorf("Percentile must be in [0,100], got %g", m.Percentile))
+	}
+	m.fit(X, Y)
+	nFeatures := len(m.Scores)
+	if m.Percentile == 0 {
+		return m
+	}
+	if m.Percentile == 100 {
+		for j := range m.Support {
+			m.Support[j] = true
+		}
+		return m
+	}
+	scores := cleanScores(m.Scores)
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+	threshold := quantile(sorted, (100-m.Percentile)/100)
+	selected := 0
+	for j, s := range scores {
+		if s > threshold {
+			m.Support[j] = true
+			selected++
+		}
+	}
+	// features whose score equals the threshold are selected in order until the percentile is reached
+	maxSelected := int(float64(nFeatures) * m.Percentile / 100)
+	for j, s := range scores {
+		if s == threshold && selected < maxSelected {
+			m.Support[j] = true
+			selected++
+		}
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *SelectPercentile) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *SelectPercentile) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// SelectFpr selects the features whose p-value is below Alpha, controlling the false positive rate
+type SelectFpr struct {
+	Alpha float64
+	univariateFilter
+}
+
+// NewSelectFpr returns a *SelectFpr with Alpha=0.05
+func NewSelectFpr(scoreFunc ScoreFunc) *SelectFpr {
+	return &SelectFpr{Alpha: .05, univariateFilter: univariateFilter{ScoreFunc: scoreFunc}}
+}
+
+// TransformerClone ...
+func (m *SelectFpr) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit scores the features of X and selects the ones whose p-value is below Alpha
+func (m *SelectFpr) Fit(X, Y mat.Matrix) base.Fiter {
+	m.fit(X, Y)
+	for j, p := range m.PValues {
+		m.Support[j] = p < m.Alpha
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *SelectFpr) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *SelectFpr) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// SelectFdr selects features using the Benjamini-Hochberg procedure, controlling the false discovery rate at Alpha
+type SelectFdr struct {
+	Alpha float64
+	univariateFilter
+}
+
+// NewSelectFdr returns a *SelectFdr with Alpha=0.05
+func NewSelectFdr(scoreFunc ScoreFunc) *SelectFdr {
+	return &SelectFdr{Alpha: .05, univariateFilter: univariateFilter{ScoreFunc: scoreFunc}}
+}
+
+// TransformerClone ...
+func (m *SelectFdr) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit scores the features of X and selects the ones whose p-value is below the highest p-value p(k) satisfying
+// p(k) <= Alpha*k/nFeatures, where p(k) is the k-th lowest p-value
+func (m *SelectFdr) Fit(X, Y mat.Matrix) base.Fiter {
+	m.fit(X, Y)
+	nFeatures := len(m.PValues)
+	sorted := append([]float64(nil), m.PValues...)
+	sort.Float64s(sorted)
+	threshold := math.Inf(-1)
+	for k, p := range sorted {
+		if p <= m.Alpha*float64(k+1)/float64(nFeatures) {
+			threshold = p
+		}
+	}
+	for j, p := range m.PValues {
+		m.Support[j] = p <= threshold
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *SelectFdr) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *SelectFdr) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// SelectFwe selects the features whose p-value is below Alpha/nFeatures, controlling the family-wise error rate
+type SelectFwe struct {
+	Alpha float64
+	univariateFilter
+}
+
+// NewSelectFwe returns a *SelectFwe with Alpha=0.05
+func NewSelectFwe(scoreFunc ScoreFunc) *SelectFwe {
+	return &SelectFwe{Alpha: .05, univariateFilter: univariateFilter{ScoreFunc: scoreFunc}}
+}
+
+// TransformerClone ...
+func (m *SelectFwe) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit scores the features of X and selects the ones whose p-value is below Alpha/nFeatures
+func (m *SelectFwe) Fit(X, Y mat.Matrix) base.Fiter {
+	m.fit(X, Y)
+	for j, p := range m.PValues {
+		m.Support[j] = p < m.Alpha/float64(len(m.PValues))
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *SelectFwe) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *SelectFwe) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
@@ -0,0 +1,98 @@
+package featureselection
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/datasets"
+	"github.com/RobinRCM/sklearn/metrics"
+	modelselection "github.com/RobinRCM/sklearn/model_selection"
+	naivebayes "github.com/RobinRCM/sklearn/naive_bayes"
+	"github.com/RobinRCM/sklearn/pipeline"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ = []base.Transformer{&VarianceThreshold{}, &SelectKBest{}, &SelectPercentile{}, &SelectFpr{}, &SelectFdr{}, &SelectFwe{}}
+
+func ExampleChi2() {
+	ds := datasets.LoadIris()
+	scores, pValues := Chi2(ds.X, ds.Y)
+	fmt.Printf("chi2: %.4f\np-values: %.4g\n", scores, pValues)
+	scores, pValues = FClassif(ds.X, ds.Y)
+	fmt.Printf("F: %.4f\np-values: %.4g\n", scores, pValues)
+	// Output:
+	// chi2: [10.8178 3.5945 116.1698 67.2448]
+	// p-values: [0.004477 0.1658 5.943e-26 2.5e-15]
+	// F: [119.2645 47.3645 1179.0343 959.3244]
+	// p-values: [1.67e-31 1.328e-16 3.052e-91 4.377e-85]
+}
+
+func ExampleFRegression() {
+	ds := datasets.LoadDiabetes()
+	corr, _ := RRegression(ds.X, ds.Y)
+	scores, pValues := FRegression(ds.X, ds.Y)
+	fmt.Println(ds.FeatureNames)
+	fmt.Printf("r: %.3f\nF: %.2f\np-values: %.3g\n", corr, scores, pValues)
+	// Output:
+	// [age sex bmi bp s1 s2 s3 s4 s5 s6]
+	// r: [0.188 0.043 0.586 0.441 0.212 0.174 -0.395 0.430 0.566 0.382]
+	// F: [16.10 0.82 230.65 106.52 20.71 13.75 81.24 100.07 207.27 75.40]
+	// p-values: [7.06e-05 0.366 3.47e-42 1.65e-22 6.92e-06 0.000236 6.16e-18 2.3e-21 8.82e-39 7.58e-17]
+}
+
+func ExampleSelectKBest() {
+	ds := datasets.LoadIris()
+	selector := NewSelectKBest(Chi2, 2)
+	Xt, _ := selector.FitTransform(ds.X, ds.Y)
+	fmt.Println(selector.GetFeatureNamesOut(ds.FeatureNames), selector.GetSupportIndices())
+	fmt.Println(Xt.Dims())
+	for _, selector := range []interface {
+		base.Transformer
+		GetSupportIndices() []int
+	}{
+		NewSelectPercentile(FClassif, 50),
+		NewSelectFpr(Chi2),
+		NewSelectFdr(Chi2),
+		NewSelectFwe(Chi2),
+	} {
+		selector.Fit(ds.X, ds.Y)
+		fmt.Printf("%T %d\n", selector, selector.GetSupportIndices())
+	}
+	// Output:
+	// [petal length (cm) petal width (cm)] [2 3]
+	// 150 2
+	// *featureselection.SelectPercentile [2 3]
+	// *featureselection.SelectFpr [0 2 3]
+	// *featureselection.SelectFdr [0 2 3]
+	// *featureselection.SelectFwe [0 2 3]
+}
+
+func ExampleSelectKBest_gridSearchCV() {
+	ds := datasets.LoadWine()
+	pl := pipeline.NewPipeline(
+		pipeline.NamedStep{Name: "scaler", Fiter: preprocessing.NewStandardScaler()},
+		pipeline.NamedStep{Name: "select", Fiter: NewSelectKBest(FClassif, 1)},
+		pipeline.NamedStep{Name: "nb", Fiter: naivebayes.NewGaussianNB(nil, 1e-9)},
+	)
+	gscv := &modelselection.GridSearchCV{
+		Estimator: pl,
+		ParamGrid: map[string][]interface{}{"select__K": {1, 2, 4, 8}},
+		Scorer: func(Ytrue, Ypred mat.Matrix) float64 {
+			return metrics.AccuracyScore(Ytrue, Ypred, true, nil)
+		},
+		CV: &modelselection.KFold{NSplits: 3, Shuffle: true, RandomState: base.NewSource(7)},
+	}
+	gscv.Fit(ds.X, ds.Y)
+	fmt.Println("best K:", gscv.BestParams["select__K"])
+	for i, k := range gscv.CVResults["select__K"] {
+		fmt.Printf("K=%d score=%.3f\n", k, gscv.CVResults["score"][i])
+	}
+	// Output:
+	// best K: 8
+	// K=1 score=0.697
+	// K=2 score=0.838
+	// K=4 score=0.955
+	// K=8 score=0.961
+}
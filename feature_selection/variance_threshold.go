@@ -0,0 +1,74 @@
+package featureselection
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// VarianceThreshold removes the features whose variance is not greater than Threshold. NaN values are ignored
+type VarianceThreshold struct {
+	Threshold float64
+
+	Variances []float64
+	selection
+}
+
+// NewVarianceThreshold returns a *VarianceThreshold removing constant features
+func NewVarianceThreshold() *VarianceThreshold { return &VarianceThreshold{} }
+
+// TransformerClone ...
+func (m *VarianceThreshold) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit computes the variance of each feature of X. Y is ignored
+func (m *VarianceThreshold) Fit(X, Y mat.Matrix) base.Fiter {
+	nSamples, nFeatures := X.Dims()
+	m.Variances = make([]float64, nFeatures)
+	m.Support = make([]bool, nFeatures)
+	for j := range m.Variances {
+		var n, sum, sum2, min, max float64
+		min, max = math.Inf(1), math.Inf(-1)
+		for i := 0; i < nSamples; i++ {
+			x := X.At(i, j)
+			if math.IsNaN(x) {
+				continue
+			}
+			n++
+			sum += x
+			min, max = math.Min(min, x), math.Max(max, x)
+		}
+		mean := sum / n
+		for i := 0; i < nSamples; i++ {
+			if x := X.At(i, j); !math.IsNaN(x) {
+				sum2 += (x - mean) * (x - mean)
+			}
+		}
+		m.Variances[j] = sum2 / n
+		if m.Threshold == 0 && max == min {
+			// avoid keeping constant features because of rounding errors
+			m.Variances[j] = 0
+		}
+		m.Support[j] = m.Variances[j] > m.Threshold
+	}
+	if len(m.GetSupportIndices()) == 0 {
+		panic(fmt.Errorf("no feature in X meets the variance threshold %g", m.Threshold))
+	}
+	return m
+}
+
+// Transform returns the selected features of X
+func (m *VarianceThreshold) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return m.transform(X), base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *VarianceThreshold) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
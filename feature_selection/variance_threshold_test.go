@@ -0,0 +1,27 @@
+package featureselection
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleVarianceThreshold() {
+	// example from https://scikit-learn.org/stable/modules/feature_selection.html#removing-features-with-low-variance
+	X := mat.NewDense(3, 4, []float64{
+		0, 2, 0, 3,
+		0, 1, 4, 3,
+		0, 1, 1, 3,
+	})
+	selector := NewVarianceThreshold()
+	Xt, _ := selector.FitTransform(X, nil)
+	fmt.Printf("variances: %.4f\n", selector.Variances)
+	fmt.Println(selector.GetSupport(), selector.GetFeatureNamesOut(nil))
+	fmt.Printf("%g\n", mat.Formatted(Xt))
+	// Output:
+	// variances: [0.0000 0.2222 2.8889 0.0000]
+	// [false true true false] [x1 x2]
+	// ⎡2  0⎤
+	// ⎢1  4⎥
+	// ⎣1  1⎦
+}
@@ -0,0 +1,3 @@
+// Package impute has transformers completing missing (NaN) values from other samples or features: KNNImputer and IterativeImputer.
+// see preprocessing.Imputer for univariate imputation and preprocessing.MissingIndicator.
+package impute
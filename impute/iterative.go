@@ -0,0 +1,355 @@
+package impute
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+// IterativeImputer completes missing (NaN) values by modeling each feature with missing values as a function of the
+// other features, in a round-robin fashion.
+// Estimator is the base.Predicter used at each step (default linearmodel.BayesianRidge). missing values are
+// initialized by a preprocessing.Imputer using InitialStrategy ("mean", "median", "most_frequent" or "constant"), then
+// each feature is predicted from the NNearestFeatures features most correlated with it (all if 0), MaxIter times or
+// until the largest change of imputed values is less than Tol times the largest absolute value of X.
+// ImputationOrder is "ascending" (default, features with fewest missing values first), "descending", "roman" (left to
+// right), "arabic" (right to left) or "random". features without missing values during fit are skipped if
+// SkipComplete. imputed values are clipped to [MinValue,MaxValue], unless both are 0.
+// if AddIndicator, the output of a preprocessing.MissingIndicator is appended to the imputed features
+type IterativeImputer struct {
+	Estimator        base.Predicter
+	MaxIter          int
+	Tol              float64
+	NNearestFeatures int
+	InitialStrategy  string
+	ImputationOrder  string
+	SkipComplete     bool
+	MinValue         float64
+	MaxValue         float64
+	AddIndicator     bool
+	RandomState      base.RandomState
+	Verbose          bool
+
+	// NIter is the number of rounds done, ImputationSequence the fitted steps in order of application
+	NIter              int
+	ImputationSequence []ImputerTriplet
+	InitialImputer     *preprocessing.Imputer
+	Indicator          *preprocessing.MissingIndicator
+}
+
+// ImputerTriplet is a step of IterativeImputer: Estimator predicts FeatureIdx from NeighborFeatIdx
+type ImputerTriplet struct {
+	FeatureIdx      int
+	NeighborFeatIdx []int
+	Estimator       base.Predicter
+}
+
+// NewIterativeImputer returns an *IterativeImputer with BayesianRidge, 10 rounds and mean initialization
+func NewIterativeImputer() *IterativeImputer {
+	return &IterativeImputer{
+		MaxIter: 10, Tol: 1e-3, InitialStrategy: "mean", ImputationOrder: "ascending",
+		MinValue: math.Inf(-1), MaxValue: math.Inf(1),
+	}
+}
+
+// TransformerClone ...
+func (m *IterativeImputer) TransformerClone() base.Transformer {
+	clone := *m
+	if m.Estimator != nil {
+		clone.Estimator = m.Estimator.PredicterClone()
+	}
+	if sourceCloner, ok := m.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
+	return &clone
+}
+
+// Fit fits the imputer on X
+func (m *IterativeImputer) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform fits the imputer on X and returns X with imputed values
+func (m *IterativeImputer) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := base.ToDense(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	estimator := m.Estimator
+	if estimator == nil {
+		estimator = linearmodel.NewBayesianRidge()
+	}
+	m.InitialImputer = &preprocessing.Imputer{Strategy: m.InitialStrategy}
+	Xout, _ = m.InitialImputer.FitTransform(X, nil)
+	m.Indicator = nil
+	if m.AddIndicator {
+		m.Indicator = preprocessing.NewMissingIndicator()
+		m.Indicator.ErrorOnNew = false
+		m.Indicator.Fit(X, nil)
+	}
+	m.ImputationSequence, m.NIter = nil, 0
+	missing := missingMask(X)
+	order := m.featureOrder(missing)
+	if m.MaxIter <= 0 || len(order) == 0 || nFeatures < 2 {
+		return m.output(Xout, X), base.ToDense(Ymatrix)
+	}
+	absCorr := m.absCorrelations(Xout)
+	normalizedTol := 0.
+	for _, v := range X.RawMatrix().Data {
+		if !math.IsNaN(v) {
+			normalizedTol = math.Max(normalizedTol, math.Abs(v))
+		}
+	}
+	normalizedTol *= m.Tol
+	rnd := m.random()
+	previous := mat.NewDense(nSamples, nFeatures, nil)
+	for m.NIter = 1; m.NIter <= m.MaxIter; m.NIter++ {
+		previous.Copy(Xout)
+		if m.ImputationOrder == "random" {
+			order = m.featureOrder(missing)
+		}
+		for _, feature := range order {
+			neighborFeatures := m.neighborFeatures(feature, nFeatures, absCorr, rnd)
+			var train, test []int
+			for i := 0; i < nSamples; i++ {
+				if missing[i][feature] {
+					test = append(test, i)
+				} else {
+					train = append(train, i)
+				}
+			}
+			triplet := ImputerTriplet{FeatureIdx: feature, NeighborFeatIdx: neighborFeatures, Estimator: estimator.PredicterClone()}
+			Ytrain := mat.NewDense(len(train), 1, nil)
+			for r, i := range train {
+				Ytrain.Set(r, 0, Xout.At(i, feature))
+			}
+			triplet.Estimator.Fit(selectSamples(Xout, train, neighborFeatures), Ytrain)
+			m.impute(Xout, triplet, test)
+			m.ImputationSequence = append(m.ImputationSequence, triplet)
+		}
+		var change float64
+		for i, row := range missing {
+			for j, isMissing := range row {
+				if isMissing {
+					change = math.Max(change, math.Abs(Xout.At(i, j)-previous.At(i, j)))
+				}
+			}
+		}
+		if m.Verbose {
+			fmt.Printf("IterativeImputer: round %d/%d change %g, scaled tolerance %g\n", m.NIter, m.MaxIter, change, normalizedTol)
+		}
+		if change < normalizedTol {
+			break
+		}
+	}
+	if m.NIter > m.MaxIter {
+		m.NIter = m.MaxIter
+	}
+	return m.output(Xout, X), base.ToDense(Ymatrix)
+}
+
+// Transform imputes missing values of X by applying the fitted steps in sequence
+func (m *IterativeImputer) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := base.ToDense(Xmatrix)
+	Xout, _ = m.InitialImputer.Transform(X, nil)
+	missing := missingMask(X)
+	for _, triplet := range m.ImputationSequence {
+		var test []int
+		for i, row := range missing {
+			if row[triplet.FeatureIdx] {
+				test = append(test, i)
+			}
+		}
+		m.impute(Xout, triplet, test)
+	}
+	return m.output(Xout, X), base.ToDense(Ymatrix)
+}
+
+// GetFeatureNamesOut returns inputNames (default x0, x1...), followed by the names of the missing indicators if
+// AddIndicator
+func (m *IterativeImputer) GetFeatureNamesOut(inputNames []string) []string {
+	names := m.InitialImputer.GetFeatureNamesOut(inputNames)
+	if m.Indicator != nil {
+		names = append(names, m.Indicator.GetFeatureNamesOut(inputNames)...)
+	}
+	return names
+}
+
+// impute predicts feature values of samples using triplet and stores them clipped in Xout
+func (m *IterativeImputer) impute(Xout *mat.Dense, triplet ImputerTriplet, samples []int) {
+	if len(samples) == 0 {
+		return
+	}
+	Ypred := triplet.Estimator.Predict(selectSamples(Xout, samples, triplet.NeighborFeatIdx), &mat.Dense{})
+	minValue, maxValue := m.MinValue, m.MaxValue
+	if minValue == 0 && maxValue == 0 {
+		minValue, maxValue = math.Inf(-1), math.Inf(1)
+	}
+	for r, i := range samples {
+		Xout.Set(i, triplet.FeatureIdx, math.Max(minValue, math.Min(maxValue, Ypred.At(r, 0))))
+	}
+}
+
+// output appends missing indicators if required
+func (m *IterativeImputer) output(Xout, X *mat.Dense) *mat.Dense {
+	if m.Indicator != nil {
+		return m.Indicator.Append(Xout, X)
+	}
+	return Xout
+}
+
+// featureOrder returns the features to impute in ImputationOrder
+func (m *IterativeImputer) featureOrder(missing [][]bool) []int {
+	nFeatures := 0
+	if len(missing) > 0 {
+		nFeatures = len(missing[0])
+	}
+	counts := make([]int, nFeatures)
+	for _, row := range missing {
+		for j, isMissing := range row {
+			if isMissing {
+				counts[j]++
+			}
+		}
+	}
+	var order []int
+	for j, count := range counts {
+		if count > 0 || !m.SkipComplete {
+			order = append(order, j)
+		}
+	}
+	switch m.ImputationOrder {
+	case "", "ascending":
+		sort.SliceStable(order, func(a, b int) bool { return counts[order[a]] < counts[order[b]] })
+	case "descending":
+		sort.SliceStable(order, func(a, b int) bool { return counts[order[a]] > counts[order[b]] })
+	case "roman":
+	case "arabic":
+		for a, b := 0, len(order)-1; a < b; a, b = a+1, b-1 {
+			order[a], order[b] = order[b], order[a]
+		}
+	case "random":
+		m.random().Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+	default:
+		panic(fmt.Errorf("IterativeImputer: unknown ImputationOrder %s", m.ImputationOrder))
+	}
+	return order
+}
+
+// absCorrelations returns the absolute correlations between features of initially imputed X, with a floor of 1e-6,
+// when NNearestFeatures restricts the features used to predict each feature
+func (m *IterativeImputer) absCorrelations(X *mat.Dense) *mat.Dense {
+	_, nFeatures := X.Dims()
+	if m.NNearestFeatures <= 0 || m.NNearestFeatures >= nFeatures-1 {
+		return nil
+	}
+	nSamples, _ := X.Dims()
+	corr := mat.NewDense(nFeatures, nFeatures, nil)
+	means, stds := make([]float64, nFeatures), make([]float64, nFeatures)
+	for j := 0; j < nFeatures; j++ {
+		for i := 0; i < nSamples; i++ {
+			means[j] += X.At(i, j)
+		}
+		means[j] /= float64(nSamples)
+		for i := 0; i < nSamples; i++ {
+			d := X.At(i, j) - means[j]
+			stds[j] += d * d
+		}
+		stds[j] = math.Sqrt(stds[j])
+	}
+	for a := 0; a < nFeatures; a++ {
+		for b := a; b < nFeatures; b++ {
+			c := 0.
+			if a != b && stds[a] > 0 && stds[b] > 0 {
+				for i := 0; i < nSamples; i++ {
+					c += (X.At(i, a) - means[a]) * (X.At(i, b) - means[b])
+				}
+				c = math.Abs(c / stds[a] / stds[b])
+			}
+			c = math.Max(c, 1e-6)
+			corr.Set(a, b, c)
+			corr.Set(b, a, c)
+		}
+	}
+	return corr
+}
+
+// neighborFeatures returns the features used to predict feature: all others, or NNearestFeatures of them drawn with
+// probabilities proportional to their absolute correlation with feature
+func (m *IterativeImputer) neighborFeatures(feature, nFeatures int, absCorr *mat.Dense, rnd *rand.Rand) []int {
+	if absCorr == nil {
+		others := make([]int, 0, nFeatures-1)
+		for j := 0; j < nFeatures; j++ {
+			if j != feature {
+				others = append(others, j)
+			}
+		}
+		return others
+	}
+	weights := make([]float64, nFeatures)
+	for j := range weights {
+		if j != feature {
+			weights[j] = absCorr.At(feature, j)
+		}
+	}
+	selected := make([]int, 0, m.NNearestFeatures)
+	for len(selected) < m.NNearestFeatures {
+		total := 0.
+		for _, w := range weights {
+			total += w
+		}
+		u := rnd.Float64() * total
+		j := 0
+		for ; j < nFeatures-1; j++ {
+			if u < weights[j] {
+				break
+			}
+			u -= weights[j]
+		}
+		for weights[j] == 0 {
+			j--
+		}
+		selected = append(selected, j)
+		weights[j] = 0
+	}
+	sort.Ints(selected)
+	return selected
+}
+
+func (m *IterativeImputer) random() *rand.Rand {
+	if m.RandomState == base.Source(nil) {
+		m.RandomState = base.NewSource(uint64(0))
+	}
+	return rand.New(m.RandomState)
+}
+
+// missingMask returns which values of X are NaN
+func missingMask(X *mat.Dense) [][]bool {
+	nSamples, nFeatures := X.Dims()
+	missing := make([][]bool, nSamples)
+	for i := range missing {
+		missing[i] = make([]bool, nFeatures)
+		for j := range missing[i] {
+			missing[i][j] = math.IsNaN(X.At(i, j))
+		}
+	}
+	return missing
+}
+
+// selectSamples returns the given rows and columns of X
+func selectSamples(X *mat.Dense, samples, features []int) *mat.Dense {
+	Xout := mat.NewDense(len(samples), len(features), nil)
+	for r, i := range samples {
+		row := Xout.RawRowView(r)
+		for c, j := range features {
+			row[c] = X.At(i, j)
+		}
+	}
+	return Xout
+}
@@ -0,0 +1,53 @@
+package impute
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/neighbors"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleIterativeImputer() {
+	// adapted from https://scikit-learn.org/stable/modules/impute.html#multivariate-feature-imputation
+	nan := math.NaN()
+	imputer := NewIterativeImputer()
+	imputer.Fit(mat.NewDense(5, 2, []float64{1, 2, 3, 6, 4, 8, nan, 3, 7, nan}), nil)
+	Xt, _ := imputer.Transform(mat.NewDense(3, 2, []float64{nan, 2, 6, nan, nan, 6}), nil)
+	fmt.Printf("%.2f\n", mat.Formatted(Xt))
+	fmt.Println(imputer.NIter <= imputer.MaxIter, len(imputer.ImputationSequence))
+	// Output:
+	// ⎡ 1.00   2.00⎤
+	// ⎢ 6.00  12.00⎥
+	// ⎣ 3.00   6.00⎦
+	// true 8
+}
+
+func ExampleIterativeImputer_estimator() {
+	// the third feature is the sum of the first two. missing values are predicted with KNeighborsRegressor
+	rnd := rand.New(base.NewSource(7))
+	X := mat.NewDense(100, 3, nil)
+	for i := 0; i < 100; i++ {
+		a, b := rnd.Float64(), rnd.Float64()
+		X.SetRow(i, []float64{a, b, a + b})
+	}
+	Xmissing := mat.DenseCopyOf(X)
+	for i := 0; i < 100; i += 5 {
+		Xmissing.Set(i, i%3, math.NaN())
+	}
+	imputer := NewIterativeImputer()
+	imputer.Estimator = neighbors.NewKNeighborsRegressor(5, "distance")
+	imputer.AddIndicator = true
+	Xt, _ := imputer.FitTransform(Xmissing, nil)
+	_, nColumns := Xt.Dims()
+	maxErr := 0.
+	for i := 0; i < 100; i += 5 {
+		maxErr = math.Max(maxErr, math.Abs(Xt.At(i, i%3)-X.At(i, i%3)))
+	}
+	fmt.Printf("%d columns, max error %.1f\n", nColumns, maxErr)
+	// Output:
+	// 6 columns, max error 0.2
+}
@@ -0,0 +1,165 @@
+package impute
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/neighbors"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// KNNImputer completes missing (NaN) values of each sample using the mean value of the NNeighbors nearest training
+// samples having this feature, according to neighbors.NanEuclideanDistance.
+// Weights is "uniform" (default) or "distance" (neighbors are weighted by the inverse of their distance).
+// features without neighbor having them are completed with their training mean, 0 if the feature is always missing.
+// if AddIndicator, the output of a preprocessing.MissingIndicator is appended to the imputed features.
+// NJobs<=0 means runtime.NumCPU()
+type KNNImputer struct {
+	NNeighbors   int
+	Weights      string
+	AddIndicator bool
+	NJobs        int
+
+	// X are the training samples
+	X         *mat.Dense
+	Indicator *preprocessing.MissingIndicator
+	means     []float64
+}
+
+// NewKNNImputer returns a *KNNImputer using 5 uniformly weighted neighbors
+func NewKNNImputer() *KNNImputer {
+	return &KNNImputer{NNeighbors: 5, Weights: "uniform"}
+}
+
+// TransformerClone ...
+func (m *KNNImputer) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit stores the training samples
+func (m *KNNImputer) Fit(X, Y mat.Matrix) base.Fiter {
+	if m.NNeighbors <= 0 {
+		panic(fmt.Errorf("KNNImputer: NNeighbors must be > 0, got %d", m.NNeighbors))
+	}
+	switch m.Weights {
+	case "", "uniform", "distance":
+	default:
+		panic(fmt.Errorf("KNNImputer: unknown Weights %s", m.Weights))
+	}
+	m.X = mat.DenseCopyOf(X)
+	nSamples, nFeatures := m.X.Dims()
+	m.means = make([]float64, nFeatures)
+	for j := range m.means {
+		n := 0
+		for i := 0; i < nSamples; i++ {
+			if v := m.X.At(i, j); !math.IsNaN(v) {
+				m.means[j] += v
+				n++
+			}
+		}
+		if n > 0 {
+			m.means[j] /= float64(n)
+		}
+	}
+	m.Indicator = nil
+	if m.AddIndicator {
+		m.Indicator = preprocessing.NewMissingIndicator()
+		m.Indicator.ErrorOnNew = false
+		m.Indicator.Fit(X, nil)
+	}
+	return m
+}
+
+// Transform completes missing values of X
+func (m *KNNImputer) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	Xout = mat.DenseCopyOf(X)
+	nSamples, nFeatures := Xout.Dims()
+	nFit, nFitFeatures := m.X.Dims()
+	if nFeatures != nFitFeatures {
+		panic(fmt.Errorf("KNNImputer: X has %d features, expected %d", nFeatures, nFitFeatures))
+	}
+	base.Parallelize(m.NJobs, nSamples, func(th, start, end int) {
+		distances := make([]float64, nFit)
+		donors := make([]int, 0, nFit)
+		for i := start; i < end; i++ {
+			row := Xout.RawRowView(i)
+			computed := false
+			for j, v := range row {
+				if !math.IsNaN(v) {
+					continue
+				}
+				if !computed {
+					sample := mat.NewVecDense(nFeatures, row)
+					for f := 0; f < nFit; f++ {
+						distances[f] = neighbors.NanEuclideanDistance(sample, m.X.RowView(f))
+					}
+					computed = true
+				}
+				donors = donors[:0]
+				for f := 0; f < nFit; f++ {
+					if !math.IsNaN(distances[f]) && !math.IsNaN(m.X.At(f, j)) {
+						donors = append(donors, f)
+					}
+				}
+				row[j] = m.impute(j, donors, distances)
+			}
+		}
+	})
+	if m.Indicator != nil {
+		Xout = m.Indicator.Append(Xout, X)
+	}
+	return Xout, base.ToDense(Y)
+}
+
+// impute returns the (weighted) mean of feature j over the NNeighbors nearest donors
+func (m *KNNImputer) impute(j int, donors []int, distances []float64) float64 {
+	if len(donors) == 0 {
+		return m.means[j]
+	}
+	sort.SliceStable(donors, func(a, b int) bool { return distances[donors[a]] < distances[donors[b]] })
+	if len(donors) > m.NNeighbors {
+		donors = donors[:m.NNeighbors]
+	}
+	if m.Weights == "distance" && distances[donors[0]] > 0 {
+		var sum, sumWeights float64
+		for _, f := range donors {
+			w := 1 / distances[f]
+			sum += w * m.X.At(f, j)
+			sumWeights += w
+		}
+		return sum / sumWeights
+	}
+	// uniform weights, or distance weights with exact matches which get all the weight
+	var sum float64
+	n := 0
+	for _, f := range donors {
+		if m.Weights == "distance" && distances[f] > 0 {
+			break
+		}
+		sum += m.X.At(f, j)
+		n++
+	}
+	return sum / float64(n)
+}
+
+// FitTransform fit to data, then transform it
+func (m *KNNImputer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns inputNames (default x0, x1...), followed by the names of the missing indicators if
+// AddIndicator
+func (m *KNNImputer) GetFeatureNamesOut(inputNames []string) []string {
+	_, nFeatures := m.X.Dims()
+	names := base.FeatureNamesIn(inputNames, nFeatures)
+	if m.Indicator != nil {
+		names = append(names, m.Indicator.GetFeatureNamesOut(inputNames)...)
+	}
+	return names
+}
@@ -0,0 +1,36 @@
+package impute
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleKNNImputer() {
+	// adapted from https://scikit-learn.org/stable/modules/impute.html#nearest-neighbors-imputation
+	nan := math.NaN()
+	X := mat.NewDense(4, 3, []float64{
+		1, 2, nan,
+		3, 4, 3,
+		nan, 6, 5,
+		8, 8, 7,
+	})
+	imputer := NewKNNImputer()
+	imputer.NNeighbors = 2
+	Xt, _ := imputer.FitTransform(X, nil)
+	fmt.Printf("%g\n", mat.Formatted(Xt))
+	imputer.Weights = "distance"
+	imputer.AddIndicator = true
+	Xt, _ = imputer.FitTransform(X, nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+	// Output:
+	// ⎡  1    2    4⎤
+	// ⎢  3    4    3⎥
+	// ⎢5.5    6    5⎥
+	// ⎣  8    8    7⎦
+	// ⎡1.000  2.000  3.667  0.000  1.000⎤
+	// ⎢3.000  4.000  3.000  0.000  0.000⎥
+	// ⎢5.500  6.000  5.000  1.000  0.000⎥
+	// ⎣8.000  8.000  7.000  0.000  0.000⎦
+}
@@ -3,9 +3,7 @@ package linearmodel
 import (
 	"log"
 	"math"
-	"strings"
 	"sync"
-	"time"
 
 	"gonum.org/v1/gonum/floats"
 
@@ -16,26 +14,41 @@ import (
 	"github.com/RobinRCM/sklearn/base"
 	"gonum.org/v1/gonum/mat"
 
+	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas64"
 
-	"golang.org/x/exp/rand"
-	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/optimize"
 )
 
 // LogisticRegression Logistic Regression (aka logit, MaxEnt) classifier.
-// In the multiclass case, the training algorithm uses the one-vs-rest (OvR) scheme if the ‘multi_class’ option is set to ‘ovr’,
-// and uses the cross-entropy loss if the ‘multi_class’ option is set to ‘multinomial’.
-// This class implements regularized logistic regression using the ‘lbfgs’ solvers.
-// support only L2 regularization with primal formulation.
+// In the multiclass case, the training algorithm uses the one-vs-rest (OvR) scheme if MultiClass is "ovr",
+// and uses the cross-entropy loss if MultiClass is "multinomial". "auto" (default) is multinomial for more than 2
+// classes.
+// Alpha is the inverse of the regularization strength C. Penalty is "l2" (default), "l1", "elasticnet" (l1 part
+// L1Ratio) or "none".
+// Solver is "lbfgs" (default) or "newton-cg" for "l2" and "none" penalties, or "saga" for all penalties.
+// ClassWeight is nil, "balanced" (weights inversely proportional to class frequencies) or a map[float64]float64 of
+// weights by class label. it multiplies SampleWeight.
+// an intercept is fitted unless NoIntercept. it is not regularized, unless InterceptScaling>0: the intercept is then the
+// weight of a synthetic feature of value InterceptScaling, regularized like the other weights.
+// if WarmStart, Fit starts from the previous solution if it has the same shape.
 type LogisticRegression struct {
-	Alpha         float64          `json:"alpha"`
-	MaxIter       int              `json:"max_iter"`
-	LossFuncName  string           `json:"loss_func_name"`
-	RandomState   base.RandomState `json:"random_state"`
-	Tol           float64          `json:"tol"`
-	Verbose       bool             `json:"verbose"`
-	NIterNoChange int              `json:"n_iter_no_change"`
+	Alpha            float64          `json:"alpha"`
+	Penalty          string           `json:"penalty"`
+	L1Ratio          float64          `json:"l1_ratio"`
+	Solver           string           `json:"solver"`
+	MultiClass       string           `json:"multi_class"`
+	ClassWeight      interface{}      `json:"class_weight"`
+	NoIntercept      bool             `json:"no_intercept"`
+	InterceptScaling float64          `json:"intercept_scaling"`
+	WarmStart        bool             `json:"warm_start"`
+	MaxIter          int              `json:"max_iter"`
+	LossFuncName     string           `json:"loss_func_name"`
+	RandomState      base.RandomState `json:"random_state"`
+	Tol              float64          `json:"tol"`
+	Verbose          bool             `json:"verbose"`
+	NIterNoChange    int              `json:"n_iter_no_change"`
+	SampleWeight     []float64
 
 	// Outputs
 	NLayers       int
@@ -47,15 +60,17 @@ type LogisticRegression struct {
 	Loss          float64
 
 	// internal
-	t                  int
-	LossCurve          []float64
-	BestLoss           float64
+	LossCurve []float64
+	BestLoss  float64
+	// NoImprovementCount is unused, it is kept for compatibility
 	NoImprovementCount int
-	InterceptsGrads    []float64
-	CoefsGrads         blas64.General
-	packedParameters   []float64
-	packedGrads        []float64
-	// bestParameters     []float64
+	// InterceptsGrads and CoefsGrads are the gradient of the smooth part of the objective at the solution
+	InterceptsGrads  []float64
+	CoefsGrads       blas64.General
+	packedParameters []float64
+	packedGrads      []float64
+	// binary is true when 2 classes are fitted with a single logistic output
+	binary         bool
 	lb             *preprocessing.LabelBinarizer
 	beforeMinimize func(optimize.Problem, []float64)
 }
@@ -69,21 +84,12 @@ var logregActivation = map[string]func(z blas64.General){
 			}
 		}
 	},
-	"relu": func(z blas64.General) {
-		for row, zpos := 0, 0; row < z.Rows; row, zpos = row+1, zpos+z.Stride {
-			for col := 0; col < z.Cols; col++ {
-				if z.Data[zpos+col] < 0 {
-					z.Data[zpos+col] = 0
-				}
-			}
-		}
-	},
 	"softmax": func(z blas64.General) {
 		for row, zpos := 0, 0; row < z.Rows; row, zpos = row+1, zpos+z.Stride {
+			max := floats.Max(z.Data[zpos : zpos+z.Cols])
 			sum := float64(0)
 			for col := 0; col < z.Cols; col++ {
-
-				z.Data[zpos+col] = math.Exp(z.Data[zpos+col])
+				z.Data[zpos+col] = math.Exp(z.Data[zpos+col] - max)
 				sum += z.Data[zpos+col]
 			}
 			for col := 0; col < z.Cols; col++ {
@@ -93,44 +99,6 @@ var logregActivation = map[string]func(z blas64.General){
 	},
 }
 
-// logregLossFunctions is a map for loss functions
-var logregLossFunctions = map[string]func(y, h blas64.General) float64{
-	"log_loss": func(y, h blas64.General) float64 {
-		sum := float64(0)
-		hmin, hmax := math.Nextafter(0, 1), math.Nextafter(1, 0)
-		for row, hpos, ypos := 0, 0, 0; row < y.Rows; row, hpos, ypos = row+1, hpos+h.Stride, ypos+y.Stride {
-			for col := 0; col < y.Cols; col++ {
-				hval := h.Data[hpos+col]
-				if hval < hmin {
-					hval = hmin
-				} else if hval > hmax {
-					hval = hmax
-				}
-				if y.Data[ypos+col] != 0 {
-					sum += -y.Data[ypos+col] * math.Log(hval)
-				}
-			}
-		}
-		return sum / float64(h.Rows)
-	},
-	"binary_log_loss": func(y, h blas64.General) float64 {
-		sum := float64(0)
-		hmin, hmax := math.Nextafter(0, 1), math.Nextafter(1, 0)
-		for row, hpos, ypos := 0, 0, 0; row < y.Rows; row, hpos, ypos = row+1, hpos+h.Stride, ypos+y.Stride {
-			for col := 0; col < y.Cols; col++ {
-				hval := h.Data[hpos+col]
-				if hval < hmin {
-					hval = hmin
-				} else if hval > hmax {
-					hval = hmax
-				}
-				sum += -y.Data[ypos+col]*math.Log(hval) - (1-y.Data[ypos+col])*math.Log1p(-hval)
-			}
-		}
-		return sum / float64(h.Rows)
-	},
-}
-
 func addIntercepts64(a blas64.General, b []float64) {
 	for arow, apos := 0, 0; arow < a.Rows; arow, apos = arow+1, apos+a.Stride {
 		for c := 0; c < a.Cols; c++ {
@@ -139,26 +107,16 @@ func addIntercepts64(a blas64.General, b []float64) {
 	}
 }
 
-func matRowMean64(a blas64.General, b []float64) {
-	for c := 0; c < a.Cols; c++ {
-		b[c] = 0
-	}
-	for arow, apos := 0, 0; arow < a.Rows; arow, apos = arow+1, apos+a.Stride {
-		for c := 0; c < a.Cols; c++ {
-			b[c] += a.Data[apos+c]
-		}
-	}
-	for c := 0; c < a.Cols; c++ {
-		b[c] /= float64(a.Rows)
-	}
-}
-
-// NewLogisticRegression returns a LogisticRegression with defaults: Alpha=1/C=1; Tol=1e-4
+// NewLogisticRegression returns a LogisticRegression with defaults: Alpha=1/C=1; Tol=1e-4, "l2" penalty,
+// "lbfgs" solver, "auto" MultiClass
 func NewLogisticRegression() *LogisticRegression {
 	return &LogisticRegression{
 
-		Alpha:   1,
-		MaxIter: 200,
+		Alpha:      1,
+		Penalty:    "l2",
+		Solver:     "lbfgs",
+		MultiClass: "auto",
+		MaxIter:    200,
 		//RandomState        base.Source,
 		Tol:           1e-4,
 		Verbose:       false,
@@ -172,210 +130,160 @@ func (m *LogisticRegression) PredicterClone() base.Predicter {
 	if sc, ok := m.RandomState.(base.SourceCloner); ok {
 		clone.RandomState = sc.SourceClone()
 	}
+	if m.packedParameters != nil {
+		clone.packedParameters = append([]float64(nil), m.packedParameters...)
+		clone.packedGrads = append([]float64(nil), m.packedGrads...)
+		clone.setParameterViews(len(m.Intercept), m.Coef.Rows)
+	}
 	return &clone
 }
 
-// forwardPass Perform a forward pass on the network by computing the values
-// of the neurons the output layer.
-//        activations : []blas64.General, length = nLayers - 1
-func (m *LogisticRegression) forwardPass(activations []blas64.General) {
-	hiddenActivation := logregActivation["logistic"]
-	var i int
-	for i = 0; i < m.NLayers-1; i++ {
-		blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, activations[i], m.Coef, 0, activations[i+1])
-		addIntercepts64(activations[i+1], m.Intercept)
-		// For the hidden layers
-		if (i + 1) != (m.NLayers - 1) {
-			hiddenActivation(activations[i+1])
-		}
-	}
-	i = m.NLayers - 2
-	// # For the last layer
-	outputActivation := logregActivation[m.OutActivation]
-	outputActivation(activations[i+1])
+// setParameterViews makes Intercept and Coef point into packedParameters, and InterceptsGrads and CoefsGrads into
+// packedGrads
+func (m *LogisticRegression) setParameterViews(nTargets, nFeatures int) {
+	m.Intercept = m.packedParameters[:nTargets]
+	m.Coef = blas64.General{Rows: nFeatures, Cols: nTargets, Stride: nTargets, Data: m.packedParameters[nTargets:]}
+	m.InterceptsGrads = m.packedGrads[:nTargets]
+	m.CoefsGrads = blas64.General{Rows: nFeatures, Cols: nTargets, Stride: nTargets, Data: m.packedGrads[nTargets:]}
 }
 
-func (m *LogisticRegression) sumCoefSquares() float64 {
-	s := float64(0)
-
-	for _, co := range m.Coef.Data {
-		s += co * co
-	}
-
-	return s
+// IsClassifier return true if LossFuncName is not square_loss
+func (m *LogisticRegression) IsClassifier() bool {
+	return true
 }
 
-// computeLossGrad Compute the gradient of loss with respect to Coef and intercept for specified layer.
-// This function does backpropagation for the specified one layer.
-func (m *LogisticRegression) computeLossGrad(layer, NSamples int, activations []blas64.General, deltas, coefGrads blas64.General, interceptGrads []float64) {
-	// coefGrads[layer] = safeSparseDot(activations[layer].T, deltas[layer])
-	// coefGrads[layer] += (self.alpha * self.coefs_[layer])
-	// coefGrads[layer] /= nSamples
-	blas64.Gemm(blas.Trans, blas.NoTrans, 1/float64(NSamples), activations[layer], deltas, 0, coefGrads)
-	blas64.Axpy(m.Alpha/float64(NSamples), blas64.Vector{N: len(m.Coef.Data), Data: m.Coef.Data, Inc: 1}, blas64.Vector{N: len(coefGrads.Data), Data: coefGrads.Data, Inc: 1})
-	// interceptGrads[layer] = np.mean(deltas[layer], 0)
-	matRowMean64(deltas, interceptGrads)
-}
+// Fit compute Coef and Intercept
+func (m *LogisticRegression) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	// # Validate input parameters.
+	m.validateHyperparameters()
+	nSamples, nFeatures := X.Dims()
+	_, m.NOutputs = Y.Dims()
 
-// backprop Compute the m loss function and its corresponding derivatives with respect to each parameter: weights and bias vectors.
-// X : blas64.General shape (nSamples, nFeatures)
-// Y : blas64.General shape (nSamples, nOutputs)
-// activations : []blas64.General, length=2
-// deltas : blas64.General
-// coefGrads : blas64.General
-// interceptGrads : []float64
-func (m *LogisticRegression) backprop(X, y blas64.General, activations []blas64.General, deltas, coefGrads blas64.General, interceptGrads []float64) float64 {
-	nSamples := X.Rows
-	m.forwardPass(activations)
-
-	//# Get loss
-	lossFuncName := m.LossFuncName
-	if strings.EqualFold(lossFuncName, "log_loss") && strings.EqualFold(m.OutActivation, "logistic") {
-		lossFuncName = "binary_log_loss"
-	}
-	// y may have less rows than activations il last batch
-	loss := logregLossFunctions[lossFuncName](y, activations[len(activations)-1])
-	// # Add L2 regularization term to loss
-	loss += (0.5 * m.Alpha) * m.sumCoefSquares() / float64(nSamples)
-
-	//# Backward propagate
-	last := m.NLayers - 2
-
-	// # The calculation of delta[last] here works with following
-	// # combinations of output activation and loss function:
-	// # sigmoid and binary cross entropy, softmax and categorical cross
-	// # entropy, and identity with squared loss
-	//deltas[last] = activations[len(activations)-1] - y
-
-	// y may have less rows than activations il last batch
-	{
-		H := activations[len(activations)-1]
-		D := deltas
-		for r, pos := 0, 0; r < y.Rows; r, pos = r+1, pos+y.Stride {
-			for o, posc := 0, pos; o < y.Cols; o, posc = o+1, posc+1 {
-				D.Data[posc] = H.Data[posc] - y.Data[posc]
-			}
+	yb := Y
+	m.lb, m.binary = nil, false
+	if !isBinarized(Y) {
+		m.lb = preprocessing.NewLabelBinarizer(0, 1)
+		_, yb = m.lb.FitTransform(X, Y)
+		if len(m.lb.Classes) == 1 && len(m.lb.Classes[0]) == 2 && m.MultiClass != "multinomial" {
+			m.binary = true
+			yb = mat.DenseCopyOf(yb.Slice(0, nSamples, 1, 2))
 		}
 	}
-
-	//# Compute gradient for the last layer
-	m.computeLossGrad(
-		last, nSamples, activations, deltas, coefGrads, interceptGrads)
-
-	return loss
-}
-
-// initialize compute size of coef and grad, allocates them and fill Coef with Glorot et Al initialization
-func (m *LogisticRegression) initialize(yCols int, layerUnits []int, isMultiClass bool) {
-	// # set all attributes, allocate weights etc for first call
-	// # Initialize parameters
-	m.NIter = 0
-	m.t = 0
-	m.NOutputs = yCols
-
-	//# Compute the number of layers
-	m.NLayers = len(layerUnits)
-
-	//# Output for regression
-	if isMultiClass {
-		m.OutActivation = "softmax"
-		m.LossFuncName = "log_loss"
-		//# Output for binary class and multi-label
+	_, nTargets := yb.Dims()
+	if nTargets > 1 && m.MultiClass != "ovr" {
+		m.OutActivation, m.LossFuncName = "softmax", "log_loss"
 	} else {
-		m.OutActivation = "logistic"
-		m.LossFuncName = "binary_log_loss"
+		m.OutActivation, m.LossFuncName = "logistic", "binary_log_loss"
 	}
-	//# Initialize coefficient and intercept layers
-	off := (1 + layerUnits[0]) * layerUnits[1]
-
-	mem := make([]float64, 2*off)
-	m.packedParameters = mem[0:off]
-	m.packedGrads = mem[off : 2*off]
+	m.NLayers = 2
 
-	off = 0
-	if m.RandomState == (base.RandomState)(nil) {
-		m.RandomState = base.NewLockedSource(uint64(time.Now().UnixNano()))
+	p := &logisticProblem{
+		X: X.RawMatrix(), Y: yb.RawMatrix(), sw: m.sampleWeights(Y),
+		softmax: m.OutActivation == "softmax", fitIntercept: !m.NoIntercept,
+		P: blas64.General{Rows: nSamples, Cols: nTargets, Stride: nTargets, Data: make([]float64, nSamples*nTargets)},
 	}
-	type Float64er interface {
-		Float64() float64
+	if !m.NoIntercept && m.InterceptScaling > 0 {
+		p.interceptScale = m.InterceptScaling
+	}
+	switch m.Penalty {
+	case "l2":
+		p.l2 = m.Alpha / float64(nSamples)
+	case "l1":
+		p.l1 = m.Alpha / float64(nSamples)
+	case "elasticnet":
+		p.l2 = m.Alpha * (1 - m.L1Ratio) / float64(nSamples)
+		p.l1 = m.Alpha * m.L1Ratio / float64(nSamples)
 	}
-	var rndFloat64 func() float64
-	if float64er, ok := m.RandomState.(Float64er); ok {
-		rndFloat64 = float64er.Float64
-	} else {
-		rndFloat64 = rand.New(m.RandomState).Float64
-	}
-	prevOff := off
-	m.Intercept = mem[off : off+layerUnits[1]]
-	off += layerUnits[1]
-	m.Coef = blas64.General{Rows: layerUnits[0], Cols: layerUnits[1], Stride: layerUnits[1], Data: mem[off : off+layerUnits[0]*layerUnits[1]]}
-	off += layerUnits[0] * layerUnits[1]
-	// # Use the initialization method recommended by
-	// # Glorot et al.
-	factor := float64(6.)
-	fanIn, fanOut := layerUnits[0], layerUnits[1]
-	factor = 2.
-
-	initBound := math.Sqrt(factor / float64(fanIn+fanOut))
-	for pos := prevOff; pos < off; pos++ {
-		mem[pos] = rndFloat64() * initBound
-	}
-
-	m.InterceptsGrads = mem[off : off+layerUnits[1]]
-	off += layerUnits[1]
-	m.CoefsGrads = blas64.General{Rows: layerUnits[0], Cols: layerUnits[1], Stride: layerUnits[1], Data: mem[off : off+layerUnits[0]*layerUnits[1]]}
-	off += layerUnits[0] * layerUnits[1]
-
-	m.BestLoss = math.Inf(1)
-}
 
-// IsClassifier return true if LossFuncName is not square_loss
-func (m *LogisticRegression) IsClassifier() bool {
-	return true
+	nParams := (1 + nFeatures) * nTargets
+	if !m.WarmStart || len(m.packedParameters) != nParams {
+		m.packedParameters = make([]float64, nParams)
+	}
+	m.packedGrads = make([]float64, nParams)
+	m.setParameterViews(nTargets, nFeatures)
+	if m.NoIntercept {
+		for k := range m.Intercept {
+			m.Intercept[k] = 0
+		}
+	}
+	m.LossCurve, m.BestLoss = nil, math.Inf(1)
+	switch m.Solver {
+	case "lbfgs":
+		m.fitLbfgs(p)
+	case "newton-cg":
+		m.fitNewtonCG(p)
+	case "saga":
+		m.fitSaga(p)
+	}
+	m.Loss = p.lossGrad(m.packedParameters, m.packedGrads) + p.l1Penalty(m.packedParameters)
+	return m
 }
 
-// Fit compute Coef and Intercept
-func (m *LogisticRegression) Fit(X, Y mat.Matrix) base.Fiter {
-	var xb, yb *mat.Dense
-	if xg, ok := X.(*mat.Dense); ok {
-		if yg, ok := Y.(*mat.Dense); ok {
-			xb, yb = xg, yg
+// sampleWeights returns SampleWeight multiplied by the weights of the classes in Y, or nil if all weights are 1
+func (m *LogisticRegression) sampleWeights(Y *mat.Dense) []float64 {
+	nSamples, nOutputs := Y.Dims()
+	var sw []float64
+	if m.SampleWeight != nil {
+		if len(m.SampleWeight) != nSamples {
+			log.Panicf("SampleWeight has %d elements, expected %d.", len(m.SampleWeight), nSamples)
 		}
-	} else {
-		xb = mat.DenseCopyOf(X)
-		yb = mat.DenseCopyOf(Y)
+		sw = append([]float64(nil), m.SampleWeight...)
 	}
-	if m.IsClassifier() && !isBinarized(yb) {
-		m.lb = preprocessing.NewLabelBinarizer(0, 1)
-		xbin, ybin := m.lb.FitTransform(X, Y)
-		xb, yb = xbin, ybin
+	if m.ClassWeight == nil {
+		return sw
 	}
-	// # Validate input parameters.
-	m.validateHyperparameters()
-
-	x, y := xb.RawMatrix(), yb.RawMatrix()
-	nSamples, nFeatures := x.Rows, x.Cols
-
-	m.NOutputs = y.Cols
-	layerUnits := []int{nFeatures}
-	layerUnits = append(layerUnits, m.NOutputs)
-
-	if m.RandomState == nil {
-		m.RandomState = rand.New(base.NewLockedSource(uint64(time.Now().UnixNano())))
+	if nOutputs != 1 {
+		log.Panicf("ClassWeight needs a single column of labels, got %d columns.", nOutputs)
+	}
+	classWeight := computeClassWeight(m.ClassWeight, Y)
+	if sw == nil {
+		sw = make([]float64, nSamples)
+		for i := range sw {
+			sw[i] = 1
+		}
 	}
-	m.initialize(y.Cols, layerUnits, y.Cols > 1)
+	for i := range sw {
+		if w, ok := classWeight[Y.At(i, 0)]; ok {
+			sw[i] *= w
+		}
+	}
+	return sw
+}
 
-	// # Initialize lists
-	batchSize := nSamples
-	activations := []blas64.General{x}
-	nFanOut := layerUnits[1]
-	activations = append(activations, blas64.General{Rows: batchSize, Cols: nFanOut, Stride: nFanOut, Data: make([]float64, batchSize*nFanOut)})
-	deltas := blas64.General{Rows: batchSize, Cols: nFanOut, Stride: nFanOut, Data: make([]float64, batchSize*nFanOut)}
+// computeClassWeight returns the weight of each label of the first column of Y: classWeight if it is a
+// map[float64]float64, or weights inversely proportional to class frequencies if it is "balanced"
+func computeClassWeight(classWeight interface{}, Y *mat.Dense) map[float64]float64 {
+	switch cw := classWeight.(type) {
+	case map[float64]float64:
+		return cw
+	case string:
+		// cw is "balanced", checked by validateClassWeight
+		nSamples, _ := Y.Dims()
+		counts := make(map[float64]float64)
+		for i := 0; i < nSamples; i++ {
+			counts[Y.At(i, 0)]++
+		}
+		weights := make(map[float64]float64)
+		for label, count := range counts {
+			weights[label] = float64(nSamples) / (float64(len(counts)) * count)
+		}
+		return weights
+	}
+	return nil
+}
 
-	// # Run the LBFGS solver
-	m.fitLbfgs(x, y, activations, deltas, m.CoefsGrads,
-		m.InterceptsGrads, layerUnits)
-	return m
+// validateClassWeight panics if classWeight is neither nil, "balanced" nor a map[float64]float64
+func validateClassWeight(classWeight interface{}) {
+	switch cw := classWeight.(type) {
+	case nil, map[float64]float64:
+	case string:
+		if cw != "balanced" {
+			log.Panicf("unknown ClassWeight %s.", cw)
+		}
+	default:
+		log.Panicf("ClassWeight must be \"balanced\" or a map[float64]float64, got %T.", cw)
+	}
 }
 
 // GetNOutputs returns output columns number for Y to pass to predict
@@ -396,10 +304,41 @@ func (m *LogisticRegression) validateHyperparameters() {
 	if m.NIterNoChange <= 0 {
 		log.Panicf("nIterNoChange must be > 0, got %d.", m.NIterNoChange)
 	}
+	if m.Penalty == "" {
+		m.Penalty = "l2"
+	}
+	if m.Solver == "" {
+		m.Solver = "lbfgs"
+	}
+	if m.MultiClass == "" {
+		m.MultiClass = "auto"
+	}
+	switch m.Penalty {
+	case "l2", "none":
+	case "l1", "elasticnet":
+		if m.Solver != "saga" {
+			log.Panicf("solver %s supports only l2 or none penalties, got %s penalty.", m.Solver, m.Penalty)
+		}
+	default:
+		log.Panicf("unknown penalty %s.", m.Penalty)
+	}
+	if m.Penalty == "elasticnet" && (m.L1Ratio < 0 || m.L1Ratio > 1) {
+		log.Panicf("L1Ratio must be in [0,1], got %g.", m.L1Ratio)
+	}
+	switch m.Solver {
+	case "lbfgs", "newton-cg", "saga":
+	default:
+		log.Panicf("unknown solver %s.", m.Solver)
+	}
+	switch m.MultiClass {
+	case "auto", "ovr", "multinomial":
+	default:
+		log.Panicf("unknown MultiClass %s.", m.MultiClass)
+	}
+	validateClassWeight(m.ClassWeight)
 }
 
-func (m *LogisticRegression) fitLbfgs(X, y blas64.General, activations []blas64.General, deltas, coefGrads blas64.General,
-	interceptGrads []float64, layerUnits []int) {
+func (m *LogisticRegression) fitLbfgs(p *logisticProblem) {
 	method := &optimize.LBFGS{}
 	settings := &optimize.Settings{
 		FuncEvaluations: m.MaxIter,
@@ -412,39 +351,33 @@ func (m *LogisticRegression) fitLbfgs(X, y blas64.General, activations []blas64.
 	var mu sync.Mutex // sync access to m.Loss on LossCurve
 	problem := optimize.Problem{
 		Func: func(w []float64) float64 {
-			for i := range w {
-				m.packedParameters[i] = float64(w[i])
-			}
-			loss := float64(m.backprop(X, y, activations, deltas, coefGrads, interceptGrads))
+			loss := p.lossGrad(w, nil)
 			mu.Lock()
-			m.Loss = float64(loss)
+			m.Loss = loss
 			m.LossCurve = append(m.LossCurve, m.Loss)
 			m.BestLoss = math.Min(m.BestLoss, m.Loss)
 			mu.Unlock()
 			return loss
 		},
 		Grad: func(g, w []float64) {
-			// Grad is called just after Func with same w
 			if g == nil { // g is nil at first call
 				g = make([]float64, len(w))
 			}
-			for i := range w {
-				g[i] = float64(m.packedGrads[i])
-			}
+			p.lossGrad(w, g)
 		},
 	}
-	w := make([]float64, len(m.packedParameters))
-	for i := range w {
-		w[i] = float64(m.packedParameters[i])
-	}
+	w := append([]float64(nil), m.packedParameters...)
 	if m.beforeMinimize != nil {
 		m.beforeMinimize(problem, w)
 	}
 	res, err := optimize.Minimize(problem, w, settings, method)
-	if err != nil {
+	// the line search fails when no progress is possible at float precision, the minimum being reached
+	if err != nil && (err != optimize.ErrLinesearcherFailure || res == nil) {
 		log.Panic(err)
 	}
-	if res.Status != optimize.GradientThreshold && res.Status != optimize.FunctionConvergence {
+	copy(m.packedParameters, res.X)
+	m.NIter = res.Stats.MajorIterations
+	if err == nil && res.Status != optimize.GradientThreshold && res.Status != optimize.FunctionConvergence {
 		log.Printf("lbfgs optimizer: Maximum iterations (%d) reached and the optimization hasn't converged yet.\n", m.MaxIter)
 	}
 }
@@ -452,23 +385,27 @@ func (m *LogisticRegression) fitLbfgs(X, y blas64.General, activations []blas64.
 // PredictProbas return probability estimates.
 // The returned estimates for all classes are ordered by the label of classes.
 func (m *LogisticRegression) PredictProbas(Xmatrix mat.Matrix, Ymutable mat.Mutable) *mat.Dense {
-	X, Y := base.ToDense(Xmatrix).RawMatrix(), base.ToDense(Ymutable)
-	if Y.IsEmpty() {
-		fanOut := 0
-		if m.lb != nil {
-			for _, classes := range m.lb.Classes {
-				fanOut += len(classes)
-			}
-		} else {
-			fanOut = m.GetNOutputs()
+	X := base.ToDense(Xmatrix).RawMatrix()
+	nTargets := len(m.Intercept)
+	Z := blas64.General{Rows: X.Rows, Cols: nTargets, Stride: nTargets, Data: make([]float64, X.Rows*nTargets)}
+	blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, X, m.Coef, 0, Z)
+	addIntercepts64(Z, m.Intercept)
+	logregActivation[m.OutActivation](Z)
+	if m.binary {
+		// probabilities of the negative and positive classes
+		P := blas64.General{Rows: X.Rows, Cols: 2, Stride: 2, Data: make([]float64, X.Rows*2)}
+		for i := 0; i < X.Rows; i++ {
+			P.Data[2*i], P.Data[2*i+1] = 1-Z.Data[i], Z.Data[i]
+		}
+		Z = P
+	} else if m.lb != nil && m.OutActivation == "logistic" && nTargets > 1 {
+		// one-vs-rest probabilities are normalized
+		for i, pos := 0, 0; i < Z.Rows; i, pos = i+1, pos+Z.Stride {
+			floats.Scale(1/floats.Sum(Z.Data[pos:pos+Z.Cols]), Z.Data[pos:pos+Z.Cols])
 		}
-		Y = mat.NewDense(X.Rows, fanOut, nil)
 	}
-
-	// # Initialize layer
-	activations := []blas64.General{X, Y.RawMatrix()}
-	// # forward propagate
-	m.forwardPass(activations)
+	Y := &mat.Dense{}
+	Y.SetRawMatrix(Z)
 	return base.FromDense(Ymutable, Y)
 }
 
@@ -530,11 +467,3 @@ func isBinarized(Y *mat.Dense) bool {
 	}
 	return true
 }
-
-/*
-func rowSlice(X blas64.General, i, k int) blas64.General {
-	var tmp mat.Dense
-	(&tmp).SetRawMatrix(X)
-	return tmp.Slice(i, k, 0, X.Cols).(*mat.Dense).RawMatrix()
-}
-*/
@@ -0,0 +1,174 @@
+package linearmodel
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LogisticRegressionCV is a LogisticRegression whose inverse regularization strength C is chosen among Cs (default 10
+// values in a logarithmic scale between 1e-4 and 1e4) by stratified cross-validation on CV folds (default 5).
+// samples of each class are dealt to the folds in order, or in a random order if RandomState is set.
+// for each fold, the regularization path is fitted with increasing Cs, each fit starting from the previous solution.
+// the folds are processed in NJobs goroutines (default runtime.NumCPU()).
+// the score is Scorer if not nil, else the accuracy. Scores holds the mean score for each of Cs.
+// the best C (the lowest one on ties) is set in C and Alpha=1/C, then the model is refitted on all samples
+type LogisticRegressionCV struct {
+	LogisticRegression
+	Cs     []float64
+	CV     int
+	Scorer func(Ytrue, Ypred mat.Matrix) float64
+	NJobs  int
+
+	C      float64
+	Scores []float64
+}
+
+var _ base.Predicter = &LogisticRegressionCV{}
+
+// NewLogisticRegressionCV returns a *LogisticRegressionCV with the defaults of NewLogisticRegression
+func NewLogisticRegressionCV() *LogisticRegressionCV {
+	Cs := make([]float64, 10)
+	floats.LogSpan(Cs, 1e-4, 1e4)
+	return &LogisticRegressionCV{LogisticRegression: *NewLogisticRegression(), Cs: Cs, CV: 5}
+}
+
+// PredicterClone ...
+func (m *LogisticRegressionCV) PredicterClone() base.Predicter {
+	clone := *m
+	clone.LogisticRegression = *m.LogisticRegression.PredicterClone().(*LogisticRegression)
+	return &clone
+}
+
+// Fit chooses C by cross-validation, then fits the model with the best C on all samples
+func (m *LogisticRegressionCV) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	if len(m.Cs) == 0 {
+		log.Panic("Cs must not be empty.")
+	}
+	nFolds := m.CV
+	if nFolds == 0 {
+		nFolds = 5
+	}
+	if nFolds < 2 {
+		log.Panicf("CV must be >= 2, got %d.", m.CV)
+	}
+	// Cs are fitted in increasing order
+	order := make([]int, len(m.Cs))
+	for c := range order {
+		if m.Cs[c] <= 0 {
+			log.Panicf("Cs must be > 0, got %g.", m.Cs[c])
+		}
+		order[c] = c
+	}
+	sort.SliceStable(order, func(a, b int) bool { return m.Cs[order[a]] < m.Cs[order[b]] })
+
+	folds := m.stratifiedFolds(Y, nFolds)
+	foldScores := make([][]float64, nFolds)
+	base.Parallelize(m.NJobs, nFolds, func(th, start, end int) {
+		for fold := start; fold < end; fold++ {
+			var train, test []int
+			for i, f := range folds {
+				if f == fold {
+					test = append(test, i)
+				} else {
+					train = append(train, i)
+				}
+			}
+			Xtrain, Ytrain, Xtest, Ytest := rows(X, train), rows(Y, train), rows(X, test), rows(Y, test)
+			estimator := m.LogisticRegression.PredicterClone().(*LogisticRegression)
+			estimator.WarmStart, estimator.packedParameters = true, nil
+			if m.SampleWeight != nil {
+				estimator.SampleWeight = make([]float64, len(train))
+				for r, i := range train {
+					estimator.SampleWeight[r] = m.SampleWeight[i]
+				}
+			}
+			foldScores[fold] = make([]float64, len(m.Cs))
+			for _, c := range order {
+				estimator.Alpha = 1 / m.Cs[c]
+				estimator.Fit(Xtrain, Ytrain)
+				if m.Scorer == nil {
+					foldScores[fold][c] = estimator.Score(Xtest, Ytest)
+					continue
+				}
+				Ypred := mat.NewDense(len(test), estimator.GetNOutputs(), nil)
+				estimator.Predict(Xtest, Ypred)
+				foldScores[fold][c] = m.Scorer(Ytest, Ypred)
+			}
+		}
+	})
+	m.Scores = make([]float64, len(m.Cs))
+	for _, scores := range foldScores {
+		floats.AddScaled(m.Scores, 1/float64(nFolds), scores)
+	}
+	best := order[0]
+	for _, c := range order {
+		if m.Scores[c] > m.Scores[best] {
+			best = c
+		}
+	}
+	m.C = m.Cs[best]
+	m.Alpha = 1 / m.C
+	m.LogisticRegression.Fit(X, Y)
+	return m
+}
+
+// stratifiedFolds returns the fold of each sample. the class of a sample is its row of Y
+func (m *LogisticRegressionCV) stratifiedFolds(Y *mat.Dense, nFolds int) []int {
+	nSamples, _ := Y.Dims()
+	if nSamples < nFolds {
+		log.Panicf("cannot split %d samples into %d folds.", nSamples, nFolds)
+	}
+	classes := make(map[string][]int)
+	var keys []string
+	for i := 0; i < nSamples; i++ {
+		key := fmt.Sprint(Y.RawRowView(i))
+		if classes[key] == nil {
+			keys = append(keys, key)
+		}
+		classes[key] = append(classes[key], i)
+	}
+	var perm func(int) []int
+	if m.RandomState != base.RandomState(nil) {
+		source := m.RandomState
+		if sc, ok := source.(base.SourceCloner); ok {
+			source = sc.SourceClone()
+		}
+		perm = rand.New(source).Perm
+	}
+	folds := make([]int, nSamples)
+	next := 0
+	for _, key := range keys {
+		samples := classes[key]
+		if perm != nil {
+			shuffled := make([]int, len(samples))
+			for r, p := range perm(len(samples)) {
+				shuffled[r] = samples[p]
+			}
+			samples = shuffled
+		}
+		// the next class starts at the next fold, so that fold sizes differ by at most one
+		for _, i := range samples {
+			folds[i] = next
+			next = (next + 1) % nFolds
+		}
+	}
+	return folds
+}
+
+// rows returns the rows of X with given indices
+func rows(X *mat.Dense, indices []int) *mat.Dense {
+	_, nCols := X.Dims()
+	Xout := mat.NewDense(len(indices), nCols, nil)
+	for r, i := range indices {
+		Xout.SetRow(r, X.RawRowView(i))
+	}
+	return Xout
+}
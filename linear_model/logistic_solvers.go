@@ -0,0 +1,332 @@
+package linearmodel
+
+import (
+	"log"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+)
+
+// logisticProblem is the objective minimized by LogisticRegression:
+// sum(sw*logloss)/nSamples + l2/2*|W|^2 + l1*|W|_1.
+// parameters w are packed as [intercept | coef], coef being nFeatures x nTargets.
+// if interceptScale>0, the intercept is penalized like the weight of a feature of value interceptScale
+type logisticProblem struct {
+	X, Y           blas64.General
+	sw             []float64
+	softmax        bool
+	fitIntercept   bool
+	l2, l1         float64
+	interceptScale float64
+
+	// P holds the probabilities for the parameters of the last call to probas
+	P blas64.General
+}
+
+func (p *logisticProblem) split(w []float64) (intercept []float64, coef blas64.General) {
+	nTargets := p.Y.Cols
+	return w[:nTargets], blas64.General{Rows: p.X.Cols, Cols: nTargets, Stride: nTargets, Data: w[nTargets:]}
+}
+
+func (p *logisticProblem) weight(i int) float64 {
+	if p.sw == nil {
+		return 1
+	}
+	return p.sw[i]
+}
+
+// probas computes P for parameters w
+func (p *logisticProblem) probas(w []float64) {
+	intercept, coef := p.split(w)
+	blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, p.X, coef, 0, p.P)
+	if p.fitIntercept {
+		addIntercepts64(p.P, intercept)
+	}
+	if p.softmax {
+		logregActivation["softmax"](p.P)
+	} else {
+		logregActivation["logistic"](p.P)
+	}
+}
+
+// lossGrad returns the smooth part of the objective (without the l1 penalty) at w, and fills grad if not nil
+func (p *logisticProblem) lossGrad(w, grad []float64) float64 {
+	p.probas(w)
+	nSamples, nTargets := p.Y.Rows, p.Y.Cols
+	hmin, hmax := math.Nextafter(0, 1), math.Nextafter(1, 0)
+	var deltas blas64.General
+	if grad != nil {
+		deltas = blas64.General{Rows: nSamples, Cols: nTargets, Stride: nTargets, Data: make([]float64, nSamples*nTargets)}
+	}
+	loss := 0.
+	for i := 0; i < nSamples; i++ {
+		sw := p.weight(i)
+		h, y := p.P.Data[i*p.P.Stride:i*p.P.Stride+nTargets], p.Y.Data[i*p.Y.Stride:i*p.Y.Stride+nTargets]
+		for k, hval := range h {
+			hval = math.Max(hmin, math.Min(hmax, hval))
+			if p.softmax {
+				if y[k] != 0 {
+					loss -= sw * y[k] * math.Log(hval)
+				}
+			} else {
+				loss -= sw * (y[k]*math.Log(hval) + (1-y[k])*math.Log1p(-hval))
+			}
+			if grad != nil {
+				deltas.Data[i*nTargets+k] = sw * (h[k] - y[k])
+			}
+		}
+	}
+	loss /= float64(nSamples)
+	intercept, coef := p.split(w)
+	loss += .5 * p.l2 * floats.Dot(coef.Data, coef.Data)
+	if p.interceptScale > 0 {
+		loss += .5 * p.l2 * floats.Dot(intercept, intercept) / (p.interceptScale * p.interceptScale)
+	}
+	if grad != nil {
+		gIntercept, gCoef := p.split(grad)
+		blas64.Gemm(blas.Trans, blas.NoTrans, 1/float64(nSamples), p.X, deltas, 0, gCoef)
+		floats.AddScaled(gCoef.Data, p.l2, coef.Data)
+		p.interceptGrad(deltas, intercept, gIntercept)
+	}
+	return loss
+}
+
+// interceptGrad fills gIntercept with the mean of the rows of deltas and the gradient of the l2 penalty
+func (p *logisticProblem) interceptGrad(deltas blas64.General, intercept, gIntercept []float64) {
+	for k := range gIntercept {
+		gIntercept[k] = 0
+	}
+	if !p.fitIntercept {
+		return
+	}
+	for i := 0; i < deltas.Rows; i++ {
+		floats.Add(gIntercept, deltas.Data[i*deltas.Stride:i*deltas.Stride+deltas.Cols])
+	}
+	floats.Scale(1/float64(deltas.Rows), gIntercept)
+	if p.interceptScale > 0 {
+		floats.AddScaled(gIntercept, p.l2/(p.interceptScale*p.interceptScale), intercept)
+	}
+}
+
+// hessVec fills hv with the product of the hessian of the smooth objective by v, at the parameters of the last call to
+// lossGrad
+func (p *logisticProblem) hessVec(v, hv []float64) {
+	nSamples, nTargets := p.Y.Rows, p.Y.Cols
+	vIntercept, vCoef := p.split(v)
+	// R = X.V + v0, then D = dP/dZ . R
+	D := blas64.General{Rows: nSamples, Cols: nTargets, Stride: nTargets, Data: make([]float64, nSamples*nTargets)}
+	blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, p.X, vCoef, 0, D)
+	if p.fitIntercept {
+		addIntercepts64(D, vIntercept)
+	}
+	for i := 0; i < nSamples; i++ {
+		sw := p.weight(i)
+		h, r := p.P.Data[i*p.P.Stride:i*p.P.Stride+nTargets], D.Data[i*nTargets:(i+1)*nTargets]
+		if p.softmax {
+			hr := floats.Dot(h, r)
+			for k := range r {
+				r[k] = sw * h[k] * (r[k] - hr)
+			}
+		} else {
+			for k := range r {
+				r[k] *= sw * h[k] * (1 - h[k])
+			}
+		}
+	}
+	hvIntercept, hvCoef := p.split(hv)
+	blas64.Gemm(blas.Trans, blas.NoTrans, 1/float64(nSamples), p.X, D, 0, hvCoef)
+	floats.AddScaled(hvCoef.Data, p.l2, vCoef.Data)
+	p.interceptGrad(D, vIntercept, hvIntercept)
+}
+
+// l1Penalty returns the l1 part of the objective at w
+func (p *logisticProblem) l1Penalty(w []float64) float64 {
+	if p.l1 == 0 {
+		return 0
+	}
+	intercept, coef := p.split(w)
+	penalty := floats.Norm(coef.Data, 1)
+	if p.interceptScale > 0 {
+		penalty += floats.Norm(intercept, 1) / p.interceptScale
+	}
+	return p.l1 * penalty
+}
+
+// fitNewtonCG minimizes the objective by a truncated newton method: the newton direction is approximated by conjugate
+// gradient iterations using hessian-vector products, then a backtracking line search is done
+func (m *LogisticRegression) fitNewtonCG(p *logisticProblem) {
+	w := m.packedParameters
+	n := len(w)
+	grad, dir, wNew := make([]float64, n), make([]float64, n), make([]float64, n)
+	converged := false
+	for m.NIter = 0; m.NIter < m.MaxIter; m.NIter++ {
+		loss := p.lossGrad(w, grad)
+		m.LossCurve = append(m.LossCurve, loss)
+		m.BestLoss = math.Min(m.BestLoss, loss)
+		if floats.Norm(grad, math.Inf(1)) <= m.Tol {
+			converged = true
+			break
+		}
+		absGrad := floats.Norm(grad, 1)
+		p.conjugateGradient(grad, dir, math.Min(.5, math.Sqrt(absGrad))*absGrad)
+		slope := floats.Dot(grad, dir)
+		if slope >= 0 {
+			// not a descent direction: use the gradient
+			floats.ScaleTo(dir, -1, grad)
+			slope = -floats.Dot(grad, grad)
+		}
+		step := 1.
+		for ls := 0; ls < 50; ls++ {
+			floats.AddScaledTo(wNew, w, step, dir)
+			if p.lossGrad(wNew, nil) <= loss+1e-4*step*slope {
+				break
+			}
+			step /= 2
+		}
+		copy(w, wNew)
+	}
+	if !converged {
+		log.Printf("newton-cg solver: Maximum iterations (%d) reached and the optimization hasn't converged yet.\n", m.MaxIter)
+	}
+}
+
+// conjugateGradient fills x with an approximate solution of H.x = -grad, stopping when the l1 norm of the residual is
+// lower than tol
+func (p *logisticProblem) conjugateGradient(grad, x []float64, tol float64) {
+	n := len(grad)
+	for j := range x {
+		x[j] = 0
+	}
+	r, d, hd := append([]float64(nil), grad...), make([]float64, n), make([]float64, n)
+	floats.ScaleTo(d, -1, r)
+	rr := floats.Dot(r, r)
+	for iter := 0; iter < 200 && floats.Norm(r, 1) > tol; iter++ {
+		p.hessVec(d, hd)
+		curvature := floats.Dot(d, hd)
+		if curvature <= 3*math.Nextafter(1, 2)-3 {
+			if iter == 0 {
+				floats.ScaleTo(x, -1, grad)
+			}
+			return
+		}
+		alpha := rr / curvature
+		floats.AddScaled(x, alpha, d)
+		floats.AddScaled(r, alpha, hd)
+		rrNew := floats.Dot(r, r)
+		for j := range d {
+			d[j] = -r[j] + rrNew/rr*d[j]
+		}
+		rr = rrNew
+	}
+}
+
+// fitSaga minimizes the objective by the SAGA incremental gradient method, with a proximal step for the l1 penalty.
+// it stops when the maximum change of the weights during an epoch, relative to the maximum weight, is lower than Tol
+func (m *LogisticRegression) fitSaga(p *logisticProblem) {
+	nSamples, nFeatures, nTargets := p.X.Rows, p.X.Cols, p.Y.Cols
+	w := m.packedParameters
+	intercept, coef := p.split(w)
+
+	// step size from the lipschitz constant of the gradient of the loss of a sample
+	maxSquaredSum, maxWeight := 0., 0.
+	for i := 0; i < nSamples; i++ {
+		x := p.X.Data[i*p.X.Stride : i*p.X.Stride+nFeatures]
+		maxSquaredSum = math.Max(maxSquaredSum, floats.Dot(x, x))
+		maxWeight = math.Max(maxWeight, p.weight(i))
+	}
+	if p.fitIntercept {
+		maxSquaredSum++
+	}
+	L := .25*maxSquaredSum*maxWeight + p.l2
+	step := 1 / (2*L + math.Min(2*float64(nSamples)*p.l2, L))
+	interceptL2 := 0.
+	if p.interceptScale > 0 {
+		interceptL2 = p.l2 / (p.interceptScale * p.interceptScale)
+	}
+
+	// the gradient of the loss of each sample with respect to its predictions, and the mean gradient
+	memory := make([]float64, nSamples*nTargets)
+	avgIntercept, avgCoef := make([]float64, nTargets), make([]float64, nFeatures*nTargets)
+	z, diff, wPrev := make([]float64, nTargets), make([]float64, nTargets), make([]float64, len(w))
+	source := m.RandomState
+	if source == base.RandomState(nil) {
+		source = base.NewSource(0)
+	}
+	intn := rand.New(source).Intn
+	softThreshold := func(v, t float64) float64 {
+		if v > t {
+			return v - t
+		} else if v < -t {
+			return v + t
+		}
+		return 0
+	}
+	converged := false
+	for m.NIter = 0; m.NIter < m.MaxIter; m.NIter++ {
+		copy(wPrev, w)
+		for iter := 0; iter < nSamples; iter++ {
+			i := intn(nSamples)
+			x, y := p.X.Data[i*p.X.Stride:i*p.X.Stride+nFeatures], p.Y.Data[i*p.Y.Stride:i*p.Y.Stride+nTargets]
+			for k := range z {
+				z[k] = 0
+				if p.fitIntercept {
+					z[k] = intercept[k]
+				}
+			}
+			for j, xj := range x {
+				if xj != 0 {
+					floats.AddScaled(z, xj, coef.Data[j*nTargets:(j+1)*nTargets])
+				}
+			}
+			zg := blas64.General{Rows: 1, Cols: nTargets, Stride: nTargets, Data: z}
+			if p.softmax {
+				logregActivation["softmax"](zg)
+			} else {
+				logregActivation["logistic"](zg)
+			}
+			sw := p.weight(i)
+			for k := range z {
+				g := sw * (z[k] - y[k])
+				diff[k] = g - memory[i*nTargets+k]
+				memory[i*nTargets+k] = g
+			}
+			if p.fitIntercept {
+				for k := range intercept {
+					intercept[k] -= step * (diff[k] + avgIntercept[k] + interceptL2*intercept[k])
+					avgIntercept[k] += diff[k] / float64(nSamples)
+					if p.interceptScale > 0 {
+						intercept[k] = softThreshold(intercept[k], step*p.l1/p.interceptScale)
+					}
+				}
+			}
+			for j, xj := range x {
+				for k := range diff {
+					c := j*nTargets + k
+					coef.Data[c] -= step * (xj*diff[k] + avgCoef[c] + p.l2*coef.Data[c])
+					avgCoef[c] += xj * diff[k] / float64(nSamples)
+					if p.l1 > 0 {
+						coef.Data[c] = softThreshold(coef.Data[c], step*p.l1)
+					}
+				}
+			}
+		}
+		maxChange, maxW := 0., 0.
+		for c, v := range w {
+			maxChange = math.Max(maxChange, math.Abs(v-wPrev[c]))
+			maxW = math.Max(maxW, math.Abs(v))
+		}
+		if maxChange <= m.Tol*maxW || maxW == 0 {
+			converged = true
+			m.NIter++
+			break
+		}
+	}
+	if !converged {
+		log.Printf("saga solver: Maximum iterations (%d) reached and the optimization hasn't converged yet.\n", m.MaxIter)
+	}
+}
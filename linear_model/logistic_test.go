@@ -12,6 +12,7 @@ import (
 
 	"github.com/RobinRCM/sklearn/base"
 	"github.com/RobinRCM/sklearn/datasets"
+	"github.com/RobinRCM/sklearn/preprocessing"
 	"gonum.org/v1/gonum/diff/fd"
 	"gonum.org/v1/gonum/mat"
 	"gonum.org/v1/gonum/optimize"
@@ -144,3 +145,95 @@ func ExampleLogisticRegression() {
 	// Output:
 	// ok
 }
+
+func ExampleLogisticRegression_solvers() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	for _, multiClass := range []string{"multinomial", "ovr"} {
+		for _, solver := range []string{"lbfgs", "newton-cg", "saga"} {
+			regr := NewLogisticRegression()
+			regr.Solver, regr.MultiClass = solver, multiClass
+			regr.Fit(X, ds.Y)
+			fmt.Printf("%-11s %-9s loss:%.4f accuracy:%.3f\n", multiClass, solver, regr.Loss, regr.Score(X, ds.Y))
+		}
+	}
+	// Output:
+	// multinomial lbfgs     loss:0.2094 accuracy:0.973
+	// multinomial newton-cg loss:0.2094 accuracy:0.973
+	// multinomial saga      loss:0.2094 accuracy:0.973
+	// ovr         lbfgs     loss:0.7208 accuracy:0.947
+	// ovr         newton-cg loss:0.7208 accuracy:0.947
+	// ovr         saga      loss:0.7209 accuracy:0.947
+}
+
+func ExampleLogisticRegression_penalties() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	for _, penalty := range []string{"l2", "elasticnet", "l1"} {
+		for _, C := range []float64{1, .1} {
+			regr := NewLogisticRegression()
+			regr.Solver, regr.Penalty, regr.L1Ratio, regr.Alpha = "saga", penalty, .5, 1/C
+			regr.MaxIter = 1000
+			regr.Fit(X, ds.Y)
+			zeros := 0
+			for _, v := range regr.Coef.Data {
+				if v == 0 {
+					zeros++
+				}
+			}
+			fmt.Printf("%-10s C=%g zero coefs:%2d accuracy:%.3f\n", penalty, C, zeros, regr.Score(X, ds.Y))
+		}
+	}
+	// Output:
+	// l2         C=1 zero coefs: 0 accuracy:0.973
+	// l2         C=0.1 zero coefs: 0 accuracy:0.927
+	// elasticnet C=1 zero coefs: 3 accuracy:0.973
+	// elasticnet C=0.1 zero coefs: 4 accuracy:0.940
+	// l1         C=1 zero coefs: 5 accuracy:0.973
+	// l1         C=0.1 zero coefs: 8 accuracy:0.947
+}
+
+func ExampleLogisticRegression_classWeight() {
+	// predict virginica (1/3 of the samples) from the sepal length
+	ds := datasets.LoadIris()
+	nSamples, _ := ds.X.Dims()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X.Slice(0, nSamples, 0, 1), nil)
+	Y := mat.NewDense(nSamples, 1, nil)
+	for i := 0; i < nSamples; i++ {
+		Y.Set(i, 0, 1)
+		if ds.Y.At(i, 0) == 2 {
+			Y.Set(i, 0, 2)
+		}
+	}
+	for _, classWeight := range []interface{}{nil, "balanced"} {
+		regr := NewLogisticRegression()
+		regr.ClassWeight = classWeight
+		regr.Fit(X, Y)
+		Ypred := regr.Predict(X, nil)
+		truePositives, positives := 0, 0
+		for i := 0; i < nSamples; i++ {
+			if Ypred.At(i, 0) == 2 {
+				positives++
+				if Y.At(i, 0) == 2 {
+					truePositives++
+				}
+			}
+		}
+		fmt.Printf("ClassWeight:%-8v predicted virginica:%d recall:%.2f\n", classWeight, positives, float64(truePositives)/50)
+	}
+	// Output:
+	// ClassWeight:<nil>    predicted virginica:42 recall:0.62
+	// ClassWeight:balanced predicted virginica:61 recall:0.82
+}
+
+func ExampleLogisticRegressionCV() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	regr := NewLogisticRegressionCV()
+	regr.Cs = []float64{.01, .1, 1, 10, 100}
+	regr.Fit(X, ds.Y)
+	fmt.Printf("scores:%.3f\nC:%g accuracy:%.3f\n", regr.Scores, regr.C, regr.Score(X, ds.Y))
+	// Output:
+	// scores:[0.880 0.907 0.953 0.960 0.973]
+	// C:100 accuracy:0.987
+}
@@ -0,0 +1,620 @@
+package linearmodel
+
+import (
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/metrics"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// sgdLoss is a loss of a decision value p for a label y in {-1,1}, with its derivative with respect to p
+type sgdLoss interface {
+	loss(p, y float64) float64
+	dloss(p, y float64) float64
+}
+
+// sgdHinge is the hinge loss max(0,threshold-p*y). threshold 0 is the perceptron loss
+type sgdHinge struct{ threshold float64 }
+
+func (l sgdHinge) loss(p, y float64) float64 { return math.Max(0, l.threshold-p*y) }
+
+func (l sgdHinge) dloss(p, y float64) float64 {
+	if p*y <= l.threshold {
+		return -y
+	}
+	return 0
+}
+
+// sgdSquaredHinge is the squared hinge loss max(0,threshold-p*y)^2
+type sgdSquaredHinge struct{ threshold float64 }
+
+func (l sgdSquaredHinge) loss(p, y float64) float64 {
+	z := math.Max(0, l.threshold-p*y)
+	return z * z
+}
+
+func (l sgdSquaredHinge) dloss(p, y float64) float64 {
+	if z := l.threshold - p*y; z > 0 {
+		return -2 * y * z
+	}
+	return 0
+}
+
+// sgdLog is the logistic loss log(1+exp(-p*y))
+type sgdLog struct{}
+
+func (sgdLog) loss(p, y float64) float64 {
+	z := p * y
+	switch {
+	case z > 18:
+		return math.Exp(-z)
+	case z < -18:
+		return -z
+	}
+	return math.Log1p(math.Exp(-z))
+}
+
+func (sgdLog) dloss(p, y float64) float64 {
+	z := p * y
+	switch {
+	case z > 18:
+		return -y * math.Exp(-z)
+	case z < -18:
+		return -y
+	}
+	return -y / (math.Exp(z) + 1)
+}
+
+// sgdModifiedHuber is the modified huber loss: max(0,1-p*y)^2 if p*y>=-1, else -4*p*y
+type sgdModifiedHuber struct{}
+
+func (sgdModifiedHuber) loss(p, y float64) float64 {
+	z := p * y
+	switch {
+	case z >= 1:
+		return 0
+	case z >= -1:
+		return (1 - z) * (1 - z)
+	}
+	return -4 * z
+}
+
+func (sgdModifiedHuber) dloss(p, y float64) float64 {
+	z := p * y
+	switch {
+	case z >= 1:
+		return 0
+	case z >= -1:
+		return -2 * (1 - z) * y
+	}
+	return -4 * y
+}
+
+// sgdLosses are the losses of SGDClassifier
+var sgdLosses = map[string]sgdLoss{
+	"hinge":          sgdHinge{threshold: 1},
+	"perceptron":     sgdHinge{threshold: 0},
+	"squared_hinge":  sgdSquaredHinge{threshold: 1},
+	"log":            sgdLog{},
+	"modified_huber": sgdModifiedHuber{},
+}
+
+// sgdWeights are the weights of a binary classifier, and their averages for averaged SGD
+type sgdWeights struct {
+	coef, averageCoef           []float64
+	intercept, averageIntercept float64
+}
+
+// SGDClassifier is a linear classifier fitted by stochastic gradient descent, one sample at a time.
+// Loss is "hinge" (default, linear SVM), "log" (logistic regression), "modified_huber", "squared_hinge" or
+// "perceptron". Penalty is "l2" (default), "l1", "elasticnet" (l1 part L1Ratio) or "none", with strength Alpha.
+// LearningRate is "optimal" (default, eta=1/(Alpha*(t+t0))), "constant" (eta=Eta0), "invscaling"
+// (eta=Eta0/t^PowerT) or "adaptive" (eta=Eta0, divided by 5 when the loss stops improving).
+// an epoch is a pass over the samples, shuffled if Shuffle. Fit stops after MaxIter epochs, or when the training
+// loss (or the validation accuracy if EarlyStopping) does not improve by Tol for NIterNoChange epochs. no stopping
+// criterion is used if Tol<0.
+// if EarlyStopping, a ValidationFraction of the samples is set aside to compute the validation accuracy.
+// if Average>0, Coef and Intercept are the averages of the weights after Average samples were seen.
+// Y is a column of labels. more than 2 classes are fitted as one-vs-rest binary classifiers in NJobs goroutines
+// (default runtime.NumCPU()).
+// ClassWeight is nil, "balanced" or a map[float64]float64 of weights by class label. it multiplies SampleWeight.
+// if WarmStart, Fit starts from the previous solution if it has the same classes and features.
+type SGDClassifier struct {
+	Loss               string
+	Penalty            string
+	Alpha              float64
+	L1Ratio            float64
+	FitIntercept       bool
+	MaxIter            int
+	Tol                float64
+	Shuffle            bool
+	RandomState        base.RandomState
+	LearningRate       string
+	Eta0               float64
+	PowerT             float64
+	EarlyStopping      bool
+	ValidationFraction float64
+	NIterNoChange      int
+	ClassWeight        interface{}
+	Average            int
+	WarmStart          bool
+	NJobs              int
+	SampleWeight       []float64
+
+	Classes   []float64
+	Coef      *mat.Dense
+	Intercept []float64
+	NIter     int
+	T         float64
+
+	weights []sgdWeights
+}
+
+var _ base.Predicter = &SGDClassifier{}
+
+// NewSGDClassifier returns a *SGDClassifier with defaults: hinge loss, l2 penalty, Alpha=1e-4, L1Ratio=.15,
+// FitIntercept, MaxIter=1000, Tol=1e-3, Shuffle, optimal LearningRate, PowerT=.5, ValidationFraction=.1,
+// NIterNoChange=5
+func NewSGDClassifier() *SGDClassifier {
+	return &SGDClassifier{
+		Loss:               "hinge",
+		Penalty:            "l2",
+		Alpha:              1e-4,
+		L1Ratio:            .15,
+		FitIntercept:       true,
+		MaxIter:            1000,
+		Tol:                1e-3,
+		Shuffle:            true,
+		LearningRate:       "optimal",
+		PowerT:             .5,
+		ValidationFraction: .1,
+		NIterNoChange:      5,
+	}
+}
+
+// PredicterClone ...
+func (m *SGDClassifier) PredicterClone() base.Predicter {
+	clone := *m
+	if sc, ok := m.RandomState.(base.SourceCloner); ok {
+		clone.RandomState = sc.SourceClone()
+	}
+	if m.Coef != nil {
+		clone.Coef = mat.DenseCopyOf(m.Coef)
+		clone.Intercept = append([]float64(nil), m.Intercept...)
+	}
+	clone.weights = make([]sgdWeights, len(m.weights))
+	for k, w := range m.weights {
+		clone.weights[k] = w
+		clone.weights[k].coef = append([]float64(nil), w.coef...)
+		clone.weights[k].averageCoef = append([]float64(nil), w.averageCoef...)
+	}
+	return &clone
+}
+
+// IsClassifier returns true for SGDClassifier
+func (*SGDClassifier) IsClassifier() bool { return true }
+
+// GetNOutputs returns 1: Y is a column of labels
+func (*SGDClassifier) GetNOutputs() int { return 1 }
+
+// Fit fits Coef and Intercept, starting from zero weights unless WarmStart
+func (m *SGDClassifier) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	m.validateHyperparameters()
+	classes := uniqueLabels(Y)
+	if len(classes) < 2 {
+		log.Panicf("SGDClassifier needs at least 2 classes, got %d.", len(classes))
+	}
+	_, nFeatures := X.Dims()
+	if !m.WarmStart || !floats.Equal(classes, m.Classes) || len(m.weights) == 0 || len(m.weights[0].coef) != nFeatures {
+		m.weights = nil
+	}
+	m.Classes = classes
+	m.T = 1
+	m.fit(X, Y, m.MaxIter, computeClassWeight(m.ClassWeight, Y), false)
+	return m
+}
+
+// PartialFit does one epoch of SGD over the samples of X, Y. classes, the labels of all the classes, must be given at
+// the first call
+func (m *SGDClassifier) PartialFit(Xmatrix, Ymatrix mat.Matrix, classes []float64) base.Fiter {
+	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
+	m.validateHyperparameters()
+	if m.ClassWeight == "balanced" {
+		log.Panic("ClassWeight \"balanced\" is not supported by PartialFit, pass the weights as a map.")
+	}
+	if classes != nil {
+		classes = uniqueLabels(mat.NewDense(len(classes), 1, append([]float64(nil), classes...)))
+	}
+	if m.weights == nil {
+		if len(classes) < 2 {
+			log.Panicf("classes must be given at the first call to PartialFit, with at least 2 classes, got %v.", classes)
+		}
+		m.Classes = classes
+		m.T = 1
+	} else if classes != nil && !floats.Equal(classes, m.Classes) {
+		log.Panicf("classes %v differ from the previous ones %v.", classes, m.Classes)
+	}
+	if _, nFeatures := X.Dims(); m.weights != nil && len(m.weights[0].coef) != nFeatures {
+		log.Panicf("X has %d features, expected %d.", nFeatures, len(m.weights[0].coef))
+	}
+	nSamples, _ := Y.Dims()
+	for i := 0; i < nSamples; i++ {
+		if k := sort.SearchFloat64s(m.Classes, Y.At(i, 0)); k == len(m.Classes) || m.Classes[k] != Y.At(i, 0) {
+			log.Panicf("label %g is not in classes %v.", Y.At(i, 0), m.Classes)
+		}
+	}
+	m.fit(X, Y, 1, computeClassWeight(m.ClassWeight, Y), true)
+	return m
+}
+
+// uniqueLabels returns the sorted labels of the first column of Y
+func uniqueLabels(Y *mat.Dense) []float64 {
+	nSamples, nOutputs := Y.Dims()
+	if nOutputs != 1 {
+		log.Panicf("Y must be a column of labels, got %d columns.", nOutputs)
+	}
+	var labels []float64
+	seen := make(map[float64]bool)
+	for i := 0; i < nSamples; i++ {
+		if v := Y.At(i, 0); !seen[v] {
+			seen[v] = true
+			labels = append(labels, v)
+		}
+	}
+	sort.Float64s(labels)
+	return labels
+}
+
+func (m *SGDClassifier) validateHyperparameters() {
+	if m.Loss == "" {
+		m.Loss = "hinge"
+	}
+	if m.Penalty == "" {
+		m.Penalty = "l2"
+	}
+	if m.LearningRate == "" {
+		m.LearningRate = "optimal"
+	}
+	if _, ok := sgdLosses[m.Loss]; !ok {
+		log.Panicf("unknown loss %s.", m.Loss)
+	}
+	switch m.Penalty {
+	case "l2", "l1", "elasticnet", "none":
+	default:
+		log.Panicf("unknown penalty %s.", m.Penalty)
+	}
+	if m.L1Ratio < 0 || m.L1Ratio > 1 {
+		log.Panicf("L1Ratio must be in [0,1], got %g.", m.L1Ratio)
+	}
+	if m.Alpha < 0 {
+		log.Panicf("alpha must be >= 0, got %g.", m.Alpha)
+	}
+	switch m.LearningRate {
+	case "optimal":
+		if m.Alpha == 0 {
+			log.Panic("alpha must be > 0 with the optimal learning rate.")
+		}
+	case "constant", "invscaling", "adaptive":
+		if m.Eta0 <= 0 {
+			log.Panicf("Eta0 must be > 0 with the %s learning rate, got %g.", m.LearningRate, m.Eta0)
+		}
+	default:
+		log.Panicf("unknown learning rate %s.", m.LearningRate)
+	}
+	if m.MaxIter <= 0 {
+		log.Panicf("maxIter must be > 0, got %d.", m.MaxIter)
+	}
+	if m.NIterNoChange <= 0 {
+		log.Panicf("nIterNoChange must be > 0, got %d.", m.NIterNoChange)
+	}
+	if m.EarlyStopping && (m.ValidationFraction <= 0 || m.ValidationFraction >= 1) {
+		log.Panicf("ValidationFraction must be in (0,1), got %g.", m.ValidationFraction)
+	}
+	if m.Average < 0 {
+		log.Panicf("Average must be >= 0, got %d.", m.Average)
+	}
+	validateClassWeight(m.ClassWeight)
+}
+
+// fit runs at most maxIter epochs of SGD for each binary classifier, then sets Coef and Intercept
+func (m *SGDClassifier) fit(X, Y *mat.Dense, maxIter int, classWeight map[float64]float64, partial bool) {
+	nSamples, nFeatures := X.Dims()
+	nClassifiers := len(m.Classes)
+	if nClassifiers == 2 {
+		nClassifiers = 1
+	}
+	if m.weights == nil {
+		m.weights = make([]sgdWeights, nClassifiers)
+		for k := range m.weights {
+			m.weights[k].coef = make([]float64, nFeatures)
+			if m.Average > 0 {
+				m.weights[k].averageCoef = make([]float64, nFeatures)
+			}
+		}
+	}
+	if m.SampleWeight != nil && len(m.SampleWeight) != nSamples {
+		log.Panicf("SampleWeight has %d elements, expected %d.", len(m.SampleWeight), nSamples)
+	}
+	if m.RandomState == base.RandomState(nil) {
+		m.RandomState = base.NewSource(uint64(time.Now().UnixNano()))
+	}
+	rnd := rand.New(m.RandomState)
+	var validation []bool
+	nTrain := nSamples
+	if m.EarlyStopping && !partial {
+		nValidation := int(math.Ceil(m.ValidationFraction * float64(nSamples)))
+		if nValidation >= nSamples {
+			log.Panicf("ValidationFraction %g leaves no training sample.", m.ValidationFraction)
+		}
+		validation = make([]bool, nSamples)
+		for _, i := range rnd.Perm(nSamples)[:nValidation] {
+			validation[i] = true
+		}
+		nTrain -= nValidation
+	}
+	// each binary classifier has its own random source, so that results do not depend on NJobs
+	seeds := make([]uint64, nClassifiers)
+	for k := range seeds {
+		seeds[k] = rnd.Uint64()
+	}
+	nIters := make([]int, nClassifiers)
+	converged := make([]bool, nClassifiers)
+	base.Parallelize(m.NJobs, nClassifiers, func(th, start, end int) {
+		y, sw := make([]float64, nSamples), make([]float64, nSamples)
+		for k := start; k < end; k++ {
+			// the positive class is the last one for a single classifier
+			positive := m.Classes[k]
+			if nClassifiers == 1 {
+				positive = m.Classes[1]
+			}
+			for i := range y {
+				label := Y.At(i, 0)
+				y[i], sw[i] = -1, 1
+				if label == positive {
+					y[i] = 1
+				}
+				if w, ok := classWeight[label]; ok && (nClassifiers == 1 || label == positive) {
+					sw[i] = w
+				}
+				if m.SampleWeight != nil {
+					sw[i] *= m.SampleWeight[i]
+				}
+			}
+			nIters[k], converged[k] = m.plainSGD(&m.weights[k], X, y, sw, validation, maxIter, base.NewSource(seeds[k]), !partial)
+		}
+	})
+	m.NIter = 0
+	for k, nIter := range nIters {
+		if nIter > m.NIter {
+			m.NIter = nIter
+		}
+		if !partial && m.Tol >= 0 && !converged[k] {
+			log.Printf("SGDClassifier: Maximum iterations (%d) reached and the optimization hasn't converged yet.\n", m.MaxIter)
+			break
+		}
+	}
+	m.T += float64(m.NIter * nTrain)
+
+	m.Coef, m.Intercept = mat.NewDense(nFeatures, nClassifiers, nil), make([]float64, nClassifiers)
+	for k, w := range m.weights {
+		if m.Average > 0 && m.T > float64(m.Average) {
+			m.Coef.SetCol(k, w.averageCoef)
+			m.Intercept[k] = w.averageIntercept
+		} else {
+			m.Coef.SetCol(k, w.coef)
+			m.Intercept[k] = w.intercept
+		}
+	}
+}
+
+// plainSGD runs at most maxIter epochs of SGD on the samples of X not in validation, for labels y in {-1,1} with
+// weights sw. it returns the number of epochs and whether the stopping criterion was met
+func (m *SGDClassifier) plainSGD(w *sgdWeights, X *mat.Dense, y, sw []float64, validation []bool, maxIter int, source base.Source, checkConvergence bool) (nIter int, converged bool) {
+	loss := sgdLosses[m.Loss]
+	nSamples, nFeatures := X.Dims()
+	var train, test []int
+	for i := 0; i < nSamples; i++ {
+		if validation != nil && validation[i] {
+			test = append(test, i)
+		} else {
+			train = append(train, i)
+		}
+	}
+	var l1Ratio, l2Ratio float64
+	switch m.Penalty {
+	case "l2":
+		l2Ratio = 1
+	case "l1":
+		l1Ratio = 1
+	case "elasticnet":
+		l1Ratio, l2Ratio = m.L1Ratio, 1-m.L1Ratio
+	}
+	// the optimal learning rate starts from an heuristic of Leon Bottou
+	optimalInit := 0.
+	if m.LearningRate == "optimal" {
+		typw := math.Sqrt(1 / math.Sqrt(m.Alpha))
+		initialEta0 := typw / math.Max(1, loss.dloss(-typw, 1))
+		optimalInit = 1 / (initialEta0 * m.Alpha)
+	}
+	// u is the l1 penalty each weight should have received, q the one it received (Tsuruoka et al. 2009)
+	var u float64
+	q := make([]float64, nFeatures)
+	const maxDloss = 1e12
+
+	rnd := rand.New(source)
+	t := m.T
+	eta := m.Eta0
+	bestLoss, bestScore, noImprovement := math.Inf(1), math.Inf(-1), 0
+	for nIter < maxIter {
+		if m.Shuffle {
+			rnd.Shuffle(len(train), func(a, b int) { train[a], train[b] = train[b], train[a] })
+		}
+		sumLoss := 0.
+		for _, i := range train {
+			x := X.RawRowView(i)
+			p := floats.Dot(w.coef, x) + w.intercept
+			switch m.LearningRate {
+			case "optimal":
+				eta = 1 / (m.Alpha * (optimalInit + t - 1))
+			case "invscaling":
+				eta = m.Eta0 / math.Pow(t, m.PowerT)
+			}
+			sumLoss += loss.loss(p, y[i])
+			dloss := math.Max(-maxDloss, math.Min(maxDloss, loss.dloss(p, y[i])))
+			update := -eta * dloss * sw[i]
+			if l2Ratio > 0 {
+				floats.Scale(math.Max(0, 1-l2Ratio*eta*m.Alpha), w.coef)
+			}
+			if update != 0 {
+				floats.AddScaled(w.coef, update, x)
+				if m.FitIntercept {
+					w.intercept += update
+				}
+			}
+			if m.Average > 0 && float64(m.Average) <= t {
+				mu := 1 / (t - float64(m.Average) + 1)
+				for j, v := range w.coef {
+					w.averageCoef[j] += mu * (v - w.averageCoef[j])
+				}
+				w.averageIntercept += mu * (w.intercept - w.averageIntercept)
+			}
+			if l1Ratio > 0 {
+				u += l1Ratio * eta * m.Alpha
+				for j, v := range w.coef {
+					if v > 0 {
+						w.coef[j] = math.Max(0, v-(u+q[j]))
+					} else if v < 0 {
+						w.coef[j] = math.Min(0, v+(u-q[j]))
+					}
+					q[j] += w.coef[j] - v
+				}
+			}
+			t++
+		}
+		nIter++
+		if !checkConvergence || m.Tol < 0 {
+			continue
+		}
+		if validation != nil {
+			score := m.validationScore(w, X, y, test, t)
+			if score < bestScore+m.Tol {
+				noImprovement++
+			} else {
+				noImprovement = 0
+			}
+			bestScore = math.Max(bestScore, score)
+		} else {
+			if sumLoss > bestLoss-m.Tol*float64(len(train)) {
+				noImprovement++
+			} else {
+				noImprovement = 0
+			}
+			bestLoss = math.Min(bestLoss, sumLoss)
+		}
+		if noImprovement >= m.NIterNoChange {
+			if m.LearningRate == "adaptive" && eta > 1e-6 {
+				eta /= 5
+				noImprovement = 0
+			} else {
+				converged = true
+				break
+			}
+		}
+	}
+	return
+}
+
+// validationScore returns the accuracy of the binary classifier w on the samples test, with the averaged weights if
+// averaging has started at time step t
+func (m *SGDClassifier) validationScore(w *sgdWeights, X *mat.Dense, y []float64, test []int, t float64) float64 {
+	coef, intercept := w.coef, w.intercept
+	if m.Average > 0 && t > float64(m.Average) {
+		coef, intercept = w.averageCoef, w.averageIntercept
+	}
+	correct := 0
+	for _, i := range test {
+		if (floats.Dot(coef, X.RawRowView(i))+intercept > 0) == (y[i] > 0) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(test))
+}
+
+// DecisionFunction returns the decision value of each binary classifier for each sample of X
+func (m *SGDClassifier) DecisionFunction(X mat.Matrix) *mat.Dense {
+	nSamples, _ := X.Dims()
+	_, nClassifiers := m.Coef.Dims()
+	D := mat.NewDense(nSamples, nClassifiers, nil)
+	D.Mul(X, m.Coef)
+	D.Apply(func(i, k int, v float64) float64 { return v + m.Intercept[k] }, D)
+	return D
+}
+
+// Predict predicts the label of each sample of X
+func (m *SGDClassifier) Predict(X mat.Matrix, Ymutable mat.Mutable) *mat.Dense {
+	D := m.DecisionFunction(X)
+	nSamples, nClassifiers := D.Dims()
+	Y := mat.NewDense(nSamples, 1, nil)
+	for i := 0; i < nSamples; i++ {
+		if nClassifiers == 1 {
+			Y.Set(i, 0, m.Classes[0])
+			if D.At(i, 0) > 0 {
+				Y.Set(i, 0, m.Classes[1])
+			}
+		} else {
+			Y.Set(i, 0, m.Classes[floats.MaxIdx(D.RawRowView(i))])
+		}
+	}
+	return base.FromDense(Ymutable, Y)
+}
+
+// PredictProbas returns the probability of each class for "log" and "modified_huber" losses. one-vs-rest
+// probabilities are normalized
+func (m *SGDClassifier) PredictProbas(X mat.Matrix, Ymutable mat.Mutable) *mat.Dense {
+	var proba func(d float64) float64
+	switch m.Loss {
+	case "log":
+		proba = func(d float64) float64 { return 1 / (1 + math.Exp(-d)) }
+	case "modified_huber":
+		proba = func(d float64) float64 { return (math.Max(-1, math.Min(1, d)) + 1) / 2 }
+	default:
+		log.Panicf("probability estimates are not available for loss %s.", m.Loss)
+	}
+	D := m.DecisionFunction(X)
+	nSamples, nClassifiers := D.Dims()
+	nClasses := len(m.Classes)
+	P := mat.NewDense(nSamples, nClasses, nil)
+	for i := 0; i < nSamples; i++ {
+		row := P.RawRowView(i)
+		if nClassifiers == 1 {
+			row[1] = proba(D.At(i, 0))
+			row[0] = 1 - row[1]
+			continue
+		}
+		for k := range row {
+			row[k] = proba(D.At(i, k))
+		}
+		if sum := floats.Sum(row); sum > 0 {
+			floats.Scale(1/sum, row)
+		} else {
+			// no class is likely for modified huber
+			for k := range row {
+				row[k] = 1 / float64(nClasses)
+			}
+		}
+	}
+	return base.FromDense(Ymutable, P)
+}
+
+// Score returns the accuracy of the predictions for X
+func (m *SGDClassifier) Score(X, Y mat.Matrix) float64 {
+	return metrics.AccuracyScore(Y, m.Predict(X, nil), true, nil)
+}
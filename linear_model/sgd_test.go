@@ -0,0 +1,125 @@
+package linearmodel
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/datasets"
+	"github.com/RobinRCM/sklearn/preprocessing"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleSGDClassifier() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	for _, loss := range []string{"hinge", "log", "modified_huber", "squared_hinge", "perceptron"} {
+		clf := NewSGDClassifier()
+		clf.Loss, clf.RandomState = loss, base.NewSource(7)
+		clf.Fit(X, ds.Y)
+		fmt.Printf("%-14s epochs:%d accuracy:%.3f\n", loss, clf.NIter, clf.Score(X, ds.Y))
+	}
+	// Output:
+	// hinge          epochs:23 accuracy:0.967
+	// log            epochs:45 accuracy:0.967
+	// modified_huber epochs:23 accuracy:0.973
+	// squared_hinge  epochs:30 accuracy:0.967
+	// perceptron     epochs:51 accuracy:0.947
+}
+
+func ExampleSGDClassifier_penalties() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	for _, penalty := range []string{"l2", "elasticnet", "l1"} {
+		clf := NewSGDClassifier()
+		clf.Penalty, clf.Alpha, clf.RandomState = penalty, .05, base.NewSource(7)
+		clf.Fit(X, ds.Y)
+		zeros := 0
+		for _, v := range clf.Coef.RawMatrix().Data {
+			if v == 0 {
+				zeros++
+			}
+		}
+		fmt.Printf("%-10s zero coefs:%d accuracy:%.3f\n", penalty, zeros, clf.Score(X, ds.Y))
+	}
+	// Output:
+	// l2         zero coefs:0 accuracy:0.900
+	// elasticnet zero coefs:0 accuracy:0.887
+	// l1         zero coefs:5 accuracy:0.887
+}
+
+func ExampleSGDClassifier_PartialFit() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	// samples are sorted by class: shuffle them to get mini-batches with all classes
+	nSamples, _ := X.Dims()
+	Xs, Ys := mat.NewDense(nSamples, 4, nil), mat.NewDense(nSamples, 1, nil)
+	for r, i := range base.NewSource(1).Perm(nSamples) {
+		Xs.SetRow(r, X.RawRowView(i))
+		Ys.SetRow(r, ds.Y.RawRowView(i))
+	}
+	clf := NewSGDClassifier()
+	clf.Loss, clf.RandomState = "log", base.NewSource(7)
+	for epoch := 1; epoch <= 3; epoch++ {
+		for start := 0; start < nSamples; start += 50 {
+			clf.PartialFit(Xs.Slice(start, start+50, 0, 4), Ys.Slice(start, start+50, 0, 1), []float64{0, 1, 2})
+		}
+		fmt.Printf("epoch %d accuracy:%.3f\n", epoch, clf.Score(X, ds.Y))
+	}
+	fmt.Printf("probabilities:\n%.3f\n", mat.Formatted(clf.PredictProbas(X.Slice(48, 53, 0, 4), nil)))
+	// Output:
+	// epoch 1 accuracy:0.900
+	// epoch 2 accuracy:0.900
+	// epoch 3 accuracy:0.927
+	// probabilities:
+	// ⎡1.000  0.000  0.000⎤
+	// ⎢1.000  0.000  0.000⎥
+	// ⎢0.000  1.000  0.000⎥
+	// ⎢0.000  0.877  0.123⎥
+	// ⎣0.000  1.000  0.000⎦
+}
+
+func ExampleSGDClassifier_earlyStopping() {
+	ds := datasets.LoadIris()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X, nil)
+	for _, earlyStopping := range []bool{false, true} {
+		clf := NewSGDClassifier()
+		clf.EarlyStopping, clf.LearningRate, clf.Eta0, clf.RandomState = earlyStopping, "adaptive", .01, base.NewSource(7)
+		clf.Fit(X, ds.Y)
+		fmt.Printf("EarlyStopping:%-5v epochs:%d accuracy:%.3f\n", earlyStopping, clf.NIter, clf.Score(X, ds.Y))
+	}
+	// Output:
+	// EarlyStopping:false epochs:84 accuracy:0.933
+	// EarlyStopping:true  epochs:39 accuracy:0.860
+}
+
+func ExampleSGDClassifier_classWeight() {
+	// predict virginica (1/3 of the samples) from the sepal length
+	ds := datasets.LoadIris()
+	nSamples, _ := ds.X.Dims()
+	X, _ := preprocessing.NewStandardScaler().FitTransform(ds.X.Slice(0, nSamples, 0, 1), nil)
+	Y := mat.NewDense(nSamples, 1, nil)
+	for i := 0; i < nSamples; i++ {
+		if ds.Y.At(i, 0) == 2 {
+			Y.Set(i, 0, 1)
+		}
+	}
+	for _, classWeight := range []interface{}{nil, "balanced"} {
+		clf := NewSGDClassifier()
+		clf.Loss, clf.ClassWeight, clf.Average, clf.RandomState = "log", classWeight, 1, base.NewSource(7)
+		clf.Fit(X, Y)
+		Ypred := clf.Predict(X, nil)
+		truePositives, positives := 0, 0
+		for i := 0; i < nSamples; i++ {
+			if Ypred.At(i, 0) == 1 {
+				positives++
+				if Y.At(i, 0) == 1 {
+					truePositives++
+				}
+			}
+		}
+		fmt.Printf("ClassWeight:%-8v predicted virginica:%d recall:%.2f\n", classWeight, positives, float64(truePositives)/50)
+	}
+	// Output:
+	// ClassWeight:<nil>    predicted virginica:51 recall:0.74
+	// ClassWeight:balanced predicted virginica:67 recall:0.86
+}
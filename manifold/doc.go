@@ -0,0 +1,2 @@
+// Package manifold includes non-linear dimensionality reduction algorithms: TSNE, Isomap, MDS and LocallyLinearEmbedding.
+package manifold
@@ -0,0 +1,216 @@
+package manifold
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/decomposition"
+	"github.com/RobinRCM/sklearn/neighbors"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Isomap is a non-linear dimensionality reduction through isometric mapping: geodesic distances between samples are
+// estimated by shortest paths in the NNeighbors nearest neighbors graph, then embedded by a kernel PCA.
+// PathMethod is "auto" or "D" (Dijkstra) or "FW" (Floyd-Warshall).
+// NJobs is the number of concurrent jobs used for the neighbors search and shortest paths. NJobs<0 means runtime.NumCPU()
+type Isomap struct {
+	NNeighbors, NComponents int
+	PathMethod              string
+	NJobs                   int
+
+	Embedding        *mat.Dense
+	DistMatrix       *mat.Dense
+	KernelPCA        *decomposition.KernelPCA
+	NearestNeighbors *neighbors.NearestNeighbors
+}
+
+// NewIsomap returns an *Isomap with 5 neighbors and 2 components
+func NewIsomap() *Isomap {
+	return &Isomap{NNeighbors: 5, NComponents: 2, PathMethod: "auto", NJobs: -1}
+}
+
+// TransformerClone ...
+func (m *Isomap) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit computes the embedding of X. Y is unused
+func (m *Isomap) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform computes the embedding of X and returns it
+func (m *Isomap) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, _ := X.Dims()
+	if m.NNeighbors <= 0 || m.NNeighbors >= nSamples {
+		panic(fmt.Errorf("Isomap: NNeighbors must be in [1,%d), got %d", nSamples, m.NNeighbors))
+	}
+	m.NearestNeighbors = neighbors.NewNearestNeighbors()
+	m.NearestNeighbors.NJobs = m.NJobs
+	m.NearestNeighbors.Fit(X, nil)
+	graph := m.NearestNeighbors.KNeighborsGraph(X, m.NNeighbors+1, "distance", false)
+	m.DistMatrix = shortestPaths(graph, m.PathMethod, m.NJobs)
+	G := mat.NewDense(nSamples, nSamples, nil)
+	G.Apply(func(i, j int, v float64) float64 { return -.5 * v * v }, m.DistMatrix)
+	m.KernelPCA = decomposition.NewKernelPCA()
+	m.KernelPCA.Kernel = "precomputed"
+	m.KernelPCA.NComponents = m.NComponents
+	m.Embedding, _ = m.KernelPCA.FitTransform(G, nil)
+	return m.Embedding, base.ToDense(Ymatrix)
+}
+
+// GetFeatureNamesOut returns isomap0, isomap1... one name per component. inputNames are unused
+func (m *Isomap) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("isomap", m.Embedding.RawMatrix().Cols)
+}
+
+// Transform embeds new samples: their geodesic distances to fit samples go through their NNeighbors nearest fit samples
+func (m *Isomap) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	nSamples, _ := X.Dims()
+	nFit, _ := m.DistMatrix.Dims()
+	distances, indices := m.NearestNeighbors.KNeighbors(X, m.NNeighbors)
+	G := mat.NewDense(nSamples, nFit, nil)
+	base.Parallelize(m.NJobs, nSamples, func(th, start, end int) {
+		for i := start; i < end; i++ {
+			row := G.RawRowView(i)
+			fill(row, math.Inf(1))
+			for k := 0; k < m.NNeighbors; k++ {
+				d, nbr := distances.At(i, k), m.DistMatrix.RawRowView(int(indices.At(i, k)))
+				for j, v := range nbr {
+					row[j] = math.Min(row[j], d+v)
+				}
+			}
+			for j, v := range row {
+				row[j] = -.5 * v * v
+			}
+		}
+	})
+	Xout, _ = m.KernelPCA.Transform(G, nil)
+	return Xout, base.ToDense(Y)
+}
+
+// ReconstructionError returns the reconstruction error of the embedding: the norm of the difference between the
+// centered geodesic kernel and its low rank approximation, divided by nSamples
+func (m *Isomap) ReconstructionError() float64 {
+	nSamples, _ := m.DistMatrix.Dims()
+	G := mat.NewDense(nSamples, nSamples, nil)
+	G.Apply(func(i, j int, v float64) float64 { return -.5 * v * v }, m.DistMatrix)
+	Gc, _ := preprocessing.NewKernelCenterer().FitTransform(G, nil)
+	s := 0.
+	for i := 0; i < nSamples; i++ {
+		row := Gc.RawRowView(i)
+		s += floats.Dot(row, row)
+	}
+	for _, ev := range m.KernelPCA.Eigenvalues {
+		s -= ev * ev
+	}
+	return math.Sqrt(math.Max(s, 0)) / float64(nSamples)
+}
+
+// shortestPaths returns the matrix of shortest path lengths in the undirected graph whose non-zero elements are edge
+// weights. method is "auto" or "D" (Dijkstra) or "FW" (Floyd-Warshall)
+func shortestPaths(graph *mat.Dense, method string, NJobs int) *mat.Dense {
+	n, _ := graph.Dims()
+	weight := func(i, j int) float64 {
+		a, b := graph.At(i, j), graph.At(j, i)
+		switch {
+		case a == 0:
+			return b
+		case b == 0:
+			return a
+		}
+		return math.Min(a, b)
+	}
+	dist := mat.NewDense(n, n, nil)
+	switch method {
+	case "", "auto", "D":
+		type edge struct {
+			to     int
+			weight float64
+		}
+		adjacency := make([][]edge, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if w := weight(i, j); j != i && w != 0 {
+					adjacency[i] = append(adjacency[i], edge{j, w})
+				}
+			}
+		}
+		base.Parallelize(NJobs, n, func(th, start, end int) {
+			for source := start; source < end; source++ {
+				d := dist.RawRowView(source)
+				fill(d, math.Inf(1))
+				d[source] = 0
+				q := &distanceHeap{{source, 0}}
+				for q.Len() > 0 {
+					e := heap.Pop(q).(distanceHeapItem)
+					if e.distance > d[e.node] {
+						continue
+					}
+					for _, adj := range adjacency[e.node] {
+						if nd := e.distance + adj.weight; nd < d[adj.to] {
+							d[adj.to] = nd
+							heap.Push(q, distanceHeapItem{adj.to, nd})
+						}
+					}
+				}
+			}
+		})
+	case "FW":
+		dist.Apply(func(i, j int, v float64) float64 {
+			if i == j {
+				return 0
+			}
+			if w := weight(i, j); w != 0 {
+				return w
+			}
+			return math.Inf(1)
+		}, dist)
+		for k := 0; k < n; k++ {
+			dk := dist.RawRowView(k)
+			for i := 0; i < n; i++ {
+				di := dist.RawRowView(i)
+				for j, v := range dk {
+					if di[k]+v < di[j] {
+						di[j] = di[k] + v
+					}
+				}
+			}
+		}
+	default:
+		panic(fmt.Errorf("Isomap: unknown path method %s", method))
+	}
+	for _, v := range dist.RawMatrix().Data {
+		if math.IsInf(v, 1) {
+			panic(fmt.Errorf("Isomap: the neighbors graph has more than one connected component, try to increase NNeighbors"))
+		}
+	}
+	return dist
+}
+
+type distanceHeapItem struct {
+	node     int
+	distance float64
+}
+
+// distanceHeap is a min heap of distanceHeapItem implementing heap.Interface
+type distanceHeap []distanceHeapItem
+
+func (h distanceHeap) Len() int            { return len(h) }
+func (h distanceHeap) Less(i, j int) bool  { return h[i].distance < h[j].distance }
+func (h distanceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *distanceHeap) Push(x interface{}) { *h = append(*h, x.(distanceHeapItem)) }
+func (h *distanceHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,58 @@
+package manifold
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func ExampleIsomap() {
+	X, t := sCurve(300, base.NewSource(7))
+	for _, pathMethod := range []string{"D", "FW"} {
+		iso := NewIsomap()
+		iso.NNeighbors = 10
+		iso.PathMethod = pathMethod
+		Xt, _ := iso.FitTransform(X, nil)
+		Xnew, _ := iso.Transform(X.Slice(0, 5, 0, 3), nil)
+		fmt.Printf("%s correlation with curve position: %.3f new samples error: %.3f reconstruction error: %.3f\n",
+			pathMethod, bestAbsCorrelation(t, Xt), maxAbsDiff(Xt.Slice(0, 5, 0, 2), Xnew), iso.ReconstructionError())
+	}
+	// Output:
+	// D correlation with curve position: 1.000 new samples error: 0.000 reconstruction error: 0.147
+	// FW correlation with curve position: 1.000 new samples error: 0.000 reconstruction error: 0.147
+}
+
+// sCurve returns nSamples points on a S shaped surface in 3d and their position along the curve
+func sCurve(nSamples int, source base.Source) (X *mat.Dense, t []float64) {
+	r := rand.New(source)
+	X, t = mat.NewDense(nSamples, 3, nil), make([]float64, nSamples)
+	for i := range t {
+		t[i] = 3 * math.Pi * (r.Float64() - .5)
+		X.Set(i, 0, math.Sin(t[i]))
+		X.Set(i, 1, 2*r.Float64())
+		X.Set(i, 2, math.Copysign(1, t[i])*(math.Cos(t[i])-1))
+	}
+	return
+}
+
+// bestAbsCorrelation returns the largest absolute correlation between t and a column of Xt
+func bestAbsCorrelation(t []float64, Xt mat.Matrix) (best float64) {
+	_, nComponents := Xt.Dims()
+	col := make([]float64, len(t))
+	for c := 0; c < nComponents; c++ {
+		mat.Col(col, c, Xt)
+		best = math.Max(best, math.Abs(stat.Correlation(t, col, nil)))
+	}
+	return
+}
+
+func maxAbsDiff(a, b mat.Matrix) float64 {
+	d := &mat.Dense{}
+	d.Sub(a, b)
+	return mat.Norm(d, math.Inf(1))
+}
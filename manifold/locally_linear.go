@@ -0,0 +1,406 @@
+package manifold
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/neighbors"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LocallyLinearEmbedding computes an embedding preserving distances within local neighborhoods: each sample is
+// reconstructed from its NNeighbors nearest neighbors and the embedding is the bottom eigenvectors of a cost
+// matrix built from these local reconstructions.
+// Method is "standard", "hessian" (Hessian eigenmapping, requires NNeighbors > NComponents*(NComponents+3)/2),
+// "modified" (multiple weights per neighborhood) or "ltsa" (local tangent space alignment).
+// Reg is the regularization of the local covariance for standard and modified methods.
+// see Roweis S., Saul L., Nonlinear dimensionality reduction by locally linear embedding, Science 290:2323 (2000)
+type LocallyLinearEmbedding struct {
+	NNeighbors, NComponents int
+	Reg                     float64
+	Method                  string
+	HessianTol, ModifiedTol float64
+	NJobs                   int
+
+	Embedding           *mat.Dense
+	ReconstructionError float64
+	NearestNeighbors    *neighbors.NearestNeighbors
+}
+
+// NewLocallyLinearEmbedding returns a *LocallyLinearEmbedding with 5 neighbors and 2 components
+func NewLocallyLinearEmbedding() *LocallyLinearEmbedding {
+	return &LocallyLinearEmbedding{NNeighbors: 5, NComponents: 2, Reg: 1e-3, Method: "standard", HessianTol: 1e-4, ModifiedTol: 1e-12, NJobs: -1}
+}
+
+// TransformerClone ...
+func (m *LocallyLinearEmbedding) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit computes the embedding of X. Y is unused
+func (m *LocallyLinearEmbedding) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform computes the embedding of X and returns it
+func (m *LocallyLinearEmbedding) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	nComponents, k := m.NComponents, m.NNeighbors
+	if nComponents > nFeatures {
+		panic(fmt.Errorf("LocallyLinearEmbedding: NComponents %d must be less than or equal to the number of features %d", nComponents, nFeatures))
+	}
+	if k <= 0 || k >= nSamples {
+		panic(fmt.Errorf("LocallyLinearEmbedding: NNeighbors must be in [1,%d), got %d", nSamples, k))
+	}
+	m.NearestNeighbors = neighbors.NewNearestNeighbors()
+	m.NearestNeighbors.NJobs = m.NJobs
+	m.NearestNeighbors.Fit(X, nil)
+	nbrs := m.neighborsExcludingSelf(X)
+	M := mat.NewDense(nSamples, nSamples, nil)
+	switch m.Method {
+	case "", "standard":
+		W := barycenterWeights(X, X, nbrs, m.Reg)
+		// M = (I-W)'(I-W)
+		IW := mat.NewDense(nSamples, nSamples, nil)
+		for i, nbr := range nbrs {
+			IW.Set(i, i, 1)
+			for kk, j := range nbr {
+				IW.Set(i, j, IW.At(i, j)-W.At(i, kk))
+			}
+		}
+		M.Mul(IW.T(), IW)
+	case "hessian":
+		dp := nComponents * (nComponents + 1) / 2
+		if k <= nComponents+dp {
+			panic(fmt.Errorf("LocallyLinearEmbedding: for method hessian, NNeighbors must be greater than %d", nComponents+dp))
+		}
+		Yi := mat.NewDense(k, 1+nComponents+dp, nil)
+		for _, nbr := range nbrs {
+			Gi := centeredNeighbors(X, nbr)
+			U := leftSingularVectors(Gi)
+			for r := 0; r < k; r++ {
+				Yi.Set(r, 0, 1)
+				for c := 0; c < nComponents; c++ {
+					Yi.Set(r, 1+c, U.At(r, c))
+				}
+				j := 1 + nComponents
+				for c1 := 0; c1 < nComponents; c1++ {
+					for c2 := c1; c2 < nComponents; c2++ {
+						Yi.Set(r, j, U.At(r, c1)*U.At(r, c2))
+						j++
+					}
+				}
+			}
+			var qr mat.QR
+			qr.Factorize(Yi)
+			Q := &mat.Dense{}
+			qr.QTo(Q)
+			w := mat.DenseCopyOf(Q.Slice(0, k, nComponents+1, 1+nComponents+dp))
+			S := make([]float64, dp)
+			for r := 0; r < k; r++ {
+				floats.Add(S, w.RawRowView(r))
+			}
+			for c, s := range S {
+				if math.Abs(s) < m.HessianTol {
+					S[c] = 1
+				}
+			}
+			for r := 0; r < k; r++ {
+				floats.Div(w.RawRowView(r), S)
+			}
+			addOuterToNeighbors(M, nbr, w, 1)
+		}
+	case "modified":
+		if k < nComponents {
+			panic(fmt.Errorf("LocallyLinearEmbedding: for method modified, NNeighbors must be at least NComponents"))
+		}
+		m.modifiedCostMatrix(X, nbrs, M)
+	case "ltsa":
+		G := mat.NewDense(k, nComponents+1, nil)
+		for _, nbr := range nbrs {
+			U := leftSingularVectors(centeredNeighbors(X, nbr))
+			for r := 0; r < k; r++ {
+				G.Set(r, 0, 1/math.Sqrt(float64(k)))
+				for c := 0; c < nComponents; c++ {
+					G.Set(r, c+1, U.At(r, c))
+				}
+			}
+			addOuterToNeighbors(M, nbr, G, -1)
+			for _, j := range nbr {
+				M.Set(j, j, M.At(j, j)+1)
+			}
+		}
+	default:
+		panic(fmt.Errorf("LocallyLinearEmbedding: unknown method %s", m.Method))
+	}
+	m.Embedding, m.ReconstructionError = nullSpace(M, nComponents, 1)
+	return m.Embedding, base.ToDense(Ymatrix)
+}
+
+// GetFeatureNamesOut returns locallylinearembedding0, locallylinearembedding1... one name per component. inputNames are unused
+func (m *LocallyLinearEmbedding) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("locallylinearembedding", m.Embedding.RawMatrix().Cols)
+}
+
+// Transform embeds new samples as the barycenter of the embedding of their nearest neighbors in fit samples, with
+// the weights reconstructing them from these neighbors
+func (m *LocallyLinearEmbedding) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, _ := X.Dims()
+	_, indices := m.NearestNeighbors.KNeighbors(X, m.NNeighbors)
+	nbrs := make([][]int, nSamples)
+	for i := range nbrs {
+		nbrs[i] = make([]int, m.NNeighbors)
+		for k := range nbrs[i] {
+			nbrs[i][k] = int(indices.At(i, k))
+		}
+	}
+	W := barycenterWeights(X, m.NearestNeighbors.X, nbrs, m.Reg)
+	Xout = mat.NewDense(nSamples, m.NComponents, nil)
+	for i, nbr := range nbrs {
+		for k, j := range nbr {
+			floats.AddScaled(Xout.RawRowView(i), W.At(i, k), m.Embedding.RawRowView(j))
+		}
+	}
+	return Xout, base.ToDense(Ymatrix)
+}
+
+// modifiedCostMatrix accumulates in M the cost matrix of modified LLE, using multiple local weight vectors
+// see Zhang Z., Wang J., MLLE: Modified Locally Linear Embedding Using Multiple Weights, NIPS 2006
+func (m *LocallyLinearEmbedding) modifiedCostMatrix(X *mat.Dense, nbrs [][]int, M *mat.Dense) {
+	nSamples, nFeatures := X.Dims()
+	k, nComponents := m.NNeighbors, m.NComponents
+	nev := k
+	if nFeatures < nev {
+		nev = nFeatures
+	}
+	// left singular vectors and squared singular values of each neighborhood, sorted by decreasing singular values
+	V := make([]*mat.Dense, nSamples)
+	evals := make([][]float64, nSamples)
+	wReg := mat.NewDense(nSamples, k, nil)
+	ones := make([]float64, k)
+	fill(ones, 1)
+	for i, nbr := range nbrs {
+		Xi := mat.NewDense(k, nFeatures, nil)
+		for r, j := range nbr {
+			floats.SubTo(Xi.RawRowView(r), X.RawRowView(j), X.RawRowView(i))
+		}
+		var svd mat.SVD
+		if !svd.Factorize(Xi, mat.SVDFull) {
+			panic("LocallyLinearEmbedding: svd failed")
+		}
+		V[i] = &mat.Dense{}
+		svd.UTo(V[i])
+		evals[i] = svd.Values(nil)[:nev]
+		for c := range evals[i] {
+			evals[i][c] *= evals[i][c]
+		}
+		// regularized weights
+		reg := 1e-3 * floats.Sum(evals[i])
+		tmp := make([]float64, k)
+		for c := 0; c < k; c++ {
+			tmp[c] = floats.Dot(mat.Col(nil, c, V[i]), ones)
+			if c < nev {
+				tmp[c] /= evals[i][c] + reg
+			} else {
+				tmp[c] /= reg
+			}
+		}
+		w := wReg.RawRowView(i)
+		for r := 0; r < k; r++ {
+			w[r] = floats.Dot(V[i].RawRowView(r), tmp)
+		}
+		floats.Scale(1/floats.Sum(w), w)
+	}
+	// the number of weight vectors of each neighborhood comes from the ratio of small to large eigenvalues
+	rho := make([]float64, nSamples)
+	for i, ev := range evals {
+		rho[i] = floats.Sum(ev[nComponents:]) / floats.Sum(ev[:nComponents])
+	}
+	sortedRho := append([]float64(nil), rho...)
+	sort.Float64s(sortedRho)
+	var eta float64
+	if nSamples%2 == 1 {
+		eta = sortedRho[nSamples/2]
+	} else {
+		eta = (sortedRho[nSamples/2-1] + sortedRho[nSamples/2]) / 2
+	}
+	for i, nbr := range nbrs {
+		// etaRange[c] = sum(evals)/cumsum(evals)[c]-1, decreasing. sI counts values lower than eta
+		cumsum := make([]float64, nev)
+		floats.CumSum(cumsum, evals[i])
+		sI := 0
+		for c := 0; c < nev-1; c++ {
+			if cumsum[nev-1]/cumsum[c]-1 < eta {
+				sI++
+			}
+		}
+		sI += k - nev
+		if sI < 1 {
+			sI = 1
+		}
+		Vi := mat.DenseCopyOf(V[i].Slice(0, k, k-sI, k))
+		viSum := make([]float64, sI)
+		for r := 0; r < k; r++ {
+			floats.Add(viSum, Vi.RawRowView(r))
+		}
+		alpha := floats.Norm(viSum, 2) / math.Sqrt(float64(sI))
+		h := make([]float64, sI)
+		for c := range h {
+			h[c] = alpha - viSum[c]
+		}
+		if normH := floats.Norm(h, 2); normH < m.ModifiedTol {
+			fill(h, 0)
+		} else {
+			floats.Scale(1/normH, h)
+		}
+		// Householder reflection of Vi, shifted by the regularized weights
+		Wi := mat.NewDense(k, sI, nil)
+		for r := 0; r < k; r++ {
+			row, vi := Wi.RawRowView(r), Vi.RawRowView(r)
+			vh := floats.Dot(vi, h)
+			for c := range row {
+				row[c] = vi[c] - 2*vh*h[c] + (1-alpha)*wReg.At(i, r)
+			}
+		}
+		addOuterToNeighbors(M, nbr, Wi, 1)
+		for r, j := range nbr {
+			s := floats.Sum(Wi.RawRowView(r))
+			M.Set(i, j, M.At(i, j)-s)
+			M.Set(j, i, M.At(j, i)-s)
+		}
+		M.Set(i, i, M.At(i, i)+float64(sI))
+	}
+}
+
+// neighborsExcludingSelf returns the indices of the NNeighbors nearest fit samples of each row of X, excluding itself
+func (m *LocallyLinearEmbedding) neighborsExcludingSelf(X *mat.Dense) [][]int {
+	nSamples, _ := X.Dims()
+	_, indices := m.NearestNeighbors.KNeighbors(X, m.NNeighbors+1)
+	nbrs := make([][]int, nSamples)
+	for i := range nbrs {
+		nbrs[i] = make([]int, 0, m.NNeighbors+1)
+		selfSeen := false
+		for k := 0; k <= m.NNeighbors; k++ {
+			j := int(indices.At(i, k))
+			if j == i && !selfSeen {
+				selfSeen = true
+				continue
+			}
+			nbrs[i] = append(nbrs[i], j)
+		}
+		nbrs[i] = nbrs[i][:m.NNeighbors]
+	}
+	return nbrs
+}
+
+// barycenterWeights returns for each row of X the weights (summing to 1) of its neighbors Y[nbrs[i]] that best
+// reconstruct it. the local gram matrix is regularized by reg times its trace
+func barycenterWeights(X, Y *mat.Dense, nbrs [][]int, reg float64) *mat.Dense {
+	nSamples, nFeatures := X.Dims()
+	k := len(nbrs[0])
+	W := mat.NewDense(nSamples, k, nil)
+	base.Parallelize(-1, nSamples, func(th, start, end int) {
+		C, G := mat.NewDense(k, nFeatures, nil), mat.NewDense(k, k, nil)
+		ones := mat.NewVecDense(k, nil)
+		w := mat.NewVecDense(k, nil)
+		for i := start; i < end; i++ {
+			for r, j := range nbrs[i] {
+				floats.SubTo(C.RawRowView(r), Y.RawRowView(j), X.RawRowView(i))
+				ones.SetVec(r, 1)
+			}
+			G.Mul(C, C.T())
+			R := reg
+			if trace := mat.Trace(G); trace > 0 {
+				R *= trace
+			}
+			for r := 0; r < k; r++ {
+				G.Set(r, r, G.At(r, r)+R)
+			}
+			if err := w.SolveVec(G, ones); err != nil {
+				panic(fmt.Errorf("LocallyLinearEmbedding: %s", err))
+			}
+			row := W.RawRowView(i)
+			copy(row, w.RawVector().Data)
+			floats.Scale(1/floats.Sum(row), row)
+		}
+	})
+	return W
+}
+
+// centeredNeighbors returns the rows X[nbr] centered on their mean
+func centeredNeighbors(X *mat.Dense, nbr []int) *mat.Dense {
+	_, nFeatures := X.Dims()
+	Xi := mat.NewDense(len(nbr), nFeatures, nil)
+	mean := make([]float64, nFeatures)
+	for r, j := range nbr {
+		copy(Xi.RawRowView(r), X.RawRowView(j))
+		floats.Add(mean, X.RawRowView(j))
+	}
+	floats.Scale(1/float64(len(nbr)), mean)
+	for r := range nbr {
+		floats.Sub(Xi.RawRowView(r), mean)
+	}
+	return Xi
+}
+
+// leftSingularVectors returns the full (rows,rows) left singular vectors of A sorted by decreasing singular values
+func leftSingularVectors(A *mat.Dense) *mat.Dense {
+	var svd mat.SVD
+	if !svd.Factorize(A, mat.SVDFull) {
+		panic("LocallyLinearEmbedding: svd failed")
+	}
+	U := &mat.Dense{}
+	svd.UTo(U)
+	return U
+}
+
+// addOuterToNeighbors adds scale*w*w' to the submatrix M[nbr,nbr]
+func addOuterToNeighbors(M *mat.Dense, nbr []int, w *mat.Dense, scale float64) {
+	for r1, j1 := range nbr {
+		w1 := w.RawRowView(r1)
+		for r2, j2 := range nbr {
+			M.Set(j1, j2, M.At(j1, j2)+scale*floats.Dot(w1, w.RawRowView(r2)))
+		}
+	}
+}
+
+// nullSpace returns the eigenvectors of the symmetric matrix M associated to its nComponents smallest eigenvalues,
+// after skipping the kSkip smallest ones, and the sum of these eigenvalues
+func nullSpace(M *mat.Dense, nComponents, kSkip int) (*mat.Dense, float64) {
+	n, _ := M.Dims()
+	Msym := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			Msym.SetSym(i, j, (M.At(i, j)+M.At(j, i))/2)
+		}
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(Msym, true) {
+		panic("LocallyLinearEmbedding: eigen decomposition failed")
+	}
+	values := eig.Values(nil)
+	vectors := &mat.Dense{}
+	eig.VectorsTo(vectors)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+	embedding := mat.NewDense(n, nComponents, nil)
+	sum := 0.
+	col := make([]float64, n)
+	for c := 0; c < nComponents; c++ {
+		mat.Col(col, order[c+kSkip], vectors)
+		embedding.SetCol(c, col)
+		sum += values[order[c+kSkip]]
+	}
+	return embedding, sum
+}
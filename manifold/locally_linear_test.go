@@ -0,0 +1,24 @@
+package manifold
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+)
+
+func ExampleLocallyLinearEmbedding() {
+	X, t := sCurve(300, base.NewSource(7))
+	for _, method := range []string{"standard", "hessian", "modified", "ltsa"} {
+		lle := NewLocallyLinearEmbedding()
+		lle.NNeighbors = 12
+		lle.Method = method
+		Xt, _ := lle.FitTransform(X, nil)
+		Xnew, _ := lle.Transform(X.Slice(0, 5, 0, 3), nil)
+		fmt.Printf("%-8s correlation with curve position: %.2f new samples error: %.3f\n", method, bestAbsCorrelation(t, Xt), maxAbsDiff(Xt.Slice(0, 5, 0, 2), Xnew))
+	}
+	// Output:
+	// standard correlation with curve position: 1.00 new samples error: 0.000
+	// hessian  correlation with curve position: 1.00 new samples error: 0.001
+	// modified correlation with curve position: 0.99 new samples error: 0.000
+	// ltsa     correlation with curve position: 1.00 new samples error: 0.000
+}
@@ -0,0 +1,207 @@
+package manifold
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+// MDS is multidimensional scaling: it looks for a low dimensional embedding whose pairwise euclidean distances
+// match the dissimilarities between samples, by minimizing the stress with the SMACOF algorithm.
+// if Metric is false, only the order of dissimilarities is preserved (non-metric MDS): distances are fitted to
+// disparities, the isotonic regression of distances on dissimilarities.
+// Dissimilarity is "euclidean" or "precomputed" (X passed to Fit is then the (nSamples,nSamples) dissimilarity matrix).
+// SMACOF is run NInit times from random initializations and the embedding with the lowest stress is kept.
+// see Borg I., Groenen P., Modern Multidimensional Scaling - Theory and Applications, Springer Series in Statistics (1997)
+type MDS struct {
+	NComponents   int
+	Metric        bool
+	NInit         int
+	MaxIter       int
+	Eps           float64
+	Dissimilarity string
+	RandomState   base.RandomState
+
+	Embedding *mat.Dense
+	Stress    float64
+	NIter     int
+}
+
+// NewMDS returns a *MDS for metric scaling in 2 dimensions
+func NewMDS() *MDS {
+	return &MDS{NComponents: 2, Metric: true, NInit: 4, MaxIter: 300, Eps: 1e-3, Dissimilarity: "euclidean"}
+}
+
+// Fit computes the embedding of X. Y is unused
+func (m *MDS) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform computes the embedding of X and returns it
+func (m *MDS) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := base.ToDense(Xmatrix)
+	var dissimilarities *mat.Dense
+	switch m.Dissimilarity {
+	case "", "euclidean":
+		dissimilarities = euclideanDistances(X)
+	case "precomputed":
+		r, c := X.Dims()
+		if r != c {
+			panic(fmt.Errorf("MDS: precomputed dissimilarity matrix must be square, got (%d,%d)", r, c))
+		}
+		dissimilarities = X
+	default:
+		panic(fmt.Errorf("MDS: unknown dissimilarity %s", m.Dissimilarity))
+	}
+	nInit := m.NInit
+	if nInit <= 0 {
+		nInit = 1
+	}
+	var uniform func() float64
+	switch rs := m.RandomState.(type) {
+	case nil:
+		uniform = rand.Float64
+	case base.Float64er:
+		uniform = rs.Float64
+	default:
+		uniform = rand.New(rs).Float64
+	}
+	m.Embedding, m.Stress = nil, math.Inf(1)
+	for run := 0; run < nInit; run++ {
+		embedding, stress, nIter := m.smacofSingle(dissimilarities, uniform)
+		if stress < m.Stress {
+			m.Embedding, m.Stress, m.NIter = embedding, stress, nIter
+		}
+	}
+	return m.Embedding, base.ToDense(Ymatrix)
+}
+
+// smacofSingle runs the SMACOF algorithm from a random initialization, it returns the embedding, its stress
+// and the number of iterations
+func (m *MDS) smacofSingle(dissimilarities *mat.Dense, uniform func() float64) (X *mat.Dense, stress float64, nIter int) {
+	nSamples, _ := dissimilarities.Dims()
+	X = mat.NewDense(nSamples, m.NComponents, nil)
+	for i := range X.RawMatrix().Data {
+		X.RawMatrix().Data[i] = uniform()
+	}
+	// upper triangle pairs with non-zero dissimilarity, sorted by dissimilarity for isotonic regression
+	type pair struct{ i, j int }
+	var pairs []pair
+	if !m.Metric {
+		for i := 0; i < nSamples; i++ {
+			for j := i + 1; j < nSamples; j++ {
+				if dissimilarities.At(i, j) != 0 {
+					pairs = append(pairs, pair{i, j})
+				}
+			}
+		}
+		sort.SliceStable(pairs, func(a, b int) bool {
+			return dissimilarities.At(pairs[a].i, pairs[a].j) < dissimilarities.At(pairs[b].i, pairs[b].j)
+		})
+	}
+	disparities := mat.NewDense(nSamples, nSamples, nil)
+	B, BX := mat.NewDense(nSamples, nSamples, nil), &mat.Dense{}
+	values := make([]float64, len(pairs))
+	oldStress := math.NaN()
+	for nIter = 1; nIter <= m.MaxIter; nIter++ {
+		dis := euclideanDistances(X)
+		if m.Metric {
+			disparities.Copy(dissimilarities)
+		} else {
+			disparities.Copy(dis)
+			for k, p := range pairs {
+				values[k] = dis.At(p.i, p.j)
+			}
+			isotonicRegression(values)
+			for k, p := range pairs {
+				disparities.Set(p.i, p.j, values[k])
+				disparities.Set(p.j, p.i, values[k])
+			}
+			sumSquares := 0.
+			for _, v := range disparities.RawMatrix().Data {
+				sumSquares += v * v
+			}
+			disparities.Scale(math.Sqrt(float64(nSamples*(nSamples-1))/sumSquares), disparities)
+		}
+		stress = 0.
+		for i := 0; i < nSamples; i++ {
+			for j := i + 1; j < nSamples; j++ {
+				d := dis.At(i, j) - disparities.At(i, j)
+				stress += d * d
+			}
+		}
+		// Guttman transform
+		for i := 0; i < nSamples; i++ {
+			rowSum := 0.
+			for j := 0; j < nSamples; j++ {
+				d := dis.At(i, j)
+				if d == 0 {
+					d = 1e-5
+				}
+				ratio := disparities.At(i, j) / d
+				B.Set(i, j, -ratio)
+				rowSum += ratio
+			}
+			B.Set(i, i, B.At(i, i)+rowSum)
+		}
+		BX.Mul(B, X)
+		X.Scale(1/float64(nSamples), BX)
+		norms := 0.
+		for i := 0; i < nSamples; i++ {
+			norms += mat.Norm(X.RowView(i), 2)
+		}
+		if oldStress-stress/norms < m.Eps {
+			break
+		}
+		oldStress = stress / norms
+	}
+	if nIter > m.MaxIter {
+		nIter = m.MaxIter
+	}
+	return
+}
+
+// isotonicRegression replaces y by its least squares non-decreasing fit, using the pool adjacent violators algorithm
+func isotonicRegression(y []float64) {
+	type block struct {
+		mean float64
+		size int
+	}
+	blocks := make([]block, 0, len(y))
+	for _, v := range y {
+		blocks = append(blocks, block{v, 1})
+		for len(blocks) > 1 && blocks[len(blocks)-2].mean > blocks[len(blocks)-1].mean {
+			a, b := blocks[len(blocks)-2], blocks[len(blocks)-1]
+			size := a.size + b.size
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{(a.mean*float64(a.size) + b.mean*float64(b.size)) / float64(size), size}
+		}
+	}
+	i := 0
+	for _, b := range blocks {
+		for k := 0; k < b.size; k++ {
+			y[i] = b.mean
+			i++
+		}
+	}
+}
+
+// euclideanDistances returns the matrix of euclidean distances between rows of X
+func euclideanDistances(X *mat.Dense) *mat.Dense {
+	nSamples, _ := X.Dims()
+	D := mat.NewDense(nSamples, nSamples, nil)
+	for i := 0; i < nSamples; i++ {
+		for j := i + 1; j < nSamples; j++ {
+			d := math.Sqrt(sqEuclidean(X.RawRowView(i), X.RawRowView(j)))
+			D.Set(i, j, d)
+			D.Set(j, i, d)
+		}
+	}
+	return D
+}
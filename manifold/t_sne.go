@@ -0,0 +1,550 @@
+package manifold
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+	"github.com/RobinRCM/sklearn/neighbors"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// TSNE is t-distributed Stochastic Neighbor Embedding. it converts similarities between samples to joint probabilities
+// and minimizes the Kullback-Leibler divergence between the joint probabilities of the low-dimensional embedding and
+// of the high-dimensional data.
+// Method is "barnes_hut" (default, O(N log N), requires NComponents<4) or "exact".
+// with "barnes_hut", only the 3*Perplexity nearest neighbors of each sample, found with a neighbors.KDTree, are used
+// and Angle is the trade-off between speed and accuracy of the repulsive forces approximation.
+// LearningRate<=0 means auto: max(nSamples/EarlyExaggeration/4, 50).
+// Init is "pca" (default) or "random".
+// see L.J.P. van der Maaten, Accelerating t-SNE using Tree-Based Algorithms, JMLR 15(Oct):3221-3245, 2014
+type TSNE struct {
+	NComponents          int
+	Perplexity           float64
+	EarlyExaggeration    float64
+	LearningRate         float64
+	MaxIter              int
+	NIterWithoutProgress int
+	MinGradNorm          float64
+	Init                 string
+	Method               string
+	Angle                float64
+	RandomState          base.RandomState
+
+	Embedding    *mat.Dense
+	KLDivergence float64
+	NIter        int
+}
+
+// NewTSNE returns a *TSNE with 2 components and a perplexity of 30
+func NewTSNE() *TSNE {
+	return &TSNE{NComponents: 2, Perplexity: 30, EarlyExaggeration: 12, MaxIter: 1000, NIterWithoutProgress: 300, MinGradNorm: 1e-7, Init: "pca", Method: "barnes_hut", Angle: .5}
+}
+
+// tsneExplorationNIter is the number of early exaggeration iterations
+const tsneExplorationNIter = 250
+
+// Fit computes the embedding of X. Y is unused
+func (m *TSNE) Fit(X, Y mat.Matrix) base.Fiter {
+	m.FitTransform(X, Y)
+	return m
+}
+
+// FitTransform computes the embedding of X and returns it
+func (m *TSNE) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := base.ToDense(Xmatrix)
+	nSamples, _ := X.Dims()
+	if m.NComponents <= 0 {
+		m.NComponents = 2
+	}
+	if m.Perplexity >= float64(nSamples) {
+		panic(fmt.Errorf("TSNE: perplexity %g must be less than nSamples %d", m.Perplexity, nSamples))
+	}
+	if m.EarlyExaggeration < 1 {
+		panic(fmt.Errorf("TSNE: EarlyExaggeration must be at least 1, got %g", m.EarlyExaggeration))
+	}
+	learningRate := m.LearningRate
+	if learningRate <= 0 {
+		learningRate = math.Max(float64(nSamples)/m.EarlyExaggeration/4, 50)
+	}
+	maxIter := m.MaxIter
+	if maxIter <= 0 {
+		maxIter = 1000
+	}
+	dof := math.Max(float64(m.NComponents-1), 1)
+
+	var objective func(p, grad []float64, computeError bool) float64
+	var exaggerate func(factor float64)
+	embedding := m.initialEmbedding(X)
+	switch m.Method {
+	case "exact":
+		P := exactJointProbabilities(X, m.Perplexity)
+		exaggerate = func(factor float64) { P.Scale(factor, P) }
+		objective = func(p, grad []float64, computeError bool) float64 {
+			return klDivergenceExact(P, mat.NewDense(nSamples, m.NComponents, p), dof, mat.NewDense(nSamples, m.NComponents, grad), computeError)
+		}
+	case "", "barnes_hut":
+		if m.NComponents > 3 {
+			panic(fmt.Errorf("TSNE: NComponents should be inferior to 4 for the barnes_hut algorithm as it relies on quad-tree or oct-tree"))
+		}
+		P := nearestNeighborsJointProbabilities(X, m.Perplexity)
+		exaggerate = func(factor float64) {
+			for _, values := range P.values {
+				floats.Scale(factor, values)
+			}
+		}
+		objective = func(p, grad []float64, computeError bool) float64 {
+			return klDivergenceBH(P, mat.NewDense(nSamples, m.NComponents, p), dof, m.Angle, mat.NewDense(nSamples, m.NComponents, grad), computeError)
+		}
+	default:
+		panic(fmt.Errorf("TSNE: unknown method %s", m.Method))
+	}
+
+	params := embedding.RawMatrix().Data
+	exaggerate(m.EarlyExaggeration)
+	it, kl := tsneGradientDescent(objective, params, 0, tsneExplorationNIter, tsneExplorationNIter, .5, learningRate, m.MinGradNorm)
+	exaggerate(1 / m.EarlyExaggeration)
+	if it < tsneExplorationNIter || maxIter > tsneExplorationNIter {
+		it, kl = tsneGradientDescent(objective, params, it+1, maxIter, m.NIterWithoutProgress, .8, learningRate, m.MinGradNorm)
+	}
+	m.Embedding, m.KLDivergence, m.NIter = embedding, kl, it
+	return embedding, base.ToDense(Ymatrix)
+}
+
+// initialEmbedding returns the PCA projection of X scaled to a standard deviation of 1e-4, or small random values
+func (m *TSNE) initialEmbedding(X *mat.Dense) *mat.Dense {
+	nSamples, _ := X.Dims()
+	embedding := mat.NewDense(nSamples, m.NComponents, nil)
+	switch m.Init {
+	case "", "pca":
+		pca := preprocessing.NewPCA()
+		pca.NComponents = m.NComponents
+		Xpca, _ := pca.FitTransform(X, nil)
+		col := mat.Col(nil, 0, Xpca)
+		std := math.Sqrt(stat.Variance(col, nil) * float64(nSamples-1) / float64(nSamples))
+		embedding.Scale(1e-4/std, Xpca)
+	case "random":
+		normFloat64 := normFloat64Func(m.RandomState)
+		data := embedding.RawMatrix().Data
+		for i := range data {
+			data[i] = 1e-4 * normFloat64()
+		}
+	default:
+		panic(fmt.Errorf("TSNE: unknown init %s", m.Init))
+	}
+	return embedding
+}
+
+// tsneGradientDescent minimizes objective by batch gradient descent with momentum and individual gains.
+// it returns the last iteration and the last computed error
+func tsneGradientDescent(objective func(p, grad []float64, computeError bool) float64, p []float64, it, maxIter, nIterWithoutProgress int, momentum, learningRate, minGradNorm float64) (int, float64) {
+	const (
+		nIterCheck = 50
+		minGain    = .01
+	)
+	update, gains, grad := make([]float64, len(p)), make([]float64, len(p)), make([]float64, len(p))
+	fill(gains, 1)
+	kl, bestError := math.MaxFloat64, math.MaxFloat64
+	i, bestIter := it, it
+	for ; i < maxIter; i++ {
+		checkConvergence := (i+1)%nIterCheck == 0
+		err := objective(p, grad, checkConvergence || i == maxIter-1)
+		if checkConvergence || i == maxIter-1 {
+			kl = err
+		}
+		for j, g := range grad {
+			if update[j]*g < 0 {
+				gains[j] += .2
+			} else {
+				gains[j] = math.Max(gains[j]*.8, minGain)
+			}
+			grad[j] = g * gains[j]
+			update[j] = momentum*update[j] - learningRate*grad[j]
+			p[j] += update[j]
+		}
+		if checkConvergence {
+			if kl < bestError {
+				bestError, bestIter = kl, i
+			} else if i-bestIter > nIterWithoutProgress {
+				break
+			}
+			if floats.Norm(grad, 2) <= minGradNorm {
+				break
+			}
+		}
+	}
+	if i == maxIter && i > it {
+		i--
+	}
+	return i, kl
+}
+
+// binarySearchPerplexity returns the conditional probabilities P(j|i) from the squared distances of each sample to
+// its neighbors, the gaussian bandwidth of each sample being chosen so that the conditional distribution has the
+// desired perplexity
+func binarySearchPerplexity(sqDistances [][]float64, perplexity float64) [][]float64 {
+	const (
+		nSteps = 100
+		tol    = 1e-5
+		tiny   = 1e-8
+	)
+	desiredEntropy := math.Log(perplexity)
+	P := make([][]float64, len(sqDistances))
+	base.Parallelize(-1, len(sqDistances), func(th, start, end int) {
+		for i := start; i < end; i++ {
+			distances := sqDistances[i]
+			Pi := make([]float64, len(distances))
+			beta, betaMin, betaMax := 1., math.Inf(-1), math.Inf(1)
+			for step := 0; step < nSteps; step++ {
+				sumPi := 0.
+				for j, d := range distances {
+					Pi[j] = math.Exp(-d * beta)
+					sumPi += Pi[j]
+				}
+				if sumPi == 0 {
+					sumPi = tiny
+				}
+				sumDistPi := 0.
+				for j, d := range distances {
+					Pi[j] /= sumPi
+					sumDistPi += d * Pi[j]
+				}
+				entropyDiff := math.Log(sumPi) + beta*sumDistPi - desiredEntropy
+				if math.Abs(entropyDiff) <= tol {
+					break
+				}
+				if entropyDiff > 0 {
+					betaMin = beta
+					if math.IsInf(betaMax, 1) {
+						beta *= 2
+					} else {
+						beta = (beta + betaMax) / 2
+					}
+				} else {
+					betaMax = beta
+					if math.IsInf(betaMin, -1) {
+						beta /= 2
+					} else {
+						beta = (beta + betaMin) / 2
+					}
+				}
+			}
+			P[i] = Pi
+		}
+	})
+	return P
+}
+
+// exactJointProbabilities returns the dense symmetric joint probabilities of all pairs of samples of X
+func exactJointProbabilities(X *mat.Dense, perplexity float64) *mat.Dense {
+	nSamples, _ := X.Dims()
+	sqDistances := make([][]float64, nSamples)
+	for i := range sqDistances {
+		sqDistances[i] = make([]float64, 0, nSamples-1)
+		for j := 0; j < nSamples; j++ {
+			if j != i {
+				sqDistances[i] = append(sqDistances[i], sqEuclidean(X.RawRowView(i), X.RawRowView(j)))
+			}
+		}
+	}
+	conditionalP := binarySearchPerplexity(sqDistances, perplexity)
+	P := mat.NewDense(nSamples, nSamples, nil)
+	for i, Pi := range conditionalP {
+		for jj, p := range Pi {
+			j := jj
+			if jj >= i {
+				j++
+			}
+			P.Set(i, j, P.At(i, j)+p)
+			P.Set(j, i, P.At(j, i)+p)
+		}
+	}
+	sumP := math.Max(mat.Sum(P), eps)
+	P.Apply(func(i, j int, v float64) float64 {
+		if i == j {
+			return 0
+		}
+		return math.Max(v/sumP, eps)
+	}, P)
+	return P
+}
+
+// sparseJointProbabilities holds, for each sample, the indices of its neighbors and the associated joint probabilities
+type sparseJointProbabilities struct {
+	indices [][]int
+	values  [][]float64
+}
+
+// nearestNeighborsJointProbabilities returns the symmetric joint probabilities restricted to the nearest neighbors of
+// each sample
+func nearestNeighborsJointProbabilities(X *mat.Dense, perplexity float64) *sparseJointProbabilities {
+	nSamples, _ := X.Dims()
+	nNeighbors := int(math.Min(float64(nSamples-1), 3*perplexity+1))
+	tree := neighbors.NewKDTree(X, 30)
+	distances, indices := tree.Query(X, nNeighbors+1, 0, 2, math.Inf(1))
+	sqDistances, nbrs := make([][]float64, nSamples), make([][]int, nSamples)
+	for i := 0; i < nSamples; i++ {
+		sqDistances[i], nbrs[i] = make([]float64, 0, nNeighbors+1), make([]int, 0, nNeighbors+1)
+		selfSeen := false
+		for k := 0; k <= nNeighbors; k++ {
+			j := int(indices.At(i, k))
+			if j == i && !selfSeen {
+				selfSeen = true
+				continue
+			}
+			d := distances.At(i, k)
+			sqDistances[i], nbrs[i] = append(sqDistances[i], d*d), append(nbrs[i], j)
+		}
+		sqDistances[i], nbrs[i] = sqDistances[i][:nNeighbors], nbrs[i][:nNeighbors]
+	}
+	conditionalP := binarySearchPerplexity(sqDistances, perplexity)
+	rows := make([]map[int]float64, nSamples)
+	for i := range rows {
+		rows[i] = make(map[int]float64)
+	}
+	sumP := 0.
+	for i, Pi := range conditionalP {
+		for k, p := range Pi {
+			j := nbrs[i][k]
+			rows[i][j] += p
+			rows[j][i] += p
+			sumP += 2 * p
+		}
+	}
+	sumP = math.Max(sumP, eps)
+	P := &sparseJointProbabilities{indices: make([][]int, nSamples), values: make([][]float64, nSamples)}
+	for i, row := range rows {
+		for j := range row {
+			P.indices[i] = append(P.indices[i], j)
+		}
+		sort.Ints(P.indices[i])
+		P.values[i] = make([]float64, len(P.indices[i]))
+		for k, j := range P.indices[i] {
+			P.values[i][k] = row[j] / sumP
+		}
+	}
+	return P
+}
+
+// eps is the float64 machine epsilon
+const eps = 2.220446049250313e-16
+
+// klDivergenceExact computes the t-SNE objective and its gradient wrt the embedding Y using all pairs of samples
+func klDivergenceExact(P, Y *mat.Dense, dof float64, grad *mat.Dense, computeError bool) float64 {
+	nSamples, nComponents := Y.Dims()
+	W := mat.NewDense(nSamples, nSamples, nil)
+	sumW := 0.
+	for i := 0; i < nSamples; i++ {
+		for j := i + 1; j < nSamples; j++ {
+			w := math.Pow(1+sqEuclidean(Y.RawRowView(i), Y.RawRowView(j))/dof, -(dof+1)/2)
+			W.Set(i, j, w)
+			W.Set(j, i, w)
+			sumW += 2 * w
+		}
+	}
+	c := 2 * (dof + 1) / dof
+	kls := make([]float64, nSamples)
+	base.Parallelize(-1, nSamples, func(th, start, end int) {
+		for i := start; i < end; i++ {
+			yi, gi := Y.RawRowView(i), grad.RawRowView(i)
+			fill(gi, 0)
+			for j := 0; j < nSamples; j++ {
+				if j == i {
+					continue
+				}
+				w, p := W.At(i, j), P.At(i, j)
+				q := math.Max(w/sumW, eps)
+				if computeError {
+					kls[i] += p * math.Log(math.Max(p, eps)/q)
+				}
+				mult := c * (p - q) * w
+				yj := Y.RawRowView(j)
+				for k := 0; k < nComponents; k++ {
+					gi[k] += mult * (yi[k] - yj[k])
+				}
+			}
+		}
+	})
+	return floats.Sum(kls)
+}
+
+// klDivergenceBH computes the t-SNE objective and its gradient wrt the embedding Y. attractive forces use the sparse
+// neighbors joint probabilities and repulsive forces are approximated with a Barnes-Hut space partitioning tree
+func klDivergenceBH(P *sparseJointProbabilities, Y *mat.Dense, dof, angle float64, grad *mat.Dense, computeError bool) float64 {
+	const float32Tiny = 1.1754944e-38
+	nSamples, nComponents := Y.Dims()
+	tree := newSpaceTree(Y)
+	negF := mat.NewDense(nSamples, nComponents, nil)
+	sumQs := make([]float64, nSamples)
+	base.Parallelize(-1, nSamples, func(th, start, end int) {
+		for i := start; i < end; i++ {
+			sumQs[i] = tree.summarize(Y.RawRowView(i), negF.RawRowView(i), angle*angle, dof)
+		}
+	})
+	sumQ := floats.Sum(sumQs)
+	c, exponent := 2*(dof+1)/dof, (dof+1)/2
+	kls := make([]float64, nSamples)
+	base.Parallelize(-1, nSamples, func(th, start, end int) {
+		for i := start; i < end; i++ {
+			yi, gi := Y.RawRowView(i), grad.RawRowView(i)
+			fill(gi, 0)
+			for k, j := range P.indices[i] {
+				yj, p := Y.RawRowView(j), P.values[i][k]
+				q := dof / (dof + sqEuclidean(yi, yj))
+				if dof != 1 {
+					q = math.Pow(q, exponent)
+				}
+				for d := 0; d < nComponents; d++ {
+					gi[d] += p * q * (yi[d] - yj[d])
+				}
+				if computeError {
+					kls[i] += p * math.Log(math.Max(p, float32Tiny)/math.Max(q/sumQ, float32Tiny))
+				}
+			}
+			for d, f := range negF.RawRowView(i) {
+				gi[d] = c * (gi[d] - f/sumQ)
+			}
+		}
+	})
+	return floats.Sum(kls)
+}
+
+// spaceTreeNode is a cell of a space partitioning tree (quad-tree in 2d, oct-tree in 3d) used by Barnes-Hut
+type spaceTreeNode struct {
+	barycenter []float64
+	size       int
+	maxWidth   float64
+	children   []*spaceTreeNode
+}
+
+// newSpaceTree builds the space partitioning tree of the rows of Y
+func newSpaceTree(Y *mat.Dense) *spaceTreeNode {
+	nSamples, nComponents := Y.Dims()
+	mins, maxes := make([]float64, nComponents), make([]float64, nComponents)
+	fill(mins, math.Inf(1))
+	fill(maxes, math.Inf(-1))
+	idx := make([]int, nSamples)
+	for i := range idx {
+		idx[i] = i
+		for d, v := range Y.RawRowView(i) {
+			mins[d], maxes[d] = math.Min(mins[d], v), math.Max(maxes[d], v)
+		}
+	}
+	for d := range mins {
+		margin := math.Max(1e-3*(maxes[d]-mins[d]), 1e-12)
+		mins[d] -= margin
+		maxes[d] += margin
+	}
+	return buildSpaceTree(Y, idx, mins, maxes, 0)
+}
+
+func buildSpaceTree(Y *mat.Dense, idx []int, mins, maxes []float64, depth int) *spaceTreeNode {
+	const maxDepth = 64
+	nComponents := len(mins)
+	node := &spaceTreeNode{barycenter: make([]float64, nComponents), size: len(idx)}
+	allEqual := true
+	first := Y.RawRowView(idx[0])
+	for _, i := range idx {
+		floats.Add(node.barycenter, Y.RawRowView(i))
+		allEqual = allEqual && floats.Equal(first, Y.RawRowView(i))
+	}
+	floats.Scale(1/float64(len(idx)), node.barycenter)
+	for d := range mins {
+		node.maxWidth = math.Max(node.maxWidth, maxes[d]-mins[d])
+	}
+	if allEqual || depth >= maxDepth {
+		return node
+	}
+	center := make([]float64, nComponents)
+	for d := range center {
+		center[d] = (mins[d] + maxes[d]) / 2
+	}
+	groups := make([][]int, 1<<uint(nComponents))
+	for _, i := range idx {
+		code := 0
+		for d, v := range Y.RawRowView(i) {
+			if v >= center[d] {
+				code |= 1 << uint(d)
+			}
+		}
+		groups[code] = append(groups[code], i)
+	}
+	for code, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		childMins, childMaxes := make([]float64, nComponents), make([]float64, nComponents)
+		for d := range center {
+			if code&(1<<uint(d)) != 0 {
+				childMins[d], childMaxes[d] = center[d], maxes[d]
+			} else {
+				childMins[d], childMaxes[d] = mins[d], center[d]
+			}
+		}
+		node.children = append(node.children, buildSpaceTree(Y, group, childMins, childMaxes, depth+1))
+	}
+	return node
+}
+
+// summarize accumulates in negF the unnormalized repulsive forces exerted on point y, using the barycenter of the
+// cells that are small enough as seen from y, and returns the contribution of y to the normalization term
+func (node *spaceTreeNode) summarize(y, negF []float64, theta2, dof float64) (sumQ float64) {
+	const duplicateTol = 1e-6
+	delta := make([]float64, len(y))
+	dist2, duplicate := 0., true
+	for d := range y {
+		delta[d] = y[d] - node.barycenter[d]
+		dist2 += delta[d] * delta[d]
+		duplicate = duplicate && math.Abs(delta[d]) <= duplicateTol
+	}
+	isLeaf := node.children == nil
+	if isLeaf && duplicate {
+		return 0
+	}
+	if isLeaf || node.maxWidth*node.maxWidth/dist2 < theta2 {
+		qijZ := dof / (dof + dist2)
+		if dof != 1 {
+			qijZ = math.Pow(qijZ, (dof+1)/2)
+		}
+		size := float64(node.size)
+		floats.AddScaled(negF, size*qijZ*qijZ, delta)
+		return size * qijZ
+	}
+	for _, child := range node.children {
+		sumQ += child.summarize(y, negF, theta2, dof)
+	}
+	return
+}
+
+// sqEuclidean returns the squared euclidean distance between a and b
+func sqEuclidean(a, b []float64) (d2 float64) {
+	for i, v := range a {
+		d := v - b[i]
+		d2 += d * d
+	}
+	return
+}
+
+// fill sets all elements of a to v
+func fill(a []float64, v float64) {
+	for i := range a {
+		a[i] = v
+	}
+}
+
+// normFloat64Func returns a standard normal generator from RandomState, or the global one if it is nil
+func normFloat64Func(RandomState base.Source) func() float64 {
+	if RandomState == base.Source(nil) {
+		return rand.NormFloat64
+	}
+	if normFloat64er, ok := RandomState.(base.NormFloat64er); ok {
+		return normFloat64er.NormFloat64
+	}
+	return rand.New(RandomState).NormFloat64
+}
@@ -0,0 +1,57 @@
+package manifold
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleTSNE() {
+	// 3 gaussian clusters in 10 dimensions
+	r := rand.New(base.NewSource(5))
+	nSamples, nFeatures := 150, 10
+	X := mat.NewDense(nSamples, nFeatures, nil)
+	labels := make([]int, nSamples)
+	for i := 0; i < nSamples; i++ {
+		labels[i] = i % 3
+		for j := 0; j < nFeatures; j++ {
+			center := 0.
+			if j == labels[i] {
+				center = 10
+			}
+			X.Set(i, j, center+r.NormFloat64())
+		}
+	}
+	for _, method := range []string{"barnes_hut", "exact"} {
+		tsne := NewTSNE()
+		tsne.Perplexity = 20
+		tsne.Method = method
+		Xt, _ := tsne.FitTransform(X, nil)
+		fmt.Printf("%-10s nearest neighbor accuracy: %.2f KL divergence: %.1f\n", method, nearestNeighborAccuracy(Xt, labels), tsne.KLDivergence)
+	}
+	// Output:
+	// barnes_hut nearest neighbor accuracy: 1.00 KL divergence: 0.4
+	// exact      nearest neighbor accuracy: 1.00 KL divergence: 0.4
+}
+
+// nearestNeighborAccuracy returns the proportion of samples having the same label as their nearest neighbor
+func nearestNeighborAccuracy(X *mat.Dense, labels []int) float64 {
+	nSamples, _ := X.Dims()
+	good := 0
+	for i := 0; i < nSamples; i++ {
+		best, bestDist := -1, math.Inf(1)
+		for j := 0; j < nSamples; j++ {
+			if d := sqEuclidean(X.RawRowView(i), X.RawRowView(j)); j != i && d < bestDist {
+				best, bestDist = j, d
+			}
+		}
+		if labels[best] == labels[i] {
+			good++
+		}
+	}
+	return float64(good) / float64(nSamples)
+}
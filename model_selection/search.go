@@ -181,6 +181,9 @@ func (gscv *GridSearchCV) Predict(X mat.Matrix, Y mat.Mutable) *mat.Dense {
 }
 
 func getParam(estimator interface{}, k string) (v interface{}, ok bool) {
+	if i := strings.Index(k, "__"); i > 0 {
+		return getParam(subEstimator(estimator, k[:i]), k[i+2:])
+	}
 	est := reflect.ValueOf(estimator)
 	est = reflect.Indirect(est)
 	if est.Kind().String() != "struct" {
@@ -194,7 +197,13 @@ func getParam(estimator interface{}, k string) (v interface{}, ok bool) {
 	return
 }
 
-func setParam(estimator base.Predicter, k string, v interface{}) {
+// setParam sets field k of estimator to v. k may be the name of a step of a composite estimator like a
+// pipeline.Pipeline followed by two underscores and the parameter of the step, like "select__K"
+func setParam(estimator interface{}, k string, v interface{}) {
+	if i := strings.Index(k, "__"); i > 0 {
+		setParam(subEstimator(estimator, k[:i]), k[i+2:], v)
+		return
+	}
 	est := reflect.ValueOf(estimator)
 	est = reflect.Indirect(est)
 	if est.Kind().String() != "struct" {
@@ -229,3 +238,27 @@ func setParam(estimator base.Predicter, k string, v interface{}) {
 	}
 
 }
+
+// subEstimator returns the step named name of a composite estimator, that is the first non nil interface field of the
+// element of a slice field of estimator whose Name field is name (like pipeline.NamedStep)
+func subEstimator(estimator interface{}, name string) interface{} {
+	est := reflect.Indirect(reflect.ValueOf(estimator))
+	for f := 0; f < est.NumField(); f++ {
+		field := est.Field(f)
+		if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		for i := 0; i < field.Len(); i++ {
+			step := field.Index(i)
+			if stepName := step.FieldByName("Name"); stepName.Kind() != reflect.String || stepName.String() != name {
+				continue
+			}
+			for j := 0; j < step.NumField(); j++ {
+				if sub := step.Field(j); sub.Kind() == reflect.Interface && !sub.IsNil() && sub.CanInterface() {
+					return sub.Interface()
+				}
+			}
+		}
+	}
+	panic(fmt.Errorf("no step %s in %T", name, estimator))
+}
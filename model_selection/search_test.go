@@ -10,6 +10,7 @@ import (
 	"github.com/RobinRCM/sklearn/datasets"
 	"github.com/RobinRCM/sklearn/metrics"
 	neuralnetwork "github.com/RobinRCM/sklearn/neural_network"
+	"github.com/RobinRCM/sklearn/pipeline"
 	"github.com/RobinRCM/sklearn/preprocessing"
 	"golang.org/x/exp/rand"
 	"gonum.org/v1/gonum/mat"
@@ -145,3 +146,19 @@ func Test_setParam(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func Test_setParamStep(t *testing.T) {
+	poly := preprocessing.NewPolynomialFeatures(2)
+	pl := pipeline.NewPipeline(
+		pipeline.NamedStep{Name: "poly", Fiter: poly},
+		pipeline.NamedStep{Name: "mlp", Fiter: neuralnetwork.NewMLPRegressor([]int{20}, "relu", "adam", 1e-4)},
+	)
+	setParam(pl, "poly__Degree", 3)
+	setParam(pl, "mlp__Alpha", 1)
+	if poly.Degree != 3 {
+		t.Errorf("expected Degree 3, got %d", poly.Degree)
+	}
+	if alpha, ok := getParam(pl, "mlp__alpha"); !ok || alpha.(float64) != 1 {
+		t.Errorf("expected Alpha 1, got %v", alpha)
+	}
+}
@@ -12,7 +12,11 @@ type Distance func(a, b mat.Vector) float64
 // MinkowskiDistanceP ...
 func MinkowskiDistanceP(a, b mat.Vector, p float64) float64 {
 	if a.Len() == 1 {
-		return math.Abs(b.At(0, 0) - a.At(0, 0))
+		d := math.Abs(b.At(0, 0) - a.At(0, 0))
+		if math.IsInf(p, 1) {
+			return d
+		}
+		return math.Pow(d, p)
 	}
 	var dp float64
 	rva, isrva := a.(mat.RawVectorer)
@@ -79,3 +83,23 @@ func EuclideanDistance(a, b mat.Vector) float64 {
 	}
 	return math.Sqrt(d2)
 }
+
+// NanEuclideanDistance is the euclidean distance ignoring coordinates where a or b is NaN, scaled up by
+// sqrt(nFeatures/nPresent) where nPresent is the number of coordinates present in both. it is NaN if there is no
+// such coordinate
+func NanEuclideanDistance(a, b mat.Vector) float64 {
+	var d2 float64
+	nPresent := 0
+	for j := 0; j < a.Len(); j++ {
+		x := b.AtVec(j) - a.AtVec(j)
+		if math.IsNaN(x) {
+			continue
+		}
+		d2 += x * x
+		nPresent++
+	}
+	if nPresent == 0 {
+		return math.NaN()
+	}
+	return math.Sqrt(d2 * float64(a.Len()) / float64(nPresent))
+}
@@ -24,3 +24,15 @@ func ExampleEuclideanDistance() {
 	// Output:
 	// 1.73205081
 }
+
+func ExampleNanEuclideanDistance() {
+	nan := math.NaN()
+	a, b := mat.NewVecDense(3, []float64{3, nan, nan}), mat.NewVecDense(3, []float64{1, nan, 0})
+	fmt.Printf("%.8f\n", NanEuclideanDistance(a, b))
+	fmt.Printf("%.8f\n", NanEuclideanDistance(a, a))
+	fmt.Println(NanEuclideanDistance(mat.NewVecDense(2, []float64{nan, 1}), mat.NewVecDense(2, []float64{1, nan})))
+	// Output:
+	// 3.46410162
+	// 0.00000000
+	// NaN
+}
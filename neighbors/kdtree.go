@@ -55,10 +55,10 @@ func (r *Rectangle) Split(d int, split float64) (less, greater *Rectangle) {
 	cp := copyFloatSlice
 	mid := cp(r.Maxes)
 	mid[d] = split
-	less = NewRectangle(r.Mins, mid)
+	less = NewRectangle(mid, r.Mins)
 	mid = cp(r.Mins)
 	mid[d] = split
-	greater = NewRectangle(mid, r.Maxes)
+	greater = NewRectangle(r.Maxes, mid)
 	return less, greater
 }
 
@@ -371,10 +371,10 @@ func (tr *KDTree) _query(X mat.Vector, k int, eps, p float64, distanceUpperBound
 					if len(neighbors) == k {
 						nHeappop()
 					}
-				}
-				nHeappush(nEle{float64: -ds, int: fitSample})
-				if len(neighbors) == k {
-					distanceUpperBound = -neighbors[0].float64
+					nHeappush(nEle{float64: -ds, int: fitSample})
+					if len(neighbors) == k {
+						distanceUpperBound = -neighbors[0].float64
+					}
 				}
 
 			}
@@ -493,3 +493,46 @@ func (tr *KDTree) Query(X mat.Matrix, k int, eps, p, distanceUpperBound float64)
 	})
 	return
 }
+
+// QueryBallPoint returns, for each row of X, the sorted indices of the points of the tree whose Minkowski p-distance
+// to the row is at most r
+func (tr *KDTree) QueryBallPoint(X mat.Matrix, r, p float64) [][]int {
+	NSamples, NFeatures := X.Dims()
+	// MinkowskiDistanceP returns distances to the power p
+	rp := r
+	if !math.IsInf(p, 1) {
+		rp = math.Pow(r, p)
+	}
+	indices := make([][]int, NSamples)
+	base.Parallelize(runtime.NumCPU(), NSamples, func(th, start, end int) {
+		row := make([]float64, NFeatures)
+		for sample := start; sample < end; sample++ {
+			mat.Row(row, sample, X)
+			x := mat.NewVecDense(NFeatures, row)
+			rect := NewRectangle(copyFloatSlice(tr.Maxes), copyFloatSlice(tr.Mins))
+			found := make([]int, 0)
+			tr._queryBallPoint(x, r, rp, p, tr.Tree, rect, &found)
+			sort.Ints(found)
+			indices[sample] = found
+		}
+	})
+	return indices
+}
+
+func (tr *KDTree) _queryBallPoint(x *mat.VecDense, r, rp, p float64, node Node, rect *Rectangle, found *[]int) {
+	if rect.MinDistancePoint(x.RawVector().Data, p) > r {
+		return
+	}
+	if node.IsLeaf() {
+		for _, fitSample := range node.(*LeafNode).idx {
+			if MinkowskiDistanceP(x, tr.Data.RowView(fitSample), p) <= rp {
+				*found = append(*found, fitSample)
+			}
+		}
+		return
+	}
+	innernode := node.(*InnerNode)
+	less, greater := rect.Split(innernode.splitDim, innernode.split)
+	tr._queryBallPoint(x, r, rp, p, innernode.less, less, found)
+	tr._queryBallPoint(x, r, rp, p, innernode.greater, greater, found)
+}
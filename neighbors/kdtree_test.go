@@ -24,6 +24,10 @@ func TestRectangle(t *testing.T) {
 	if math.Abs(5-R.MaxDistancePoint([]float64{0, 0}, 2)) > 1.e-3 {
 		t.Error("err MaxDistancePoint")
 	}
+	less, greater := R.Split(0, 2)
+	if less.String() != "<Rectangle 1 2, 2 4>" || greater.String() != "<Rectangle 2 3, 2 4>" {
+		t.Errorf("err Split %s %s", less, greater)
+	}
 }
 
 func ExampleKDTree() {
@@ -45,3 +49,21 @@ func ExampleKDTree() {
 	// [2.000000  0.141421]
 	// [ 0  13]
 }
+
+func ExampleKDTree_QueryBallPoint() {
+	X := mat.NewDense(30, 2, nil)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 6; j++ {
+			X.Set(i*6+j, 0, float64(i))
+			X.Set(i*6+j, 1, float64(j+2))
+		}
+	}
+	tree := NewKDTree(X, 1)
+	pts := mat.NewDense(2, 2, []float64{0, 0, 2.1, 2.9})
+	fmt.Println(tree.QueryBallPoint(pts, 2, 2))
+	// with the maximum-coordinate-difference distance
+	fmt.Println(tree.QueryBallPoint(pts, 1, math.Inf(1)))
+	// Output:
+	// [[0] [6 7 8 12 13 14 18 19 20 25]]
+	// [[] [12 13 18 19]]
+}
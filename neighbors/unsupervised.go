@@ -14,7 +14,8 @@ import (
 // NearestNeighbors is the unsupervised alog implementing search of k nearest neighbors
 // Algorithm is one of 'auto', 'ball_tree', 'kd_tree', 'brute' defaults to "auto"
 //
-// Metric = 'cityblock', 'cosine', 'euclidean', 'l1', 'l2', 'manhattan' defaults to euclidean (= minkowski with P=2)
+// Metric = 'cityblock', 'cosine', 'euclidean', 'l1', 'l2', 'manhattan', 'nan_euclidean' defaults to euclidean (= minkowski with P=2)
+// 'nan_euclidean' ignores missing (NaN) coordinates, see NanEuclideanDistance. it always uses brute force
 // P is power for 'minkowski'
 // NJobs: number of concurrent jobs. NJobs<0 means runtime.NumCPU()  default to -1
 type NearestNeighbors struct {
@@ -44,6 +45,9 @@ func (m *NearestNeighbors) Fit(X, Y mat.Matrix) {
 	case "euclidean":
 		m.P = 2
 		m.Distance = MinkowskiDistance(m.P)
+	case "nan_euclidean":
+		m.P = 2
+		m.Distance = NanEuclideanDistance
 	default:
 		m.Distance = MinkowskiDistance(m.P)
 	}
@@ -51,7 +55,7 @@ func (m *NearestNeighbors) Fit(X, Y mat.Matrix) {
 		m.NJobs = runtime.NumCPU()
 	}
 	m.X = mat.DenseCopyOf(X)
-	useKDTree := strings.Contains(strings.ToLower(m.Algorithm), "tree") || (m.Algorithm == "auto" && r*c > 1000)
+	useKDTree := m.Metric != "nan_euclidean" && (strings.Contains(strings.ToLower(m.Algorithm), "tree") || (m.Algorithm == "auto" && r*c > 1000))
 	if useKDTree {
 		if m.LeafSize <= 0 {
 			m.LeafSize = 30
@@ -83,7 +87,11 @@ func (m *NearestNeighbors) KNeighbors(X mat.Matrix, NNeighbors int) (distances,
 					idx[ifs] = ifs
 				}
 			})
-			sort.Slice(idx, func(i, j int) bool { return sampleDistance[idx[i]] < sampleDistance[idx[j]] })
+			// NaN distances come last
+			sort.Slice(idx, func(i, j int) bool {
+				di, dj := sampleDistance[idx[i]], sampleDistance[idx[j]]
+				return di < dj || (!math.IsNaN(di) && math.IsNaN(dj))
+			})
 			for ik := 0; ik < NNeighbors; ik++ {
 				indices.Set(sample, ik, float64(idx[ik]))
 				distances.Set(sample, ik, sampleDistance[idx[ik]])
@@ -104,22 +112,18 @@ func (m *NearestNeighbors) KNeighborsGraph(X *mat.Dense, NNeighbors int, mode st
 	NSamplesFit, _ := m.X.Dims()
 	distances, indices := m.KNeighbors(X, NNeighbors)
 	graph = mat.NewDense(NSamples, NSamplesFit, nil)
-	var source *mat.Dense
-
-	switch mode {
-	case "connectivity":
-		source = indices
-	default:
-		source = distances
-	}
 	base.Parallelize(m.NJobs, NSamples, func(th, start, end int) {
 		for sample := start; sample < end; sample++ {
 			for ik := 0; ik < NNeighbors; ik++ {
-				index := int(source.At(sample, ik))
+				index := int(indices.At(sample, ik))
 				if sample == index && !includeSelf {
 					continue
 				}
-				graph.Set(sample, index, 1.)
+				weight := 1.
+				if mode == "distance" {
+					weight = distances.At(sample, ik)
+				}
+				graph.Set(sample, index, weight)
 			}
 		}
 	})
@@ -39,10 +39,15 @@ func ExampleNearestNeighbors_KNeighborsGraph() {
 	neigh.Fit(X, mat.Matrix(nil))
 	A := neigh.KNeighborsGraph(X, 2, "connectivity", true)
 	fmt.Println(mat.Formatted(A))
+	A = neigh.KNeighborsGraph(X, 2, "distance", false)
+	fmt.Println(mat.Formatted(A))
 	// Output:
 	// ⎡1  0  1⎤
 	// ⎢0  1  1⎥
 	// ⎣1  0  1⎦
+	// ⎡0  0  1⎤
+	// ⎢0  0  2⎥
+	// ⎣1  0  0⎦
 }
 
 func ExampleNearestNeighbors_Tree() {
@@ -0,0 +1,37 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/datasets"
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+	"github.com/RobinRCM/sklearn/preprocessing"
+)
+
+func ExamplePipeline_GetFeatureNamesOut() {
+	ds := datasets.LoadDiabetes()
+	X, Y := ds.X.Slice(0, 442, 0, 3), ds.Y
+	poly := preprocessing.NewPolynomialFeatures(2)
+	poly.IncludeBias = false
+	poly.InteractionOnly = true
+	regr := linearmodel.NewLinearRegression()
+	pl := NewPipeline(NamedStep{"scaler", preprocessing.NewStandardScaler()}, NamedStep{"poly", poly}, NamedStep{"regr", regr})
+	pl.Fit(X, Y)
+	// map the coefficients of the final estimator back to the input features
+	for j, name := range pl.GetFeatureNamesOut(ds.FeatureNames[:3]) {
+		fmt.Printf("%-8s %8.3f\n", name, regr.Coef.At(j, 0))
+	}
+
+	union := MakeUnion(preprocessing.NewPCA(), preprocessing.NewMinMaxScaler([]float64{0, 1}))
+	union.TransformerList[0].Name, union.TransformerList[1].Name = "pca", "minmax"
+	union.Fit(X, nil)
+	fmt.Println(union.GetFeatureNamesOut(nil))
+	// Output:
+	// age         7.664
+	// sex        -1.929
+	// bmi        45.947
+	// age sex     6.225
+	// age bmi     5.736
+	// sex bmi     5.928
+	// [pca__pca0 pca__pca1 pca__pca2 minmax__x0 minmax__x1 minmax__x2]
+}
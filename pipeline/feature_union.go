@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// FeatureUnion concatenates the results of several transformers fitted on the same input.
+// each NamedStep of TransformerList must be a base.Transformer. TransformerWeights multiplies the output of named
+// transformers. transformers are fitted and applied concurrently using NJobs goroutines. NJobs<=0 means runtime.NumCPU()
+type FeatureUnion struct {
+	TransformerList    []NamedStep
+	TransformerWeights map[string]float64
+	NJobs              int
+}
+
+// NewFeatureUnion returns a *FeatureUnion
+func NewFeatureUnion(transformers ...NamedStep) *FeatureUnion {
+	for _, step := range transformers {
+		if _, ok := step.Fiter.(base.Transformer); !ok {
+			panic(fmt.Errorf("FeatureUnion: %s is not a Transformer", step.Name))
+		}
+	}
+	return &FeatureUnion{TransformerList: transformers}
+}
+
+// MakeUnion returns a FeatureUnion from unnamed transformers
+func MakeUnion(transformers ...base.Transformer) *FeatureUnion {
+	u := &FeatureUnion{}
+	for _, transformer := range transformers {
+		u.TransformerList = append(u.TransformerList, NamedStep{Name: strings.ToLower(fmt.Sprintf("%T", transformer)), Fiter: transformer})
+	}
+	return u
+}
+
+// TransformerClone clones the FeatureUnion and its transformers
+func (u *FeatureUnion) TransformerClone() base.Transformer {
+	clone := *u
+	clone.TransformerList = make([]NamedStep, len(u.TransformerList))
+	for i, step := range u.TransformerList {
+		clone.TransformerList[i] = NamedStep{Name: step.Name, Fiter: u.transformer(i).TransformerClone()}
+	}
+	return &clone
+}
+
+// Fit fits all transformers on X
+func (u *FeatureUnion) Fit(X, Y mat.Matrix) base.Fiter {
+	base.Parallelize(u.NJobs, len(u.TransformerList), func(th, start, end int) {
+		for i := start; i < end; i++ {
+			u.transformer(i).Fit(X, Y)
+		}
+	})
+	return u
+}
+
+// Transform transforms X with each transformer and concatenates the results
+func (u *FeatureUnion) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return u.apply(X, Y, false), base.ToDense(Y)
+}
+
+// FitTransform fits all transformers, transforms X and concatenates the results
+func (u *FeatureUnion) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	return u.apply(X, Y, true), base.ToDense(Y)
+}
+
+// GetFeatureNamesOut returns the output feature names of each transformer prefixed with its name and a double
+// underscore, like pca__pca0
+func (u *FeatureUnion) GetFeatureNamesOut(inputNames []string) []string {
+	var names []string
+	for _, step := range u.TransformerList {
+		namer, ok := step.Fiter.(base.FeatureNamesOuter)
+		if !ok {
+			panic(fmt.Errorf("FeatureUnion: %s has no GetFeatureNamesOut", step.Name))
+		}
+		for _, featureName := range namer.GetFeatureNamesOut(inputNames) {
+			names = append(names, step.Name+"__"+featureName)
+		}
+	}
+	return names
+}
+
+func (u *FeatureUnion) transformer(i int) base.Transformer {
+	step := u.TransformerList[i]
+	transformer, ok := step.Fiter.(base.Transformer)
+	if !ok {
+		panic(fmt.Errorf("FeatureUnion: %s is not a Transformer", step.Name))
+	}
+	return transformer
+}
+
+// apply runs each transformer on X concurrently and stacks their weighted outputs horizontally
+func (u *FeatureUnion) apply(X, Y mat.Matrix, fit bool) *mat.Dense {
+	outputs := make([]*mat.Dense, len(u.TransformerList))
+	base.Parallelize(u.NJobs, len(u.TransformerList), func(th, start, end int) {
+		for i := start; i < end; i++ {
+			if fit {
+				outputs[i], _ = u.transformer(i).FitTransform(X, Y)
+			} else {
+				outputs[i], _ = u.transformer(i).Transform(X, Y)
+			}
+			if weight, ok := u.TransformerWeights[u.TransformerList[i].Name]; ok {
+				// the output may be X itself, which must not be modified
+				scaled := &mat.Dense{}
+				scaled.Scale(weight, outputs[i])
+				outputs[i] = scaled
+			}
+		}
+	})
+	return hstack(outputs...)
+}
+
+// hstack returns the horizontal concatenation of matrices having the same number of rows
+func hstack(matrices ...*mat.Dense) *mat.Dense {
+	if len(matrices) == 0 {
+		panic(fmt.Errorf("hstack: no matrix to stack"))
+	}
+	nRows, nCols := matrices[0].Dims()
+	for _, m := range matrices[1:] {
+		r, c := m.Dims()
+		if r != nRows {
+			panic(fmt.Errorf("hstack: matrices have different number of rows %d and %d", nRows, r))
+		}
+		nCols += c
+	}
+	res := mat.NewDense(nRows, nCols, nil)
+	col := 0
+	for _, m := range matrices {
+		_, c := m.Dims()
+		res.Slice(0, nRows, col, col+c).(*mat.Dense).Copy(m)
+		col += c
+	}
+	return res
+}
@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"fmt"
+
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
+	"github.com/RobinRCM/sklearn/preprocessing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleFeatureUnion() {
+	X := mat.NewDense(6, 2, []float64{
+		0, 1,
+		1, 3,
+		2, 2,
+		3, 5,
+		4, 4,
+		5, 6,
+	})
+	pca := preprocessing.NewPCA()
+	pca.NComponents = 1
+	poly := preprocessing.NewPolynomialFeatures(2)
+	poly.IncludeBias = false
+	bins := preprocessing.NewKBinsDiscretizer(2)
+	bins.Strategy = "uniform"
+	bins.Encode = "ordinal"
+	union := NewFeatureUnion(NamedStep{Name: "pca", Fiter: pca}, NamedStep{Name: "poly", Fiter: poly}, NamedStep{Name: "bins", Fiter: bins})
+	union.TransformerWeights = map[string]float64{"bins": 10}
+	Xt, _ := union.FitTransform(X, nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+
+	// a FeatureUnion is a Transformer and may be a Pipeline step
+	Y := mat.NewDense(6, 1, nil)
+	for i := 0; i < 6; i++ {
+		x0, x1 := X.At(i, 0), X.At(i, 1)
+		Y.Set(i, 0, 1+x0*x1-x1*x1)
+	}
+	pl := NewPipeline(NamedStep{Name: "union", Fiter: MakeUnion(preprocessing.NewStandardScaler(), preprocessing.NewPolynomialFeatures(2))}, NamedStep{Name: "regression", Fiter: linearmodel.NewLinearRegression()})
+	pl = pl.PredicterClone().(*Pipeline)
+	pl.Fit(X, Y)
+	fmt.Printf("R2: %.3f\n", pl.Score(X, Y))
+	// Output:
+	// ⎡-3.536   0.000   1.000   0.000   0.000   1.000   0.000   0.000⎤
+	// ⎢-1.414   1.000   3.000   1.000   3.000   9.000   0.000   0.000⎥
+	// ⎢-1.414   2.000   2.000   4.000   4.000   4.000   0.000   0.000⎥
+	// ⎢ 1.414   3.000   5.000   9.000  15.000  25.000  10.000  10.000⎥
+	// ⎢ 1.414   4.000   4.000  16.000  16.000  16.000  10.000  10.000⎥
+	// ⎣ 3.536   5.000   6.000  25.000  30.000  36.000  10.000  10.000⎦
+	// R2: 1.000
+}
+
+func ExampleFeatureUnion_passthrough() {
+	X := mat.NewDense(3, 2, []float64{
+		0, 1,
+		2, 3,
+		4, 5,
+	})
+	identity := preprocessing.NewFunctionTransformer(
+		func(X, Y *mat.Dense) (*mat.Dense, *mat.Dense) { return X, Y },
+		func(X, Y *mat.Dense) (*mat.Dense, *mat.Dense) { return X, Y },
+	)
+	scaler := preprocessing.NewStandardScaler()
+	union := NewFeatureUnion(NamedStep{Name: "id", Fiter: identity}, NamedStep{Name: "scaler", Fiter: scaler})
+	union.TransformerWeights = map[string]float64{"id": 10}
+	Xt, _ := union.FitTransform(X, nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+	// the weighted passthrough does not modify X
+	fmt.Printf("%g\n", mat.Formatted(X))
+	// Output:
+	// ⎡ 0.000  10.000  -1.225  -1.225⎤
+	// ⎢20.000  30.000   0.000   0.000⎥
+	// ⎣40.000  50.000   1.225   1.225⎦
+	// ⎡0  1⎤
+	// ⎢2  3⎥
+	// ⎣4  5⎦
+}
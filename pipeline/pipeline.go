@@ -128,7 +128,9 @@ func (p *Pipeline) Predict(X mat.Matrix, Y mat.Mutable) *mat.Dense {
 	}
 	for iStep := len(p.NamedSteps) - 2; iStep >= 0; iStep-- {
 		step := p.NamedSteps[iStep]
-		_, Ytmp = step.Fiter.(preprocessing.InverseTransformer).InverseTransform(nil, Ytmp)
+		if inverseTransformer, ok := step.Fiter.(preprocessing.InverseTransformer); ok {
+			_, Ytmp = inverseTransformer.InverseTransform(nil, Ytmp)
+		}
 	}
 	return base.FromDense(Y, base.ToDense(Ytmp))
 }
@@ -148,6 +150,23 @@ func (p *Pipeline) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return p.Transform(X, Y)
 }
 
+// GetFeatureNamesOut passes inputNames through the GetFeatureNamesOut of each transformer step, ignoring a final
+// Predicter step, so that it returns the names of the features seen by the final estimator
+func (p *Pipeline) GetFeatureNamesOut(inputNames []string) []string {
+	names := inputNames
+	for istep, step := range p.NamedSteps {
+		if _, ok := step.Fiter.(base.Transformer); !ok && istep == len(p.NamedSteps)-1 {
+			break
+		}
+		namer, ok := step.Fiter.(base.FeatureNamesOuter)
+		if !ok {
+			panic(fmt.Errorf("pipeline step %d (%s) has no GetFeatureNamesOut", istep, step.Name))
+		}
+		names = namer.GetFeatureNamesOut(names)
+	}
+	return names
+}
+
 // MakePipeline returns a Pipeline from unnamed steps
 func MakePipeline(steps ...base.Fiter) *Pipeline {
 	p := &Pipeline{}
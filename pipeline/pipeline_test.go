@@ -2,10 +2,12 @@ package pipeline
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/RobinRCM/sklearn/base"
 
 	"github.com/RobinRCM/sklearn/datasets"
+	linearmodel "github.com/RobinRCM/sklearn/linear_model"
 	nn "github.com/RobinRCM/sklearn/neural_network"
 	"github.com/RobinRCM/sklearn/preprocessing"
 	"golang.org/x/exp/rand"
@@ -59,3 +61,41 @@ func ExamplePipeline() {
 	// accuracy>0.999 ? true
 
 }
+
+func ExamplePipeline_ordinalEncoder() {
+	// the categories 10, 20, 30 of x0 are encoded as 0, 1, 2
+	X := mat.NewDense(6, 2, []float64{10, 1, 20, 0, 30, 1, 10, 0, 20, 1, 30, 0})
+	Y := mat.NewDense(6, 1, nil)
+	for i := 0; i < 6; i++ {
+		Y.Set(i, 0, 2*float64(i%3)+X.At(i, 1))
+	}
+	pl := MakePipeline(preprocessing.NewOrdinalEncoder(), linearmodel.NewLinearRegression())
+	pl.Fit(X, Y)
+	fmt.Printf("%.3f\n", mat.Formatted(pl.Predict(X, mat.NewDense(6, 1, nil)).T()))
+	// Output:
+	// [1.000  2.000  5.000  0.000  3.000  4.000]
+}
+
+func ExamplePipeline_imputer() {
+	nan := math.NaN()
+	X := mat.NewDense(6, 2, []float64{0, 1, 1, nan, 2, 3, 3, 4, nan, 5, 5, 6})
+	Y := mat.NewDense(6, 1, []float64{1, 2, 5, 7, 9, 11})
+	imputer := preprocessing.NewImputer()
+	imputer.AddIndicator = true
+	pl := MakePipeline(imputer, linearmodel.NewLinearRegression())
+	pl.Fit(X, Y)
+	fmt.Printf("%.3f\n", mat.Formatted(pl.Predict(X, mat.NewDense(6, 1, nil)).T()))
+	// Output:
+	// [ 1.000   2.000   5.000   7.000   9.000  11.000]
+}
+
+func ExamplePipeline_oneHotEncoder() {
+	// the categories 10, 20, 30 of x0 have the effects 0, 2, 5
+	X := mat.NewDense(6, 1, []float64{10, 20, 30, 10, 20, 30})
+	Y := mat.NewDense(6, 1, []float64{0, 2, 5, 0, 2, 5})
+	pl := MakePipeline(preprocessing.NewOneHotEncoder(), linearmodel.NewLinearRegression())
+	pl.Fit(X, Y)
+	fmt.Printf("%.3f\n", mat.Formatted(pl.Predict(X, mat.NewDense(6, 1, nil)).T()))
+	// Output:
+	// [0.000  2.000  5.000  0.000  2.000  5.000]
+}
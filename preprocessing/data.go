@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 
 	"golang.org/x/exp/rand"
 
@@ -26,9 +27,12 @@ type InverseTransformer interface {
 	InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense)
 }
 
-// MinMaxScaler rescale data between FeatureRange
+// MinMaxScaler rescale data between FeatureRange.
+// NaN values are ignored by Fit and kept by Transform. if SampleWeight is not nil, samples of null weight are ignored.
+// SampleWeight is tied to the samples passed to Fit, so it is not copied by TransformerClone
 type MinMaxScaler struct {
 	FeatureRange                            []float
+	SampleWeight                            []float64
 	Scale, Min, DataMin, DataMax, DataRange *mat.Dense
 	NSamplesSeen                            int
 }
@@ -41,6 +45,7 @@ func NewMinMaxScaler(featureRange []float) *MinMaxScaler {
 // TransformerClone ...
 func (scaler *MinMaxScaler) TransformerClone() base.Transformer {
 	var clone = *scaler
+	clone.SampleWeight = nil
 	return &clone
 }
 
@@ -70,14 +75,30 @@ func (scaler *MinMaxScaler) PartialFit(Xmatrix, Ymatrix mat.Matrix) Transformer
 		scaler.Min = mat.NewDense(1, nFeatures, nil)
 		scaler.Scale = mat.NewDense(1, nFeatures, nil)
 
-		scaler.DataMin.CloneFrom(X.RowView(0).T())
-		scaler.DataMax.CloneFrom(X.RowView(0).T())
+		// DataMin and DataMax stay NaN for features without any value
+		for j := 0; j < nFeatures; j++ {
+			scaler.DataMin.Set(0, j, math.NaN())
+			scaler.DataMax.Set(0, j, math.NaN())
+		}
+	}
+	checkSampleWeight(scaler.SampleWeight, nSamples)
+	for i := 0; i < nSamples; i++ {
+		if sampleWeightAt(scaler.SampleWeight, i) == 0 {
+			continue
+		}
+		for j := 0; j < nFeatures; j++ {
+			x := X.At(i, j)
+			if math.IsNaN(x) {
+				continue
+			}
+			if min := scaler.DataMin.At(0, j); math.IsNaN(min) || x < min {
+				scaler.DataMin.Set(0, j, x)
+			}
+			if max := scaler.DataMax.At(0, j); math.IsNaN(max) || x > max {
+				scaler.DataMax.Set(0, j, x)
+			}
+		}
 	}
-	X.Apply(func(i int, j int, x float64) float64 {
-		scaler.DataMin.Set(0, j, math.Min(scaler.DataMin.At(0, j), x))
-		scaler.DataMax.Set(0, j, math.Max(scaler.DataMax.At(0, j), x))
-		return x
-	}, X)
 	scaler.NSamplesSeen += nSamples
 	// dataRange = dataMax - dataMin
 	scaler.DataRange.Sub(scaler.DataMax, scaler.DataMin)
@@ -115,6 +136,11 @@ func (scaler *MinMaxScaler) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense
 	return scaler.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (scaler *MinMaxScaler) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, scaler.Scale.RawMatrix().Cols)
+}
+
 // InverseTransform rescale data into original bounds
 func (scaler *MinMaxScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	if X == nil {
@@ -128,11 +154,16 @@ func (scaler *MinMaxScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.D
 	return Xout, Y
 }
 
-// StandardScaler scales data by removing Mean and dividing by stddev
+// StandardScaler scales data by removing Mean and dividing by stddev.
+// NaN values are ignored by Fit and kept by Transform. SampleWeight, if not nil, holds the weights of the samples passed
+// to Fit or PartialFit. NSamplesSeenByFeature is the weighted count of non-NaN values of each feature.
+// SampleWeight is tied to the samples passed to Fit, so it is not copied by TransformerClone
 type StandardScaler struct {
-	WithMean, WithStd bool
-	Scale, Mean, Var  *mat.Dense
-	NSamplesSeen      int
+	WithMean, WithStd     bool
+	SampleWeight          []float64
+	Scale, Mean, Var      *mat.Dense
+	NSamplesSeen          int
+	NSamplesSeenByFeature []float64
 }
 
 // NewStandardScaler creates a *StandardScaler
@@ -143,6 +174,7 @@ func NewStandardScaler() *StandardScaler {
 // TransformerClone ...
 func (scaler *StandardScaler) TransformerClone() base.Transformer {
 	var clone = *scaler
+	clone.SampleWeight = nil
 	return &clone
 }
 
@@ -169,9 +201,10 @@ func (scaler *StandardScaler) PartialFit(X, Y *mat.Dense) Transformer {
 		scaler.Var = mat.NewDense(1, nFeatures, nil)
 		scaler.Mean = mat.NewDense(1, nFeatures, nil)
 		scaler.Scale = mat.NewDense(1, nFeatures, nil)
-
+		scaler.NSamplesSeenByFeature = make([]float64, nFeatures)
 	}
-	scaler.Mean, scaler.Var, scaler.NSamplesSeen = IncrementalMeanAndVar(X, scaler.Mean, scaler.Var, scaler.NSamplesSeen)
+	scaler.Mean, scaler.Var, scaler.NSamplesSeenByFeature = IncrementalMeanAndVarNaN(X, scaler.Mean, scaler.Var, scaler.NSamplesSeenByFeature, scaler.SampleWeight)
+	scaler.NSamplesSeen += nSamples
 	scaler.Scale.Apply(func(i int, j int, vj float64) float64 {
 		if vj == 0. {
 			vj = 1.
@@ -208,6 +241,11 @@ func (scaler *StandardScaler) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Den
 	return scaler.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (scaler *StandardScaler) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, scaler.Scale.RawMatrix().Cols)
+}
+
 // InverseTransform unscales data
 func (scaler *StandardScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	if X == nil {
@@ -306,13 +344,16 @@ func (m NumpyLike) Std(X mat.Matrix) *mat.Dense {
 // removing outliers by Quantile. See python sklearn's RobustScaler
 // http://scikit-learn.org/stable/modules/generated/sklearn.preprocessing.RobustScaler.html.
 //
+// NaN values are ignored by Fit and kept by Transform. if SampleWeight is not nil, Median and Quantiles are weighted.
+// SampleWeight is tied to the samples passed to Fit, so it is not copied by TransformerClone
 type RobustScaler struct {
 	Center          bool
 	Scale           bool
 	Quantiles       *QuantilePair
+	SampleWeight    []float64
 	Median          *mat.Dense
-	Tmp             *mat.Dense
 	QuantileDivider *mat.Dense
+	NFeaturesIn     int
 }
 
 // QuantilePair represents bounds of quantile
@@ -329,6 +370,7 @@ func NewDefaultRobustScaler() *RobustScaler {
 // TransformerClone ...
 func (scaler *RobustScaler) TransformerClone() base.Transformer {
 	var clone = *scaler
+	clone.SampleWeight = nil
 	return &clone
 }
 
@@ -368,21 +410,22 @@ func (scaler *RobustScaler) PartialFit(Xmatrix, Ymatrix mat.Matrix) Transformer
 	if scaler.Scale && (scaler.QuantileDivider == nil) {
 		scaler.QuantileDivider = mat.NewDense(1, nFeatures, nil)
 	}
-	if scaler.Tmp == nil {
-		scaler.Tmp = mat.NewDense(1, nSamples, nil)
-	}
+	scaler.NFeaturesIn = nFeatures
 
 	for c := 0; c < nFeatures; c++ {
-		for r := 0; r < nSamples; r++ {
-			scaler.Tmp.Set(0, r, X.At(r, c))
+		values, weights := nanSortedColumn(X, c, scaler.SampleWeight)
+		quantile := func(p float64) float64 {
+			if len(values) == 0 {
+				return math.NaN()
+			}
+			return stat.Quantile(p, stat.Empirical, values, weights)
 		}
-		sort.Float64s(scaler.Tmp.RawRowView(0))
 		if scaler.Center {
-			scaler.Median.Set(0, c, stat.Quantile(0.5, stat.Empirical, scaler.Tmp.RawRowView(0), nil))
+			scaler.Median.Set(0, c, quantile(0.5))
 		}
 		if scaler.Scale {
-			q1 := stat.Quantile(scaler.Quantiles.Left, stat.Empirical, scaler.Tmp.RawRowView(0), nil)
-			q2 := stat.Quantile(scaler.Quantiles.Right, stat.Empirical, scaler.Tmp.RawRowView(0), nil)
+			q1 := quantile(scaler.Quantiles.Left)
+			q2 := quantile(scaler.Quantiles.Right)
 			scaler.QuantileDivider.Set(0, c, q2-q1)
 			scaler.QuantileDivider.Apply(
 				func(r int, c int, v float64) float64 {
@@ -420,6 +463,11 @@ func (scaler *RobustScaler) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense
 	return scaler.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (scaler *RobustScaler) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, scaler.NFeaturesIn)
+}
+
 // InverseTransform unscales data
 func (scaler *RobustScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	if X == nil {
@@ -471,71 +519,107 @@ func (scaler *RobustScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.D
 // `utils.sparsefuncs.incrMeanVarianceAxis` and
 // `utils.sparsefuncsFast.incrMeanVarianceAxis0`
 // """
+// NaN values of X are ignored, see IncrementalMeanAndVarNaN to keep track of the count of values of each feature
 func IncrementalMeanAndVar(X, lastMean, lastVariance *mat.Dense,
 	lastSampleCount int) (updatedMean, updatedVariance *mat.Dense, updatedSampleCount int) {
-	// # old = stats until now
-	// # new = the current increment
-	// # updated = the aggregated stats
-	//lastSum := lastMean * lastSampleCount
 	newSampleCount, nFeatures := X.Dims()
-	lastSum := mat.DenseCopyOf(lastMean)
-	lastSum.Scale(float(lastSampleCount), lastSum)
-	// new sum
-	newSum := mat.NewDense(1, nFeatures, nil)
-	for i := 0; i < newSampleCount; i++ {
-		newSum.Add(newSum, X.RowView(i).T())
+	lastCounts := make([]float64, nFeatures)
+	for j := range lastCounts {
+		lastCounts[j] = float64(lastSampleCount)
 	}
+	updatedMean, updatedVariance, _ = IncrementalMeanAndVarNaN(X, lastMean, lastVariance, lastCounts, nil)
+	return updatedMean, updatedVariance, lastSampleCount + newSampleCount
+}
 
-	updatedSampleCount = lastSampleCount + newSampleCount
-
-	//updatedMean = (lastSum + newSum) / updatedSampleCount
+// IncrementalMeanAndVarNaN is IncrementalMeanAndVar ignoring NaN values and weighting samples by sampleWeight (nil
+// for unit weights). lastSampleCount and updatedSampleCount are the weighted counts of non-NaN values of each feature.
+// the statistics of a feature without any value are left unchanged
+func IncrementalMeanAndVarNaN(X mat.Matrix, lastMean, lastVariance *mat.Dense,
+	lastSampleCount []float64, sampleWeight []float64) (updatedMean, updatedVariance *mat.Dense, updatedSampleCount []float64) {
+	nSamples, nFeatures := X.Dims()
+	checkSampleWeight(sampleWeight, nSamples)
 	updatedMean = mat.NewDense(1, nFeatures, nil)
-	updatedMean.Add(lastSum, newSum)
-	updatedMean.Scale(1./float(updatedSampleCount), updatedMean)
+	updatedVariance = mat.NewDense(1, nFeatures, nil)
+	updatedSampleCount = make([]float64, nFeatures)
+	col := make([]float64, nSamples)
+	for j := 0; j < nFeatures; j++ {
+		lastCount, lastMeanj, lastVarj := lastSampleCount[j], lastMean.At(0, j), lastVariance.At(0, j)
+		mat.Col(col, j, X)
+		var newCount, newSum float64
+		for i, x := range col {
+			if !math.IsNaN(x) {
+				w := sampleWeightAt(sampleWeight, i)
+				newCount += w
+				newSum += w * x
+			}
+		}
+		if newCount == 0 {
+			updatedMean.Set(0, j, lastMeanj)
+			updatedVariance.Set(0, j, lastVarj)
+			updatedSampleCount[j] = lastCount
+			continue
+		}
+		newMean := newSum / newCount
+		var newUnnormalizedVariance float64
+		for i, x := range col {
+			if !math.IsNaN(x) {
+				newUnnormalizedVariance += sampleWeightAt(sampleWeight, i) * (x - newMean) * (x - newMean)
+			}
+		}
+		count := lastCount + newCount
+		unnormalizedVariance := newUnnormalizedVariance
+		if lastCount > 0 {
+			unnormalizedVariance += lastVarj*lastCount + lastCount*newCount/count*(lastMeanj-newMean)*(lastMeanj-newMean)
+		}
+		updatedMean.Set(0, j, (lastMeanj*lastCount+newSum)/count)
+		updatedVariance.Set(0, j, unnormalizedVariance/count)
+		updatedSampleCount[j] = count
+	}
+	return
+}
 
-	//newUnnormalizedVariance = X.var(axis=0) * newSampleCount
-	newUnnormalizedVariance := mat.NewDense(1, nFeatures, nil)
-	updatedUnnormalizedVariance := mat.NewDense(1, nFeatures, nil)
-	newMean := mat.NewDense(1, nFeatures, nil)
-	newMean.Scale(1./float(newSampleCount), newSum)
+// checkSampleWeight panics if sampleWeight is neither nil nor of length nSamples
+func checkSampleWeight(sampleWeight []float64, nSamples int) {
+	if sampleWeight != nil && len(sampleWeight) != nSamples {
+		panic(fmt.Errorf("SampleWeight has %d elements, expected %d", len(sampleWeight), nSamples))
+	}
+}
 
-	tmp := mat.NewDense(1, nFeatures, nil)
-	for i := 0; i < newSampleCount; i++ {
-		tmp.Sub(X.RowView(i).T(), newMean)
-		tmp.MulElem(tmp, tmp)
-		newUnnormalizedVariance.Add(newUnnormalizedVariance, tmp)
+// sampleWeightAt returns the weight of sample i, 1 if sampleWeight is nil
+func sampleWeightAt(sampleWeight []float64, i int) float64 {
+	if sampleWeight == nil {
+		return 1
 	}
+	return sampleWeight[i]
+}
 
-	if lastSampleCount == 0 { //# Avoid division by 0
-		updatedUnnormalizedVariance.CloneFrom(newUnnormalizedVariance)
+// nanSortedColumn returns the sorted non-NaN values of column j of X and their weights (nil if sampleWeight is nil)
+func nanSortedColumn(X mat.Matrix, j int, sampleWeight []float64) (values, weights []float64) {
+	nSamples, _ := X.Dims()
+	checkSampleWeight(sampleWeight, nSamples)
+	for i := 0; i < nSamples; i++ {
+		if x := X.At(i, j); !math.IsNaN(x) {
+			values = append(values, x)
+			if sampleWeight != nil {
+				weights = append(weights, sampleWeight[i])
+			}
+		}
+	}
+	if weights == nil {
+		sort.Float64s(values)
 	} else {
-		lastOverNewCount := float(lastSampleCount) / float(newSampleCount)
-		//lastUnnormalizedVariance := lastVariance * lastSampleCount
-		lastUnnormalizedVariance := mat.NewDense(1, nFeatures, nil)
-		lastUnnormalizedVariance.CloneFrom(lastVariance)
-		lastUnnormalizedVariance.Scale(float(lastSampleCount), lastUnnormalizedVariance)
-
-		// updatedUnnormalizedVariance = (
-		//     lastUnnormalizedVariance +
-		//     newUnnormalizedVariance +
-		//     lastOverNewCount / updatedSampleCount *
-		//     (lastSum / lastOverNewCount - newSum) ** 2)
-		tmp.CloneFrom(lastSum)
-		tmp.Scale(1./lastOverNewCount, tmp)
-		tmp.Add(tmp, newSum)
-		tmp.Mul(tmp, tmp)
-		tmp.Scale(lastOverNewCount/float(updatedSampleCount), tmp)
-
-		updatedUnnormalizedVariance.CloneFrom(lastUnnormalizedVariance)
-		updatedUnnormalizedVariance.Add(updatedUnnormalizedVariance, newUnnormalizedVariance)
-		updatedUnnormalizedVariance.Add(updatedUnnormalizedVariance, tmp)
-	}
-	//updatedVariance = updatedUnnormalizedVariance / updatedSampleCount
-	updatedVariance = mat.NewDense(1, nFeatures, nil)
-	updatedVariance.CloneFrom(updatedUnnormalizedVariance)
-	updatedVariance.Scale(1./float(updatedSampleCount), updatedVariance)
+		sort.Sort(weightedValues{values, weights})
+	}
+	return
+}
+
+type weightedValues struct{ values, weights []float64 }
 
-	return updatedMean, updatedVariance, updatedSampleCount
+func (s weightedValues) Len() int           { return len(s.values) }
+func (s weightedValues) Less(i, j int) bool { return s.values[i] < s.values[j] }
+func (s weightedValues) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.weights[i], s.weights[j] = s.weights[j], s.weights[i]
 }
 
 // PolynomialFeatures struct
@@ -602,6 +686,33 @@ func (poly *PolynomialFeatures) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.D
 	return poly.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns the names of the monomials given by Powers, like 1, x0, x0^2 or x0 x1.
+// inputNames defaults to x0, x1...
+func (poly *PolynomialFeatures) GetFeatureNamesOut(inputNames []string) []string {
+	nFeatures := 0
+	if len(poly.Powers) > 0 {
+		nFeatures = len(poly.Powers[0])
+	}
+	inputNames = base.FeatureNamesIn(inputNames, nFeatures)
+	names := make([]string, len(poly.Powers))
+	for i, p := range poly.Powers {
+		var factors []string
+		for j, pj := range p {
+			switch {
+			case pj == 1:
+				factors = append(factors, inputNames[j])
+			case pj > 1:
+				factors = append(factors, fmt.Sprintf("%s^%d", inputNames[j], pj))
+			}
+		}
+		names[i] = strings.Join(factors, " ")
+		if len(factors) == 0 {
+			names[i] = "1"
+		}
+	}
+	return names
+}
+
 // InverseTransform inverse tranformation for PolynomialFeatures.
 func (poly *PolynomialFeatures) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	if X == nil {
@@ -678,117 +789,11 @@ func AddDummyFeature(X *mat.Dense) {
 	X.CloneFrom(X1)
 }
 
-// OneHotEncoder Encode categorical integer features using a one-hot aka one-of-K scheme.
-type OneHotEncoder struct {
-	NValues, FeatureIndices []int
-	Values                  [][]float64
-}
-
-// NewOneHotEncoder creates a *OneHotEncoder
-func NewOneHotEncoder() *OneHotEncoder {
-	return &OneHotEncoder{}
-}
-
-// TransformerClone ...
-func (m *OneHotEncoder) TransformerClone() base.Transformer {
-	var clone = *m
-	return &clone
-}
-
-// Fit ...
-func (m *OneHotEncoder) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
-	X := base.ToDense(Xmatrix)
-	NSamples, NFeatures := X.Dims()
-	m.NValues = make([]int, NFeatures)
-	m.FeatureIndices = make([]int, NFeatures+1)
-	m.Values = make([][]float64, NFeatures)
-	for feature := 0; feature < NFeatures; feature++ {
-		cmap := make(map[float64]bool)
-		for sample := 0; sample < NSamples; sample++ {
-			val := X.At(sample, feature)
-			cmap[val] = true
-		}
-		m.NValues[feature] = len(cmap)
-		m.FeatureIndices[feature+1] = m.FeatureIndices[feature] + len(cmap)
-		vals := make([]float64, len(cmap))
-		ival := 0
-		for v := range cmap {
-			vals[ival] = v
-			ival++
-		}
-		sort.Float64s(vals)
-		m.Values[feature] = vals
-	}
-	return m
-}
-
-// Transform transform Y labels to one hot encoded format
-func (m *OneHotEncoder) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
-	NSamples, nfeatures := X.Dims()
-	Yout = base.ToDense(Y)
-	columns := 0
-	for feature := 0; feature < nfeatures; feature++ {
-		ovo := false
-		if ovo {
-			columns++
-		} else {
-			columns += m.NValues[feature]
-		}
-	}
-	Xout = mat.NewDense(NSamples, columns, nil)
-	baseColumn := 0
-	for feature := 0; feature < nfeatures; feature++ {
-		ovo := false
-		if ovo {
-			for sample := 0; sample < NSamples; sample++ {
-				v := Y.At(sample, feature)
-				Yout.Set(sample, baseColumn, v)
-			}
-			baseColumn++
-			break
-		}
-		cmap := make(map[float64]int)
-		for i, v := range m.Values[feature] {
-			cmap[v] = i
-		}
-		for sample := 0; sample < NSamples; sample++ {
-			v := X.At(sample, feature)
-			//fmt.Printf("sample %d feature %d baseColumn %d nClasses %d y %g min:%d yint %d\n", sample, feature, baseColumn, m.NumClasses[feature], Y.At(sample, feature), m.Min[feature], yint)
-			Xout.Set(sample, baseColumn+cmap[v], 1.)
-		}
-		baseColumn += m.NValues[feature]
-	}
-	return
-}
-
-// FitTransform fit to dat, then transform it
-func (m *OneHotEncoder) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
-	m.Fit(X, Y)
-	return m.Transform(X, Y)
-}
-
-// InverseTransform compute Yout classes from one hot encoded format
-func (m *OneHotEncoder) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
-	nSamples, _ := X.Dims()
-	nFeatures := len(m.NValues)
-	Yout = Y
-	Xout = mat.NewDense(nSamples, nFeatures, nil)
-
-	for feature := 0; feature < nFeatures; feature++ {
-		cstart, cend := m.FeatureIndices[feature], m.FeatureIndices[feature+1]
-		for sample := 0; sample < nSamples; sample++ {
-			classNo := floats.MaxIdx(X.RawRowView(sample)[cstart:cend])
-			Xout.Set(sample, feature, float64(classNo))
-		}
-	}
-	return
-
-}
-
 // Shuffler shuffles rows of X and Y
 type Shuffler struct {
 	Perm        []int
 	RandomState base.Source
+	NFeaturesIn int
 }
 
 // NewShuffler returns a *Shuffler
@@ -808,6 +813,7 @@ func (m *Shuffler) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 		Perm = rand.New(m.RandomState).Perm
 	}
 	m.Perm = Perm(X.RawMatrix().Rows)
+	_, m.NFeaturesIn = X.Dims()
 	return m
 }
 
@@ -841,6 +847,11 @@ func (m *Shuffler) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *Shuffler) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+}
+
 // InverseTransform for Shuffler
 func (m *Shuffler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	xmat, ymat := X.RawMatrix(), Y.RawMatrix()
@@ -868,7 +879,10 @@ func (m *Shuffler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 }
 
 // Binarizer Binarize data (set feature values to 0 or 1) according to a threshold
-type Binarizer struct{ Threshold float64 }
+type Binarizer struct {
+	Threshold   float64
+	NFeaturesIn int
+}
 
 // NewBinarizer ...
 func NewBinarizer() *Binarizer { return &Binarizer{} }
@@ -879,8 +893,9 @@ func (m *Binarizer) TransformerClone() base.Transformer {
 	return &clone
 }
 
-// Fit for binarizer does nothing
+// Fit for binarizer only records the number of features
 func (m *Binarizer) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	_, m.NFeaturesIn = Xmatrix.Dims()
 	return m
 }
 
@@ -909,8 +924,16 @@ func (m *Binarizer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
-// MaxAbsScaler ...
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *Binarizer) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+}
+
+// MaxAbsScaler scales each feature by its maximum absolute value.
+// NaN values are ignored by Fit and kept by Transform. if SampleWeight is not nil, samples of null weight are ignored.
+// SampleWeight is tied to the samples passed to Fit, so it is not copied by TransformerClone
 type MaxAbsScaler struct {
+	SampleWeight  []float64
 	Scale, MaxAbs []float64
 	NSamplesSeen  int
 }
@@ -921,26 +944,34 @@ func NewMaxAbsScaler() *MaxAbsScaler { return &MaxAbsScaler{} }
 // TransformerClone ...
 func (m *MaxAbsScaler) TransformerClone() base.Transformer {
 	var clone = *m
+	clone.SampleWeight = nil
 	return &clone
 }
 
 // Fit for MaxAbsScaler ...
 func (m *MaxAbsScaler) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymatrix)
-	Xmat := X.RawMatrix()
-	m.MaxAbs = make([]float64, Xmat.Cols)
-	m.Scale = make([]float64, Xmat.Cols)
+	m.MaxAbs, m.Scale, m.NSamplesSeen = nil, nil, 0
 	return m.PartialFit(X, Y)
 }
 
 // PartialFit for MaxAbsScaler ...
 func (m *MaxAbsScaler) PartialFit(X, Y *mat.Dense) base.Transformer {
 	Xmat := X.RawMatrix()
-	for jX := 0; jX < Xmat.Rows*Xmat.Stride; jX = jX + Xmat.Stride {
+	if m.MaxAbs == nil {
+		m.MaxAbs = make([]float64, Xmat.Cols)
+		m.Scale = make([]float64, Xmat.Cols)
+	}
+	checkSampleWeight(m.SampleWeight, Xmat.Rows)
+	for r, jX := 0, 0; jX < Xmat.Rows*Xmat.Stride; r, jX = r+1, jX+Xmat.Stride {
+		if sampleWeightAt(m.SampleWeight, r) == 0 {
+			continue
+		}
 		for i, v := range Xmat.Data[jX : jX+Xmat.Cols] {
 			if v < 0. {
 				v = -v
 			}
+			// NaN values are never greater
 			if v > m.MaxAbs[i] {
 				m.MaxAbs[i] = v
 			}
@@ -977,6 +1008,11 @@ func (m *MaxAbsScaler) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *MaxAbsScaler) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, len(m.Scale))
+}
+
 // InverseTransform for MaxAbsScaler ...
 func (m *MaxAbsScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	Xmat := X.RawMatrix()
@@ -994,9 +1030,10 @@ func (m *MaxAbsScaler) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense)
 // Normalizer Normalize samples individually to unit norm.
 // Norm is l1|l2|max. l2 by default
 type Normalizer struct {
-	Norm      string
-	Axis      int
-	nrmValues []float64
+	Norm        string
+	Axis        int
+	NFeaturesIn int
+	nrmValues   []float64
 }
 
 // NewNormalizer returns a normaliser with Norm l2 and axis 1
@@ -1009,7 +1046,10 @@ func (m *Normalizer) TransformerClone() base.Transformer {
 }
 
 // Fit for Normalizer ...
-func (m *Normalizer) Fit(X, Y mat.Matrix) base.Fiter { return m }
+func (m *Normalizer) Fit(X, Y mat.Matrix) base.Fiter {
+	_, m.NFeaturesIn = X.Dims()
+	return m
+}
 
 // Transform for Normalizer ...
 func (m *Normalizer) Transform(Xmatrix, Y mat.Matrix) (Xout, Yout *mat.Dense) {
@@ -1059,6 +1099,11 @@ func (m *Normalizer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *Normalizer) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+}
+
 // InverseTransform for Normalizer ...
 func (m *Normalizer) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	NSamples, NFeatures := X.Dims()
@@ -1127,7 +1172,7 @@ func (m *KernelCenterer) Transform(Xmatrix, Y mat.Matrix) (Xout, Yout *mat.Dense
 		}
 	})
 	Xmat := X.RawMatrix()
-	Xout = mat.NewDense(Xmat.Cols, Xmat.Cols, nil)
+	Xout = mat.NewDense(Xmat.Rows, Xmat.Cols, nil)
 	Xoutmat := Xout.RawMatrix()
 	for j, jX, jXout := 0, 0, 0; j < Xmat.Rows; j, jX, jXout = j+1, jX+Xmat.Stride, jXout+Xoutmat.Stride {
 		for i, v := range Xmat.Data[jX : jX+Xmat.Cols] {
@@ -1144,14 +1189,23 @@ func (m *KernelCenterer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *KernelCenterer) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, len(m.KFitRows))
+}
+
 // QuantileTransformer Transform features using quantiles information.
+// NaN values are ignored by Fit and kept by Transform. if SampleWeight is not nil, Quantiles are weighted.
+// SampleWeight is tied to the samples passed to Fit, so it is not copied by TransformerClone
 type QuantileTransformer struct {
 	NQuantiles         int
 	Subsample          int
 	OutputDistribution string
 	RandomState        rand.Source
+	SampleWeight       []float64
 	references         []float64
 	Quantiles          mat.Matrix
+	NFeaturesIn        int
 }
 
 // NewQuantileTransformer returns a new QuantileTransformer
@@ -1161,25 +1215,27 @@ func NewQuantileTransformer(NQuantiles int, outputDistribution string, RandomSta
 
 // Fit for QuantileTransformer retain X or a part of it
 func (m *QuantileTransformer) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
-	nSamples, nFeatures := Xmatrix.Dims()
-	XT := mat.DenseCopyOf(Xmatrix.T())
+	_, nFeatures := Xmatrix.Dims()
+	m.NFeaturesIn = nFeatures
 	m.references = make([]float64, m.NQuantiles)
 	Q := mat.NewDense(nFeatures, m.NQuantiles, nil)
 	for i := range m.references {
 		m.references[i] = float64(i) / float64(m.NQuantiles-1)
 	}
 	for feature := 0; feature < nFeatures; feature++ {
-		values := XT.RawRowView(feature)
+		values, weights := nanSortedColumn(Xmatrix, feature, m.SampleWeight)
+		nSamples := len(values)
 		quantiles := Q.RawRowView(feature)
-		for i := range m.references {
-			realSample := float64(i) / float64(m.NQuantiles-1) * float64(nSamples-1)
-			i0 := int(math.Min(math.Floor(realSample), float64(nSamples-1)))
-			if realSample == float(i0) || i0+1 >= len(values) {
-				quantiles[i] = values[i0]
-			} else {
-				p := realSample - float64(i0)
-				quantiles[i] = (values[i0]*(1-p) + values[i0+1]*(p))
+		for i, ref := range m.references {
+			if nSamples == 0 {
+				quantiles[i] = math.NaN()
+				continue
 			}
+			if weights != nil {
+				quantiles[i] = stat.Quantile(ref, stat.LinInterp, values, weights)
+				continue
+			}
+			quantiles[i] = percentile(values, ref)
 		}
 	}
 	m.Quantiles = Q.T()
@@ -1197,7 +1253,9 @@ func (m *QuantileTransformer) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout
 			x0, x1 := m.Quantiles.At(q, c), m.Quantiles.At(q+1, c)
 			for i := 0; i < nSamples; i++ {
 				x := X.At(i, c)
-				if x >= x0 && x <= x1 {
+				if math.IsNaN(x) {
+					Xout.Set(i, c, x)
+				} else if x >= x0 && x <= x1 {
 					Xout.Set(i, c, math.Min(m.references[m.NQuantiles-1]-eps, math.Max(m.references[0]+eps, m.references[q]+(x-x0)/(x1-x0)*(m.references[q+1]-m.references[q]))))
 				}
 			}
@@ -1212,9 +1270,15 @@ func (m *QuantileTransformer) FitTransform(Xmatrix, Ymatrix mat.Matrix) (Xout, Y
 	return m.Transform(Xmatrix, Ymatrix)
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *QuantileTransformer) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+}
+
 // TransformerClone ...
 func (m *QuantileTransformer) TransformerClone() Transformer {
 	clone := *m
+	clone.SampleWeight = nil
 	return &clone
 }
 
@@ -1323,6 +1387,11 @@ func (m *PowerTransformer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense)
 	return
 }
 
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *PowerTransformer) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(inputNames, len(m.Lambdas))
+}
+
 // InverseTransform apply the inverse power transformation using the fitted lambdas.
 // The inverse of the Box-Cox transformation is given by::
 // 	if lambda == 0:
@@ -76,6 +76,39 @@ func TestStandardScaler(t *testing.T) {
 	}
 }
 
+func TestIncrementalMeanAndVar(t *testing.T) {
+	X := mat.NewDense(5, 2, []float64{1, 2, 3, 5, 4, 7, 9, 5, 2, 1})
+	mean, variance, n := IncrementalMeanAndVar(X, mat.NewDense(1, 2, nil), mat.NewDense(1, 2, nil), 0)
+	lastMean, lastVar, lastN := IncrementalMeanAndVar(X.Slice(0, 2, 0, 2).(*mat.Dense), mat.NewDense(1, 2, nil), mat.NewDense(1, 2, nil), 0)
+	mean2, variance2, n2 := IncrementalMeanAndVar(X.Slice(2, 5, 0, 2).(*mat.Dense), lastMean, lastVar, lastN)
+	if n != n2 || !floats.EqualApprox(mean.RawRowView(0), mean2.RawRowView(0), 1e-12) || !floats.EqualApprox(variance.RawRowView(0), variance2.RawRowView(0), 1e-12) {
+		t.Errorf("expected %v %v %d, got %v %v %d", mean.RawRowView(0), variance.RawRowView(0), n, mean2.RawRowView(0), variance2.RawRowView(0), n2)
+	}
+}
+
+func TestIncrementalMeanAndVarNaN(t *testing.T) {
+	nan := math.NaN()
+	X := mat.NewDense(5, 2, []float64{1, nan, 3, 5, nan, 7, 9, 5, 2, 1})
+	sampleWeight := []float64{1, 2, 1, 1, 3}
+	mean, variance, n := IncrementalMeanAndVarNaN(X, mat.NewDense(1, 2, nil), mat.NewDense(1, 2, nil), make([]float64, 2), sampleWeight)
+	lastMean, lastVar, lastN := IncrementalMeanAndVarNaN(X.Slice(0, 2, 0, 2), mat.NewDense(1, 2, nil), mat.NewDense(1, 2, nil), make([]float64, 2), sampleWeight[:2])
+	mean2, variance2, n2 := IncrementalMeanAndVarNaN(X.Slice(2, 5, 0, 2), lastMean, lastVar, lastN, sampleWeight[2:])
+	// repeating samples is equivalent to weighting them
+	Xrepeated := mat.NewDense(8, 2, []float64{1, nan, 3, 5, 3, 5, nan, 7, 9, 5, 2, 1, 2, 1, 2, 1})
+	mean3, variance3, n3 := IncrementalMeanAndVarNaN(Xrepeated, mat.NewDense(1, 2, nil), mat.NewDense(1, 2, nil), make([]float64, 2), nil)
+	for _, got := range []struct {
+		mean, variance *mat.Dense
+		n              []float64
+	}{{mean2, variance2, n2}, {mean3, variance3, n3}} {
+		if !floats.Equal(n, got.n) || !floats.EqualApprox(mean.RawRowView(0), got.mean.RawRowView(0), 1e-12) || !floats.EqualApprox(variance.RawRowView(0), got.variance.RawRowView(0), 1e-12) {
+			t.Errorf("expected %v %v %v, got %v %v %v", mean.RawRowView(0), variance.RawRowView(0), n, got.mean.RawRowView(0), got.variance.RawRowView(0), got.n)
+		}
+	}
+	if !floats.Equal([]float64{7, 7}, n) {
+		t.Errorf("expected counts [7 7], got %v", n)
+	}
+}
+
 func TestRobustScaler(t *testing.T) {
 	m := NewDefaultRobustScaler()
 	isTransformer := func(Transformer) {}
@@ -148,6 +181,76 @@ func ExampleRobustScaler() {
 	// ⎣2.2⎦
 }
 
+func Example_scalersNaN() {
+	// NaN values are ignored by Fit and kept by Transform
+	nan := math.NaN()
+	X := mat.NewDense(5, 2, []float64{
+		1, nan,
+		2, 10,
+		nan, 20,
+		4, 30,
+		8, 40,
+	})
+	for _, scaler := range []Transformer{
+		NewStandardScaler(),
+		NewMinMaxScaler([]float64{0, 1}),
+		NewDefaultRobustScaler(),
+		NewMaxAbsScaler(),
+		NewQuantileTransformer(5, "uniform", nil),
+	} {
+		Xout, _ := scaler.FitTransform(X, nil)
+		fmt.Printf("%T\n%.3f\n", scaler, mat.Formatted(Xout))
+	}
+	// Output:
+	// *preprocessing.StandardScaler
+	// ⎡-1.026     NaN⎤
+	// ⎢-0.653  -1.342⎥
+	// ⎢   NaN  -0.447⎥
+	// ⎢ 0.093   0.447⎥
+	// ⎣ 1.585   1.342⎦
+	// *preprocessing.MinMaxScaler
+	// ⎡0.000    NaN⎤
+	// ⎢0.143  0.000⎥
+	// ⎢  NaN  0.333⎥
+	// ⎢0.429  0.667⎥
+	// ⎣1.000  1.000⎦
+	// *preprocessing.RobustScaler
+	// ⎡-0.333     NaN⎤
+	// ⎢ 0.000  -0.500⎥
+	// ⎢   NaN   0.000⎥
+	// ⎢ 0.667   0.500⎥
+	// ⎣ 2.000   1.000⎦
+	// *preprocessing.MaxAbsScaler
+	// ⎡0.125    NaN⎤
+	// ⎢0.250  0.250⎥
+	// ⎢  NaN  0.500⎥
+	// ⎢0.500  0.750⎥
+	// ⎣1.000  1.000⎦
+	// *preprocessing.QuantileTransformer
+	// ⎡0.000    NaN⎤
+	// ⎢0.300  0.000⎥
+	// ⎢  NaN  0.333⎥
+	// ⎢0.625  0.667⎥
+	// ⎣1.000  1.000⎦
+}
+
+func ExampleStandardScaler_sampleWeight() {
+	X := mat.NewDense(3, 1, []float64{1, 2, 6})
+	scaler := NewStandardScaler()
+	// the weights are equivalent to repeating the first sample 3 times
+	scaler.SampleWeight = []float64{3, 1, 1}
+	scaler.Fit(X, nil)
+	fmt.Printf("mean: %.3f var: %.3f count: %g\n", scaler.Mean.At(0, 0), scaler.Var.At(0, 0), scaler.NSamplesSeenByFeature)
+	Xrepeated := mat.NewDense(5, 1, []float64{1, 1, 1, 2, 6})
+	// the weights are not copied by TransformerClone, so the clone can be fitted on other samples
+	scaler = scaler.TransformerClone().(*StandardScaler)
+	scaler.Fit(Xrepeated, nil)
+	fmt.Printf("mean: %.3f var: %.3f count: %g\n", scaler.Mean.At(0, 0), scaler.Var.At(0, 0), scaler.NSamplesSeenByFeature)
+	// Output:
+	// mean: 2.200 var: 3.760 count: [5]
+	// mean: 2.200 var: 3.760 count: [5]
+}
+
 func TestPolynomialFeatures(t *testing.T) {
 	nSamples, nFeatures := 1, 3
 	X := mat.NewDense(nSamples, nFeatures, []float{1, 2, 3})
@@ -283,6 +386,37 @@ func TestTransformer(t *testing.T) {
 	f(NewShuffler())
 }
 
+func TestFeatureNamesOuter(t *testing.T) {
+	for _, transformer := range []Transformer{
+		NewMinMaxScaler([]float64{0, 1}), NewStandardScaler(), NewDefaultRobustScaler(), NewPolynomialFeatures(2),
+		NewShuffler(), NewBinarizer(), NewMaxAbsScaler(), NewNormalizer(), NewKernelCenterer(),
+		NewQuantileTransformer(10, "uniform", nil), NewPowerTransformer(), NewPCA(), NewIncrementalPCA(),
+		NewFunctionTransformer(nil, nil), NewKBinsDiscretizer(2), NewImputer(), NewMissingIndicator(),
+		NewOneHotEncoder(), NewOrdinalEncoder(), NewTargetEncoder(), NewSplineTransformer(),
+	} {
+		if _, ok := transformer.(base.FeatureNamesOuter); !ok {
+			t.Errorf("%T has no GetFeatureNamesOut", transformer)
+		}
+	}
+}
+
+func ExamplePolynomialFeatures_GetFeatureNamesOut() {
+	X := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	poly := NewPolynomialFeatures(2)
+	poly.Fit(X, nil)
+	fmt.Println(poly.GetFeatureNamesOut(nil))
+	poly.InteractionOnly = true
+	poly.Fit(X, nil)
+	fmt.Println(poly.GetFeatureNamesOut([]string{"a", "b"}))
+	pca := NewPCA()
+	pca.Fit(X, nil)
+	fmt.Println(pca.GetFeatureNamesOut(nil))
+	// Output:
+	// [1 x0 x1 x0^2 x0 x1 x1^2]
+	// [1 a b a b]
+	// [pca0 pca1]
+}
+
 func ExampleMaxAbsScaler() {
 	mas := NewMaxAbsScaler()
 	X0 := mat.NewDense(2, 3, []float64{1, 2, 0, 3, -4, 0})
@@ -3,26 +3,34 @@ package preprocessing
 import (
 	"fmt"
 	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
 
 	"github.com/RobinRCM/sklearn/base"
-	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
-	"gonum.org/v1/gonum/stat"
 )
 
 // KBinsDiscretizer structure
-// Encode = "onehot-dense","ordinal"
+// Encode = "onehot-dense","onehot","ordinal". "onehot" Transform returns the dense version of TransformSparse
 // Strategy = "quantile","uniform","kmeans"
+// NBinsByFeature, if not nil, overrides NBins for each feature. bins narrower than 1e-8 are removed for "quantile" and
+// "kmeans" strategies, so NBinsFitted may be lower than the requested number of bins.
+// if Subsample>0, at most Subsample rows, drawn using RandomState, are used to fit
 type KBinsDiscretizer struct {
-	NBins    int
-	Encode   string
-	Strategy string
-	BinEdges [][]float64
+	NBins          int
+	NBinsByFeature []int
+	Encode         string
+	Strategy       string
+	Subsample      int
+	RandomState    base.Source
+	BinEdges       [][]float64
+	NBinsFitted    []int
 }
 
 // NewKBinsDiscretizer returns a discretizer with Encode="onehot-dense" ans strategy="quantile"
 func NewKBinsDiscretizer(NBins int) *KBinsDiscretizer {
-	return &KBinsDiscretizer{NBins: NBins, Encode: "onehot-dense", Strategy: "quantile"}
+	return &KBinsDiscretizer{NBins: NBins, Encode: "onehot-dense", Strategy: "quantile", Subsample: 200000}
 }
 
 // TransformerClone ...
@@ -34,63 +42,187 @@ func (m *KBinsDiscretizer) TransformerClone() Transformer {
 // Fit fits the transformer
 func (m *KBinsDiscretizer) Fit(X, Y mat.Matrix) base.Fiter {
 	NSamples, NFeatures := X.Dims()
+	switch m.Strategy {
+	case "quantile", "uniform", "kmeans":
+	default:
+		panic(fmt.Errorf("not implemented strategy %s", m.Strategy))
+	}
+	if m.NBinsByFeature != nil && len(m.NBinsByFeature) != NFeatures {
+		panic(fmt.Errorf("NBinsByFeature has %d elements, expected %d", len(m.NBinsByFeature), NFeatures))
+	}
+	rows := make([]int, NSamples)
+	for i := range rows {
+		rows[i] = i
+	}
+	if m.Subsample > 0 && NSamples > m.Subsample {
+		Perm := rand.Perm
+		if m.RandomState != base.Source(nil) {
+			Perm = rand.New(m.RandomState).Perm
+		}
+		rows = Perm(NSamples)[:m.Subsample]
+	}
 	m.BinEdges = make([][]float64, NFeatures)
+	m.NBinsFitted = make([]int, NFeatures)
 	base.Parallelize(-1, NFeatures, func(th, start, end int) {
-		tmp := make([]float64, NSamples)
+		tmp := make([]float64, len(rows))
 		for f := start; f < end; f++ {
-			mat.Col(tmp, f, X)
-			min, max := floats.Min(tmp), floats.Max(tmp)
-			m.BinEdges[f] = make([]float64, m.NBins+1)
-			for b := 0; b <= m.NBins; b++ {
-				switch m.Strategy {
-				case "quantile":
-					m.BinEdges[f][b] = stat.Quantile(float64(b)/float64(m.NBins), stat.Empirical, tmp, nil)
-				case "uniform":
-					m.BinEdges[f][b] = min + float64(b)/float64(m.NBins)*(max-min)
-				default:
-					panic(fmt.Errorf("not implemented strategy %s", m.Strategy))
+			nBins := m.NBins
+			if m.NBinsByFeature != nil {
+				nBins = m.NBinsByFeature[f]
+			}
+			if nBins < 2 {
+				panic(fmt.Errorf("KBinsDiscretizer needs at least 2 bins, got %d for feature %d", nBins, f))
+			}
+			for i, row := range rows {
+				tmp[i] = X.At(row, f)
+			}
+			sort.Float64s(tmp)
+			min, max := tmp[0], tmp[len(tmp)-1]
+			if min == max {
+				m.BinEdges[f] = []float64{min, max}
+				m.NBinsFitted[f] = 1
+				continue
+			}
+			edges := make([]float64, nBins+1)
+			switch m.Strategy {
+			case "quantile":
+				for b := range edges {
+					edges[b] = percentile(tmp, float64(b)/float64(nBins))
+				}
+			case "uniform":
+				for b := range edges {
+					edges[b] = min + float64(b)/float64(nBins)*(max-min)
 				}
+			case "kmeans":
+				edges = kmeans1DEdges(tmp, nBins)
 			}
+			if m.Strategy != "uniform" {
+				// remove bins whose width is too small
+				kept := edges[:1]
+				for b := 1; b < len(edges); b++ {
+					if edges[b]-edges[b-1] > 1e-8 {
+						kept = append(kept, edges[b])
+					}
+				}
+				edges = kept
+			}
+			m.BinEdges[f] = edges
+			m.NBinsFitted[f] = len(edges) - 1
 		}
 	})
 
 	return m
 }
 
+// kmeans1DEdges returns the bin edges of sorted values clustered by a 1-D k-means whose centers are initialized at the
+// centers of uniform bins. edges are the midpoints between consecutive sorted centers, and the min and max values
+func kmeans1DEdges(sorted []float64, nBins int) []float64 {
+	min, max := sorted[0], sorted[len(sorted)-1]
+	centers := make([]float64, nBins)
+	for k := range centers {
+		centers[k] = min + (float64(k)+.5)/float64(nBins)*(max-min)
+	}
+	sums, counts := make([]float64, nBins), make([]int, nBins)
+	for iter := 0; iter < 300; iter++ {
+		for k := range sums {
+			sums[k], counts[k] = 0, 0
+		}
+		// centers stay sorted, so values of cluster k lie between midpoints k-1 and k
+		k := 0
+		for _, x := range sorted {
+			for k < nBins-1 && x > .5*(centers[k]+centers[k+1]) {
+				k++
+			}
+			sums[k] += x
+			counts[k]++
+		}
+		changed := false
+		for k := range centers {
+			if counts[k] > 0 {
+				if c := sums[k] / float64(counts[k]); c != centers[k] {
+					centers[k] = c
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+		sort.Float64s(centers)
+	}
+	edges := make([]float64, nBins+1)
+	edges[0], edges[nBins] = min, max
+	for k := 1; k < nBins; k++ {
+		edges[k] = .5 * (centers[k-1] + centers[k])
+	}
+	return edges
+}
+
+// binIndex returns the bin of x for feature f
+func (m *KBinsDiscretizer) binIndex(f int, x float64) int {
+	ith := 0
+	for ith < m.NBinsFitted[f]-1 && m.BinEdges[f][ith+1] <= x {
+		ith++
+	}
+	return ith
+}
+
+// onehotOffsets returns the index of the first one hot column of each feature
+func (m *KBinsDiscretizer) onehotOffsets() []int {
+	offsets := make([]int, len(m.NBinsFitted)+1)
+	for f, nBins := range m.NBinsFitted {
+		offsets[f+1] = offsets[f] + nBins
+	}
+	return offsets
+}
+
 // Transform discretizes the Data
 func (m *KBinsDiscretizer) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	NSamples, NFeatures := X.Dims()
+	offsets := m.onehotOffsets()
 	switch m.Encode {
 	case "ordinal":
 		Xout = mat.NewDense(NSamples, NFeatures, nil)
 	case "onehot":
+		return m.TransformSparse(X).ToDense(), base.ToDense(Y)
 	case "onehot-dense":
-		Xout = mat.NewDense(NSamples, NFeatures*m.NBins, nil)
+		Xout = mat.NewDense(NSamples, offsets[NFeatures], nil)
+	default:
+		panic(fmt.Errorf("unknown Encode %s", m.Encode))
 	}
 	base.Parallelize(-1, NFeatures, func(th, start, end int) {
 		for f := start; f < end; f++ {
 			for i := 0; i < NSamples; i++ {
-				x := X.At(i, f)
-				ith := 0
-				for ith < m.NBins-1 {
-					if m.BinEdges[f][ith+1] <= x {
-						ith++
-					} else {
-						break
-					}
-				}
+				ith := m.binIndex(f, X.At(i, f))
 				switch m.Encode {
 				case "ordinal":
 					Xout.Set(i, f, float64(ith))
-				case "onehot":
 				case "onehot-dense":
-					Xout.Set(i, f*m.NBins+ith, 1)
+					Xout.Set(i, offsets[f]+ith, 1)
 				}
 			}
 		}
 	})
 
-	return
+	return Xout, base.ToDense(Y)
+}
+
+// TransformSparse returns the sparse one hot encoding of the bins of X, whatever Encode is
+func (m *KBinsDiscretizer) TransformSparse(X mat.Matrix) *base.CSRMatrix {
+	NSamples, NFeatures := X.Dims()
+	offsets := m.onehotOffsets()
+	Xout := base.NewCSRMatrix(NSamples, offsets[NFeatures])
+	indices, ones := make([]int, NFeatures), make([]float64, NFeatures)
+	for f := range ones {
+		ones[f] = 1
+	}
+	for i := 0; i < NSamples; i++ {
+		for f := range indices {
+			indices[f] = offsets[f] + m.binIndex(f, X.At(i, f))
+		}
+		Xout.AppendRow(indices, ones)
+	}
+	return Xout
 }
 
 // FitTransform fitts the data then transforms it
@@ -99,14 +231,32 @@ func (m *KBinsDiscretizer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense)
 	return m.Transform(X, Y)
 }
 
-// InverseTransform transforms discretized data back to original feature space.
+// GetFeatureNamesOut returns inputNames (default x0, x1...) for ordinal encoding, else the input names followed by an
+// underscore and the bin index
+func (m *KBinsDiscretizer) GetFeatureNamesOut(inputNames []string) []string {
+	inputNames = base.FeatureNamesIn(inputNames, len(m.BinEdges))
+	if m.Encode == "ordinal" {
+		return inputNames
+	}
+	names := make([]string, 0, m.onehotOffsets()[len(inputNames)])
+	for f, name := range inputNames {
+		for b := 0; b < m.NBinsFitted[f]; b++ {
+			names = append(names, fmt.Sprintf("%s_%d", name, b))
+		}
+	}
+	return names
+}
+
+// InverseTransform transforms discretized data back to original feature space: the center of the bins
 func (m *KBinsDiscretizer) InverseTransform(X mat.Matrix, Y mat.Mutable) (Xout, Yout *mat.Dense) {
 	NSamples, _ := X.Dims()
 	NFeatures := len(m.BinEdges)
+	offsets := m.onehotOffsets()
 	Xout = mat.NewDense(NSamples, NFeatures, nil)
 	base.Parallelize(-1, NFeatures, func(th, start, end int) {
 		tmp := make([]float64, NSamples)
 		for f := start; f < end; f++ {
+			nBins := m.NBinsFitted[f]
 			for i := 0; i < NSamples; i++ {
 				var ith int
 				switch m.Encode {
@@ -114,16 +264,16 @@ func (m *KBinsDiscretizer) InverseTransform(X mat.Matrix, Y mat.Mutable) (Xout,
 					ith = int(math.Floor(X.At(i, f)))
 				case "onehot", "onehot-dense":
 					ith = 0
-					for c := f*m.NBins + 1; c < (f+1)*m.NBins; c++ {
-						if X.At(i, c) > X.At(i, f*m.NBins+ith) {
-							ith = c - f*m.NBins
+					for b := 1; b < nBins; b++ {
+						if X.At(i, offsets[f]+b) > X.At(i, offsets[f]+ith) {
+							ith = b
 						}
 					}
 				}
 				if ith < 0 {
 					ith = 0
-				} else if ith > m.NBins-1 {
-					ith = m.NBins - 1
+				} else if ith > nBins-1 {
+					ith = nBins - 1
 				}
 				tmp[i] = .5 * (m.BinEdges[f][ith] + m.BinEdges[f][ith+1])
 			}
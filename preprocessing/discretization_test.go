@@ -36,3 +36,50 @@ func ExampleKBinsDiscretizer() {
 	// ⎣ 0.5   3.5  -1.5   1.5⎦
 
 }
+
+func ExampleKBinsDiscretizer_kmeans() {
+	// two groups of values and an outlier
+	X := mat.NewDense(9, 1, []float64{0, 0.5, 1, 1.5, 9, 9.5, 10, 10.5, 30})
+	for _, strategy := range []string{"uniform", "quantile", "kmeans"} {
+		est := NewKBinsDiscretizer(3)
+		est.Encode = "ordinal"
+		est.Strategy = strategy
+		Xt, _ := est.FitTransform(X, nil)
+		fmt.Printf("%-8s edges: %.4g bins: %g\n", strategy, est.BinEdges[0], Xt.RawMatrix().Data)
+	}
+	// Output:
+	// uniform  edges: [0 10 20 30] bins: [0 0 0 0 0 0 1 1 2]
+	// quantile edges: [0 1.333 9.667 30] bins: [0 0 0 1 1 1 2 2 2]
+	// kmeans   edges: [0 5.25 19.88 30] bins: [0 0 0 0 1 1 1 1 2]
+}
+
+func ExampleKBinsDiscretizer_TransformSparse() {
+	X := mat.NewDense(5, 2, []float64{
+		1, 0,
+		2, 0,
+		3, 0,
+		4, 1,
+		5, 1,
+	})
+	est := NewKBinsDiscretizer(3)
+	est.Encode = "onehot"
+	// 2 bins are requested for the second feature, but the quantile edges of its repeated values collapse into 1
+	est.NBinsByFeature = []int{4, 2}
+	Xt := est.Fit(X, nil).(*KBinsDiscretizer).TransformSparse(X)
+	fmt.Println(est.NBinsFitted, est.GetFeatureNamesOut(nil))
+	fmt.Printf("%g\n", mat.Formatted(Xt))
+	Xinv, _ := est.InverseTransform(Xt, nil)
+	fmt.Printf("%g\n", mat.Formatted(Xinv))
+	// Output:
+	// [4 1] [x0_0 x0_1 x0_2 x0_3 x1_0]
+	// ⎡1  0  0  0  1⎤
+	// ⎢0  1  0  0  1⎥
+	// ⎢0  0  1  0  1⎥
+	// ⎢0  0  0  1  1⎥
+	// ⎣0  0  0  1  1⎦
+	// ⎡1.5  0.5⎤
+	// ⎢2.5  0.5⎥
+	// ⎢3.5  0.5⎥
+	// ⎢4.5  0.5⎥
+	// ⎣4.5  0.5⎦
+}
@@ -0,0 +1,562 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// OneHotEncoder encodes categorical features as a one-hot numeric array.
+// Categories are the expected categories of each feature. if nil, they are the sorted unique values of each feature
+// in training data.
+// HandleUnknown is "error" (default), "ignore" (unknown values are encoded as all zeros) or "infrequent_if_exist"
+// alias "infrequent" (unknown values are encoded as the infrequent category of the feature if any, else as all zeros).
+// Drop is "" (keep all categories), "first" or "if_binary" (drop the first category of features with 2 output columns).
+// categories seen less than MinFrequency times (less than MinFrequency*nSamples if MinFrequency<1) and the least
+// frequent categories exceeding MaxCategories output columns per feature are grouped in a single infrequent column,
+// the last one of the feature.
+// after Fit, Values are the categories of each feature, NValues the number of output columns of each feature and
+// FeatureIndices the offsets of each feature in the output. DropIdx is the index of the dropped category of each
+// feature, or -1
+type OneHotEncoder struct {
+	Categories    [][]float64
+	HandleUnknown string
+	Drop          string
+	MinFrequency  float64
+	MaxCategories int
+
+	NValues, FeatureIndices []int
+	Values                  [][]float64
+	InfrequentCategories    [][]float64
+	DropIdx                 []int
+	// outputColumn[f][c] is the output column of category c of feature f relative to FeatureIndices[f], -1 if dropped
+	outputColumn [][]int
+	// infrequentColumn[f] is the output column of infrequent categories of feature f, -1 if none or dropped
+	infrequentColumn []int
+	categoryIndex    []categoryIndex
+	tableEncoding
+}
+
+// NewOneHotEncoder creates a *OneHotEncoder
+func NewOneHotEncoder() *OneHotEncoder {
+	return &OneHotEncoder{HandleUnknown: "error"}
+}
+
+// TransformerClone ...
+func (m *OneHotEncoder) TransformerClone() base.Transformer {
+	var clone = *m
+	return &clone
+}
+
+// Fit determines the categories of each feature and the output columns
+func (m *OneHotEncoder) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := base.ToDense(m.fitTable(Xmatrix))
+	nSamples, nFeatures := X.Dims()
+	if m.Categories != nil && len(m.Categories) != nFeatures {
+		panic(fmt.Errorf("OneHotEncoder: Categories has %d features, X has %d", len(m.Categories), nFeatures))
+	}
+	switch m.HandleUnknown {
+	case "", "error", "ignore", "infrequent_if_exist", "infrequent":
+	default:
+		panic(fmt.Errorf("OneHotEncoder: unknown HandleUnknown %s", m.HandleUnknown))
+	}
+	m.Values, m.InfrequentCategories = make([][]float64, nFeatures), make([][]float64, nFeatures)
+	m.NValues, m.FeatureIndices, m.DropIdx = make([]int, nFeatures), make([]int, nFeatures+1), make([]int, nFeatures)
+	m.outputColumn, m.infrequentColumn = make([][]int, nFeatures), make([]int, nFeatures)
+	m.categoryIndex = make([]categoryIndex, nFeatures)
+	col := make([]float64, nSamples)
+	for feature := 0; feature < nFeatures; feature++ {
+		mat.Col(col, feature, X)
+		if m.Categories == nil {
+			m.Values[feature] = uniqueSorted(col)
+		} else {
+			m.Values[feature] = append([]float64(nil), m.Categories[feature]...)
+		}
+		index := newCategoryIndex(m.Values[feature])
+		m.categoryIndex[feature] = index
+		counts := make([]int, len(m.Values[feature]))
+		for _, v := range col {
+			c := index.get(v)
+			if c < 0 {
+				if m.HandleUnknown == "" || m.HandleUnknown == "error" {
+					panic(fmt.Errorf("OneHotEncoder: found unknown category %g in column %d during fit", v, feature))
+				}
+				continue
+			}
+			counts[c]++
+		}
+		infrequent := infrequentMask(counts, nSamples, m.MinFrequency, m.MaxCategories)
+		m.setOutputColumns(feature, infrequent)
+		m.FeatureIndices[feature+1] = m.FeatureIndices[feature] + m.NValues[feature]
+	}
+	return m
+}
+
+// setOutputColumns assigns output columns to the categories of feature, infrequent ones sharing the last column,
+// then drops the first category column if required
+func (m *OneHotEncoder) setOutputColumns(feature int, infrequent []bool) {
+	values := m.Values[feature]
+	columns := make([]int, len(values))
+	nColumns := 0
+	m.InfrequentCategories[feature] = nil
+	for c, v := range values {
+		if infrequent[c] {
+			m.InfrequentCategories[feature] = append(m.InfrequentCategories[feature], v)
+			continue
+		}
+		columns[c] = nColumns
+		nColumns++
+	}
+	m.infrequentColumn[feature] = -1
+	if len(m.InfrequentCategories[feature]) > 0 {
+		m.infrequentColumn[feature] = nColumns
+		for c := range values {
+			if infrequent[c] {
+				columns[c] = nColumns
+			}
+		}
+		nColumns++
+	}
+	m.DropIdx[feature] = -1
+	switch m.Drop {
+	case "":
+	case "first":
+		m.DropIdx[feature] = 0
+	case "if_binary":
+		if nColumns == 2 {
+			m.DropIdx[feature] = 0
+		}
+	default:
+		panic(fmt.Errorf("OneHotEncoder: unknown Drop %s", m.Drop))
+	}
+	if m.DropIdx[feature] >= 0 && len(values) > 0 {
+		dropped := columns[m.DropIdx[feature]]
+		for c, column := range columns {
+			if column == dropped {
+				columns[c] = -1
+			} else if column > dropped {
+				columns[c]--
+			}
+		}
+		if m.infrequentColumn[feature] == dropped {
+			m.infrequentColumn[feature] = -1
+		} else if m.infrequentColumn[feature] > dropped {
+			m.infrequentColumn[feature]--
+		}
+		nColumns--
+	}
+	m.outputColumn[feature] = columns
+	m.NValues[feature] = nColumns
+}
+
+// encode calls set(sample, column) for each active output column
+func (m *OneHotEncoder) encode(X mat.Matrix, set func(sample, column int)) {
+	X = m.transformTable(X)
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != len(m.Values) {
+		panic(fmt.Errorf("OneHotEncoder: X has %d features, expected %d", nFeatures, len(m.Values)))
+	}
+	for sample := 0; sample < nSamples; sample++ {
+		for feature := 0; feature < nFeatures; feature++ {
+			v := X.At(sample, feature)
+			column := -1
+			if c := m.categoryIndex[feature].get(v); c >= 0 {
+				column = m.outputColumn[feature][c]
+			} else {
+				switch m.HandleUnknown {
+				case "ignore":
+				case "infrequent_if_exist", "infrequent":
+					column = m.infrequentColumn[feature]
+				default:
+					panic(fmt.Errorf("OneHotEncoder: found unknown category %g in column %d during transform", v, feature))
+				}
+			}
+			if column >= 0 {
+				set(sample, m.FeatureIndices[feature]+column)
+			}
+		}
+	}
+}
+
+// Transform transform X to one hot encoded format
+func (m *OneHotEncoder) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	nSamples, _ := X.Dims()
+	Xout = mat.NewDense(nSamples, m.FeatureIndices[len(m.FeatureIndices)-1], nil)
+	m.encode(X, func(sample, column int) { Xout.Set(sample, column, 1) })
+	return Xout, base.ToDense(Y)
+}
+
+// TransformSparse transforms X to a sparse one hot encoded matrix
+func (m *OneHotEncoder) TransformSparse(X mat.Matrix) *base.CSRMatrix {
+	nSamples, _ := X.Dims()
+	Xout := base.NewCSRMatrix(nSamples, m.FeatureIndices[len(m.FeatureIndices)-1])
+	var indices []int
+	var values []float64
+	current := 0
+	m.encode(X, func(sample, column int) {
+		for ; current < sample; current++ {
+			Xout.AppendRow(indices, values)
+			indices, values = indices[:0], values[:0]
+		}
+		indices, values = append(indices, column), append(values, 1)
+	})
+	for ; current < nSamples; current++ {
+		Xout.AppendRow(indices, values)
+		indices, values = indices[:0], values[:0]
+	}
+	return Xout
+}
+
+// FitTransform fit to dat, then transform it
+func (m *OneHotEncoder) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// InverseTransform converts one hot encoded X back to categories. all-zero features are decoded as the dropped
+// category if any. unknown and infrequent categories are decoded as NaN
+func (m *OneHotEncoder) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	nSamples, _ := X.Dims()
+	nFeatures := len(m.NValues)
+	Yout = Y
+	Xout = mat.NewDense(nSamples, nFeatures, nil)
+	for feature := 0; feature < nFeatures; feature++ {
+		// categoryOfColumn is the category index of each output column, -1 for infrequent column
+		categoryOfColumn := make([]int, m.NValues[feature])
+		for c, column := range m.outputColumn[feature] {
+			if column >= 0 {
+				categoryOfColumn[column] = c
+			}
+		}
+		if m.infrequentColumn[feature] >= 0 {
+			categoryOfColumn[m.infrequentColumn[feature]] = -1
+		}
+		dropped := math.NaN()
+		if dropIdx := m.DropIdx[feature]; dropIdx >= 0 && !m.isInfrequent(feature, m.Values[feature][dropIdx]) {
+			dropped = m.Values[feature][dropIdx]
+		}
+		cstart, cend := m.FeatureIndices[feature], m.FeatureIndices[feature+1]
+		for sample := 0; sample < nSamples; sample++ {
+			v := dropped
+			if cend > cstart {
+				row := X.RawRowView(sample)[cstart:cend]
+				if column := floats.MaxIdx(row); row[column] > 0 {
+					v = math.NaN()
+					if c := categoryOfColumn[column]; c >= 0 {
+						v = m.Values[feature][c]
+					}
+				}
+			}
+			Xout.Set(sample, feature, v)
+		}
+	}
+	return
+}
+
+// isInfrequent returns true if v is an infrequent category of feature
+func (m *OneHotEncoder) isInfrequent(feature int, v float64) bool {
+	return containsCategory(m.InfrequentCategories[feature], v)
+}
+
+// containsCategory returns true if v is in categories. NaN is a regular category
+func containsCategory(categories []float64, v float64) bool {
+	for _, category := range categories {
+		if category == v || (math.IsNaN(category) && math.IsNaN(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFeatureNamesOut returns the names of output columns: input feature name, underscore and category.
+// inputNames defaults to x0, x1...
+func (m *OneHotEncoder) GetFeatureNamesOut(inputNames []string) []string {
+	inputNames = base.FeatureNamesIn(m.inputNames(inputNames), len(m.Values))
+	names := make([]string, m.FeatureIndices[len(m.FeatureIndices)-1])
+	for feature, values := range m.Values {
+		prefix := inputNames[feature]
+		for c, column := range m.outputColumn[feature] {
+			if column >= 0 && column != m.infrequentColumn[feature] {
+				names[m.FeatureIndices[feature]+column] = prefix + "_" + m.categoryName(feature, values[c])
+			}
+		}
+		if column := m.infrequentColumn[feature]; column >= 0 {
+			names[m.FeatureIndices[feature]+column] = prefix + "_infrequent_sklearn"
+		}
+	}
+	return names
+}
+
+// OrdinalEncoder encodes categorical features as integer codes, the index of the category in the sorted categories
+// of the feature.
+// Categories are the expected categories of each feature. if nil, they are the sorted unique values of each feature
+// in training data.
+// HandleUnknown is "error" (default) or "use_encoded_value" (unknown values are encoded as UnknownValue).
+// missing values (NaN) seen during fit are encoded as EncodedMissingValue (default NaN).
+// categories seen less than MinFrequency times (less than MinFrequency*nSamples if MinFrequency<1) and the least
+// frequent categories exceeding MaxCategories codes per feature share the last code of the feature.
+// after Fit, Values are the categories of each feature
+type OrdinalEncoder struct {
+	Categories          [][]float64
+	HandleUnknown       string
+	UnknownValue        float64
+	EncodedMissingValue float64
+	MinFrequency        float64
+	MaxCategories       int
+
+	Values               [][]float64
+	InfrequentCategories [][]float64
+	// codes[f][c] is the code of category c of feature f
+	codes         [][]float64
+	categoryIndex []categoryIndex
+	tableEncoding
+}
+
+// NewOrdinalEncoder returns an *OrdinalEncoder encoding missing and unknown values as NaN
+func NewOrdinalEncoder() *OrdinalEncoder {
+	return &OrdinalEncoder{HandleUnknown: "error", UnknownValue: math.NaN(), EncodedMissingValue: math.NaN()}
+}
+
+// TransformerClone ...
+func (m *OrdinalEncoder) TransformerClone() base.Transformer {
+	var clone = *m
+	return &clone
+}
+
+// Fit determines the categories and codes of each feature
+func (m *OrdinalEncoder) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := base.ToDense(m.fitTable(Xmatrix))
+	nSamples, nFeatures := X.Dims()
+	if m.Categories != nil && len(m.Categories) != nFeatures {
+		panic(fmt.Errorf("OrdinalEncoder: Categories has %d features, X has %d", len(m.Categories), nFeatures))
+	}
+	switch m.HandleUnknown {
+	case "", "error":
+	case "use_encoded_value":
+		if !math.IsNaN(m.UnknownValue) && m.UnknownValue != math.Trunc(m.UnknownValue) {
+			panic(fmt.Errorf("OrdinalEncoder: UnknownValue must be an integer or NaN, got %g", m.UnknownValue))
+		}
+	default:
+		panic(fmt.Errorf("OrdinalEncoder: unknown HandleUnknown %s", m.HandleUnknown))
+	}
+	m.Values, m.InfrequentCategories = make([][]float64, nFeatures), make([][]float64, nFeatures)
+	m.codes, m.categoryIndex = make([][]float64, nFeatures), make([]categoryIndex, nFeatures)
+	col := make([]float64, nSamples)
+	for feature := 0; feature < nFeatures; feature++ {
+		mat.Col(col, feature, X)
+		if m.Categories == nil {
+			m.Values[feature] = uniqueSorted(col)
+		} else {
+			m.Values[feature] = append([]float64(nil), m.Categories[feature]...)
+		}
+		values := m.Values[feature]
+		index := newCategoryIndex(values)
+		m.categoryIndex[feature] = index
+		counts := make([]int, len(values))
+		for _, v := range col {
+			c := index.get(v)
+			if c < 0 {
+				panic(fmt.Errorf("OrdinalEncoder: found unknown category %g in column %d during fit", v, feature))
+			}
+			counts[c]++
+		}
+		// missing values have their own code and do not take part in frequency grouping
+		if index.nanIndex >= 0 {
+			counts = append(counts[:index.nanIndex:index.nanIndex], counts[index.nanIndex+1:]...)
+		}
+		infrequent := infrequentMask(counts, nSamples, m.MinFrequency, m.MaxCategories)
+		if index.nanIndex >= 0 {
+			infrequent = append(infrequent[:index.nanIndex:index.nanIndex], append([]bool{false}, infrequent[index.nanIndex:]...)...)
+		}
+		codes := make([]float64, len(values))
+		nCodes := 0
+		m.InfrequentCategories[feature] = nil
+		for c, v := range values {
+			switch {
+			case c == index.nanIndex:
+				codes[c] = m.EncodedMissingValue
+			case infrequent[c]:
+				m.InfrequentCategories[feature] = append(m.InfrequentCategories[feature], v)
+			default:
+				codes[c] = float64(nCodes)
+				nCodes++
+			}
+		}
+		for c := range values {
+			if infrequent[c] {
+				codes[c] = float64(nCodes)
+			}
+		}
+		if len(m.InfrequentCategories[feature]) > 0 {
+			nCodes++
+		}
+		if m.HandleUnknown == "use_encoded_value" && !math.IsNaN(m.UnknownValue) && m.UnknownValue >= 0 && m.UnknownValue < float64(nCodes) {
+			panic(fmt.Errorf("OrdinalEncoder: UnknownValue %g is already used to encode a category of feature %d", m.UnknownValue, feature))
+		}
+		if index.nanIndex >= 0 && !math.IsNaN(m.EncodedMissingValue) && m.EncodedMissingValue >= 0 && m.EncodedMissingValue < float64(nCodes) {
+			panic(fmt.Errorf("OrdinalEncoder: EncodedMissingValue %g is already used to encode a category of feature %d", m.EncodedMissingValue, feature))
+		}
+		m.codes[feature] = codes
+	}
+	return m
+}
+
+// Transform encodes X as codes
+func (m *OrdinalEncoder) Transform(Xmatrix, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := m.transformTable(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != len(m.Values) {
+		panic(fmt.Errorf("OrdinalEncoder: X has %d features, expected %d", nFeatures, len(m.Values)))
+	}
+	Xout = mat.NewDense(nSamples, nFeatures, nil)
+	for sample := 0; sample < nSamples; sample++ {
+		row := Xout.RawRowView(sample)
+		for feature := range row {
+			v := X.At(sample, feature)
+			c := m.categoryIndex[feature].get(v)
+			switch {
+			case c >= 0:
+				row[feature] = m.codes[feature][c]
+			case math.IsNaN(v) && m.HandleUnknown != "use_encoded_value":
+				panic(fmt.Errorf("OrdinalEncoder: found missing value in column %d during transform, unseen during fit", feature))
+			case m.HandleUnknown == "use_encoded_value":
+				row[feature] = m.UnknownValue
+			default:
+				panic(fmt.Errorf("OrdinalEncoder: found unknown category %g in column %d during transform", v, feature))
+			}
+		}
+	}
+	return Xout, base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *OrdinalEncoder) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// InverseTransform converts codes back to categories. unknown, missing and infrequent codes are decoded as NaN
+func (m *OrdinalEncoder) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
+	if X == nil {
+		return X, Y
+	}
+	nSamples, nFeatures := X.Dims()
+	Xout, Yout = mat.NewDense(nSamples, nFeatures, nil), Y
+	for feature, codes := range m.codes {
+		// category of each code, NaN for the infrequent code
+		categories := make(map[float64]float64)
+		for c, code := range codes {
+			v := m.Values[feature][c]
+			switch {
+			case c == m.categoryIndex[feature].nanIndex:
+			case containsCategory(m.InfrequentCategories[feature], v):
+				categories[code] = math.NaN()
+			default:
+				categories[code] = v
+			}
+		}
+		for sample := 0; sample < nSamples; sample++ {
+			v, ok := categories[X.At(sample, feature)]
+			if !ok {
+				v = math.NaN()
+			}
+			Xout.Set(sample, feature, v)
+		}
+	}
+	return
+}
+
+// GetFeatureNamesOut returns inputNames, which defaults to x0, x1...
+func (m *OrdinalEncoder) GetFeatureNamesOut(inputNames []string) []string {
+	return base.FeatureNamesIn(m.inputNames(inputNames), len(m.Values))
+}
+
+// infrequentMask returns which categories are infrequent given their counts: categories seen less than minFrequency
+// times (less than minFrequency*nSamples if minFrequency<1) and the least frequent ones exceeding maxCategories-1
+func infrequentMask(counts []int, nSamples int, minFrequency float64, maxCategories int) []bool {
+	mask := make([]bool, len(counts))
+	if minFrequency > 0 {
+		minCount := minFrequency
+		if minCount < 1 {
+			minCount *= float64(nSamples)
+		}
+		for c, count := range counts {
+			mask[c] = float64(count) < minCount
+		}
+	}
+	nFrequent := 0
+	for _, infrequent := range mask {
+		if !infrequent {
+			nFrequent++
+		}
+	}
+	if maxCategories > 0 && maxCategories < nFrequent+1 {
+		// keep maxCategories-1 most frequent categories, the last one is for infrequent ones
+		order := make([]int, len(counts))
+		for c := range order {
+			order[c] = c
+		}
+		sort.SliceStable(order, func(a, b int) bool { return counts[order[a]] > counts[order[b]] })
+		for _, c := range order[maxCategories-1:] {
+			mask[c] = true
+		}
+	}
+	return mask
+}
+
+// categoryIndex maps category values to their index. NaN is a regular category
+type categoryIndex struct {
+	index    map[float64]int
+	nanIndex int
+}
+
+func newCategoryIndex(categories []float64) categoryIndex {
+	ci := categoryIndex{index: make(map[float64]int), nanIndex: -1}
+	for c, v := range categories {
+		if math.IsNaN(v) {
+			ci.nanIndex = c
+		} else {
+			ci.index[v] = c
+		}
+	}
+	return ci
+}
+
+// get returns the index of category v or -1
+func (ci categoryIndex) get(v float64) int {
+	if math.IsNaN(v) {
+		return ci.nanIndex
+	}
+	if c, ok := ci.index[v]; ok {
+		return c
+	}
+	return -1
+}
+
+// uniqueSorted returns the sorted unique values of a, NaN being last
+func uniqueSorted(a []float64) []float64 {
+	seen := make(map[float64]bool)
+	hasNaN := false
+	var values []float64
+	for _, v := range a {
+		if math.IsNaN(v) {
+			hasNaN = true
+		} else if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Float64s(values)
+	if hasNaN {
+		values = append(values, math.NaN())
+	}
+	return values
+}
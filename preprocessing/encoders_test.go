@@ -0,0 +1,173 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/table"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleOneHotEncoder_handleUnknown() {
+	X := mat.NewDense(4, 2, []float64{
+		1, 10,
+		2, 20,
+		1, 30,
+		3, 10,
+	})
+	enc := NewOneHotEncoder()
+	enc.Categories = [][]float64{{1, 2, 3, 4}, {10, 20, 30}}
+	enc.HandleUnknown = "ignore"
+	enc.Fit(X, nil)
+	fmt.Println(enc.GetFeatureNamesOut([]string{"color", "size"}))
+	Xnew := mat.NewDense(2, 2, []float64{4, 20, 5, 30})
+	Xt, _ := enc.Transform(Xnew, nil)
+	fmt.Println(mat.Formatted(Xt))
+	Xinv, _ := enc.InverseTransform(Xt, nil)
+	fmt.Println(mat.Formatted(Xinv))
+	// Output:
+	// [color_1 color_2 color_3 color_4 size_10 size_20 size_30]
+	// ⎡0  0  0  1  0  1  0⎤
+	// ⎣0  0  0  0  0  0  1⎦
+	// ⎡  4   20⎤
+	// ⎣NaN   30⎦
+}
+
+func ExampleOneHotEncoder_drop() {
+	X := mat.NewDense(4, 2, []float64{
+		0, 10,
+		1, 20,
+		0, 30,
+		1, 10,
+	})
+	for _, drop := range []string{"first", "if_binary"} {
+		enc := NewOneHotEncoder()
+		enc.Drop = drop
+		Xt, _ := enc.FitTransform(X, nil)
+		fmt.Println(drop, enc.GetFeatureNamesOut(nil), enc.DropIdx)
+		fmt.Println(mat.Formatted(Xt))
+		Xinv, _ := enc.InverseTransform(Xt, nil)
+		fmt.Println(mat.Equal(X, Xinv))
+	}
+	// Output:
+	// first [x0_1 x1_20 x1_30] [0 0]
+	// ⎡0  0  0⎤
+	// ⎢1  1  0⎥
+	// ⎢0  0  1⎥
+	// ⎣1  0  0⎦
+	// true
+	// if_binary [x0_1 x1_10 x1_20 x1_30] [0 -1]
+	// ⎡0  1  0  0⎤
+	// ⎢1  0  1  0⎥
+	// ⎢0  0  0  1⎥
+	// ⎣1  1  0  0⎦
+	// true
+}
+
+func ExampleOneHotEncoder_infrequent() {
+	X := mat.NewDense(10, 1, []float64{1, 1, 1, 1, 2, 2, 2, 3, 3, 4})
+	enc := NewOneHotEncoder()
+	enc.MinFrequency = 3
+	enc.HandleUnknown = "infrequent_if_exist"
+	enc.Fit(X, nil)
+	fmt.Println(enc.GetFeatureNamesOut(nil), "infrequent:", enc.InfrequentCategories)
+	Xnew := mat.NewDense(4, 1, []float64{2, 3, 4, 5})
+	Xt, _ := enc.Transform(Xnew, nil)
+	fmt.Println(mat.Formatted(Xt))
+
+	enc = NewOneHotEncoder()
+	enc.MaxCategories = 2
+	enc.Fit(X, nil)
+	fmt.Println(enc.GetFeatureNamesOut(nil), "infrequent:", enc.InfrequentCategories)
+
+	// sparse output
+	Xs := enc.TransformSparse(X)
+	fmt.Println("nnz:", Xs.NNZ(), "equal to dense:", mat.Equal(Xs, mat.NewDense(10, 2, []float64{1, 0, 1, 0, 1, 0, 1, 0, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1})))
+
+	// NaN is a regular category
+	enc = NewOneHotEncoder()
+	Xt, _ = enc.FitTransform(mat.NewDense(3, 1, []float64{1, math.NaN(), 1}), nil)
+	fmt.Println(enc.Values)
+	fmt.Println(mat.Formatted(Xt))
+	// Output:
+	// [x0_1 x0_2 x0_infrequent_sklearn] infrequent: [[3 4]]
+	// ⎡0  1  0⎤
+	// ⎢0  0  1⎥
+	// ⎢0  0  1⎥
+	// ⎣0  0  1⎦
+	// [x0_1 x0_infrequent_sklearn] infrequent: [[2 3 4]]
+	// nnz: 10 equal to dense: true
+	// [[1 NaN]]
+	// ⎡1  0⎤
+	// ⎢0  1⎥
+	// ⎣1  0⎦
+}
+
+func ExampleOrdinalEncoder() {
+	nan := math.NaN()
+	X := mat.NewDense(5, 2, []float64{
+		3, 10,
+		1, nan,
+		2, 10,
+		3, 20,
+		1, 20,
+	})
+	enc := NewOrdinalEncoder()
+	enc.HandleUnknown = "use_encoded_value"
+	enc.UnknownValue = -1
+	Xt, _ := enc.FitTransform(X, nil)
+	fmt.Println(enc.Values)
+	fmt.Println(mat.Formatted(Xt))
+	Xnew := mat.NewDense(2, 2, []float64{4, 20, 2, 30})
+	Xt, _ = enc.Transform(Xnew, nil)
+	fmt.Println(mat.Formatted(Xt))
+	Xinv, _ := enc.InverseTransform(Xt, nil)
+	fmt.Println(mat.Formatted(Xinv))
+	// Output:
+	// [[1 2 3] [10 20 NaN]]
+	// ⎡  2    0⎤
+	// ⎢  0  NaN⎥
+	// ⎢  1    0⎥
+	// ⎢  2    1⎥
+	// ⎣  0    1⎦
+	// ⎡-1   1⎤
+	// ⎣ 1  -1⎦
+	// ⎡NaN   20⎤
+	// ⎣  2  NaN⎦
+}
+
+func ExampleOrdinalEncoder_infrequent() {
+	X := mat.NewDense(8, 1, []float64{0, 0, 0, 1, 1, 2, 3, 0})
+	enc := NewOrdinalEncoder()
+	enc.MinFrequency = 2
+	Xt, _ := enc.FitTransform(X, nil)
+	fmt.Println(enc.InfrequentCategories, mat.Formatted(Xt.T()))
+	Xinv, _ := enc.InverseTransform(Xt, nil)
+	fmt.Println(mat.Formatted(Xinv.T()))
+	// Output:
+	// [[2 3]] [0  0  0  1  1  2  2  0]
+	// [  0    0    0    1    1  NaN  NaN    0]
+}
+
+func ExampleOneHotEncoder_table() {
+	train := table.New(
+		table.NewCategoricalColumn("city", []string{"Paris", "London", "Paris", "Berlin"}, nil),
+		table.NewIntColumn("rooms", []int{3, 2, 3, 1}, nil),
+	)
+	enc := NewOneHotEncoder()
+	enc.HandleUnknown = "ignore"
+	enc.Fit(train, nil)
+	fmt.Println(enc.GetFeatureNamesOut(nil))
+	// codes of string categories are those learnt at fit, "Rome" is unknown
+	test := table.New(
+		table.NewCategoricalColumn("city", []string{"London", "Rome"}, nil),
+		table.NewIntColumn("rooms", []int{2, 1}, nil),
+	)
+	Xout, _ := enc.Transform(test, nil)
+	fmt.Printf("%g\n", mat.Formatted(Xout))
+	// Output:
+	// [city_Berlin city_London city_Paris rooms_1 rooms_2 rooms_3]
+	// ⎡0  1  0  0  1  0⎤
+	// ⎣0  0  0  1  0  0⎦
+}
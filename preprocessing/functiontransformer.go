@@ -6,8 +6,11 @@ import (
 )
 
 // FunctionTransformer Constructs a transformer from an arbitrary callable.
+// FeatureNamesOut computes the output feature names of Func. if nil, output features keep their input names
 type FunctionTransformer struct {
 	Func, InverseFunc func(X, Y *mat.Dense) (X1, Y1 *mat.Dense)
+	FeatureNamesOut   func(inputNames []string) []string
+	NFeaturesIn       int
 }
 
 // NewFunctionTransformer ...
@@ -26,6 +29,7 @@ func (m *FunctionTransformer) TransformerClone() base.Transformer {
 
 // Fit ...
 func (m *FunctionTransformer) Fit(X, Y mat.Matrix) base.Fiter {
+	_, m.NFeaturesIn = X.Dims()
 	return m
 }
 
@@ -41,6 +45,14 @@ func (m *FunctionTransformer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Den
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns FeatureNamesOut(inputNames) or inputNames, which defaults to x0, x1...
+func (m *FunctionTransformer) GetFeatureNamesOut(inputNames []string) []string {
+	if m.FeatureNamesOut != nil {
+		return m.FeatureNamesOut(inputNames)
+	}
+	return base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+}
+
 // InverseTransform ...
 func (m *FunctionTransformer) InverseTransform(X, Y *mat.Dense) (X1, Y1 *mat.Dense) {
 	X1, Y1 = m.InverseFunc(X, Y)
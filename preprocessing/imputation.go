@@ -1,6 +1,7 @@
 package preprocessing
 
 import (
+	"fmt"
 	"math"
 	"sort"
 
@@ -10,10 +11,14 @@ import (
 )
 
 // Imputer ...
-// Stragegy is mean|median|most_frequent. default to mean
+// Stragegy is mean|median|most_frequent|constant. default to mean. "constant" replaces missing values with FillValue
+// if AddIndicator, the output of a MissingIndicator is appended to the imputed features
 type Imputer struct {
 	Strategy      string
+	FillValue     float64
+	AddIndicator  bool
 	MissingValues []float64
+	Indicator     *MissingIndicator
 }
 
 // NewImputer ...
@@ -43,6 +48,8 @@ func (m *Imputer) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 			}
 
 			switch m.Strategy {
+			case "constant":
+				def = m.FillValue
 			case "median":
 				sort.Float64s(tmp)
 				def = stat.Quantile(.5, stat.Empirical, tmp, nil)
@@ -55,6 +62,12 @@ func (m *Imputer) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 		}
 
 	})
+	m.Indicator = nil
+	if m.AddIndicator {
+		m.Indicator = NewMissingIndicator()
+		m.Indicator.ErrorOnNew = false
+		m.Indicator.Fit(X, nil)
+	}
 	return m
 }
 
@@ -80,6 +93,9 @@ func (m *Imputer) Transform(Xmatrix, Ymatrix mat.Matrix) (Xout, Yout *mat.Dense)
 		}
 
 	})
+	if m.Indicator != nil {
+		Xout = m.Indicator.Append(Xout, X)
+	}
 	return
 }
 
@@ -89,8 +105,143 @@ func (m *Imputer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns inputNames (default x0, x1...), followed by the names of the missing indicators if
+// AddIndicator
+func (m *Imputer) GetFeatureNamesOut(inputNames []string) []string {
+	names := base.FeatureNamesIn(inputNames, len(m.MissingValues))
+	if m.Indicator != nil {
+		names = append(names, m.Indicator.GetFeatureNamesOut(inputNames)...)
+	}
+	return names
+}
+
 // InverseTransform for Imputer ...
 func (m *Imputer) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	Xout, Yout = X, Y
+	if X == nil {
+		return
+	}
+	if m.Indicator != nil {
+		// restore missing values and drop indicator columns
+		nSamples, nColumns := X.Dims()
+		nFeatures := nColumns - len(m.Indicator.FeatureIndices)
+		Xout = mat.DenseCopyOf(X.Slice(0, nSamples, 0, nFeatures))
+		for i := 0; i < nSamples; i++ {
+			for c, feature := range m.Indicator.FeatureIndices {
+				if X.At(i, nFeatures+c) != 0 {
+					Xout.Set(i, feature, math.NaN())
+				}
+			}
+		}
+	}
 	return
 }
+
+// MissingIndicator transforms X into a binary matrix indicating missing (NaN) values.
+// Features is "missing-only" (default: only features having missing values during fit) or "all".
+// if ErrorOnNew, Transform panics when a feature without missing values during fit has missing values.
+// FeatureIndices are the indices of the features of X reported in the output
+type MissingIndicator struct {
+	Features   string
+	ErrorOnNew bool
+
+	FeatureIndices []int
+	NFeaturesIn    int
+}
+
+// NewMissingIndicator returns a *MissingIndicator
+func NewMissingIndicator() *MissingIndicator {
+	return &MissingIndicator{Features: "missing-only", ErrorOnNew: true}
+}
+
+// TransformerClone ...
+func (m *MissingIndicator) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit determines the features to report
+func (m *MissingIndicator) Fit(X, Y mat.Matrix) base.Fiter {
+	var nSamples int
+	nSamples, m.NFeaturesIn = X.Dims()
+	m.FeatureIndices = nil
+	for j := 0; j < m.NFeaturesIn; j++ {
+		switch m.Features {
+		case "all":
+			m.FeatureIndices = append(m.FeatureIndices, j)
+		case "", "missing-only":
+			for i := 0; i < nSamples; i++ {
+				if math.IsNaN(X.At(i, j)) {
+					m.FeatureIndices = append(m.FeatureIndices, j)
+					break
+				}
+			}
+		default:
+			panic(fmt.Errorf("MissingIndicator: unknown Features %s", m.Features))
+		}
+	}
+	return m
+}
+
+// Transform returns 1 where X is NaN for features in FeatureIndices, else 0
+func (m *MissingIndicator) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != m.NFeaturesIn {
+		panic(fmt.Errorf("MissingIndicator: X has %d features, expected %d", nFeatures, m.NFeaturesIn))
+	}
+	if m.ErrorOnNew {
+		reported := make([]bool, nFeatures)
+		for _, j := range m.FeatureIndices {
+			reported[j] = true
+		}
+		for i := 0; i < nSamples; i++ {
+			for j := 0; j < nFeatures; j++ {
+				if !reported[j] && math.IsNaN(X.At(i, j)) {
+					panic(fmt.Errorf("MissingIndicator: feature %d has missing values in transform but had none in fit", j))
+				}
+			}
+		}
+	}
+	Xout = &mat.Dense{}
+	if len(m.FeatureIndices) > 0 {
+		Xout = mat.NewDense(nSamples, len(m.FeatureIndices), nil)
+	}
+	for i := 0; i < nSamples; i++ {
+		for c, j := range m.FeatureIndices {
+			if math.IsNaN(X.At(i, j)) {
+				Xout.Set(i, c, 1)
+			}
+		}
+	}
+	return Xout, base.ToDense(Y)
+}
+
+// FitTransform fit to dat, then transform it
+func (m *MissingIndicator) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns missingindicator_ followed by the input names of reported features.
+// inputNames defaults to x0, x1...
+func (m *MissingIndicator) GetFeatureNamesOut(inputNames []string) []string {
+	inputNames = base.FeatureNamesIn(inputNames, m.NFeaturesIn)
+	names := make([]string, len(m.FeatureIndices))
+	for c, j := range m.FeatureIndices {
+		names[c] = "missingindicator_" + inputNames[j]
+	}
+	return names
+}
+
+// Append returns Ximputed with the missing indicators of X appended
+func (m *MissingIndicator) Append(Ximputed *mat.Dense, X mat.Matrix) *mat.Dense {
+	if len(m.FeatureIndices) == 0 {
+		return Ximputed
+	}
+	indicator, _ := m.Transform(X, nil)
+	nSamples, nFeatures := Ximputed.Dims()
+	Xout := mat.NewDense(nSamples, nFeatures+len(m.FeatureIndices), nil)
+	Xout.Slice(0, nSamples, 0, nFeatures).(*mat.Dense).Copy(Ximputed)
+	Xout.Slice(0, nSamples, nFeatures, nFeatures+len(m.FeatureIndices)).(*mat.Dense).Copy(indicator)
+	return Xout
+}
@@ -48,3 +48,32 @@ func ExampleImputer() {
 	// ⎢                 6  3.6666666666666665⎥
 	// ⎣                 7                   6⎦
 }
+
+func ExampleMissingIndicator() {
+	nan := math.NaN()
+	X := mat.NewDense(3, 4, []float64{
+		nan, 1, 3, nan,
+		4, 0, nan, nan,
+		8, 1, 0, nan,
+	})
+	indicator := NewMissingIndicator()
+	Xt, _ := indicator.FitTransform(X, nil)
+	fmt.Println(indicator.FeatureIndices, indicator.GetFeatureNamesOut(nil))
+	fmt.Println(mat.Formatted(Xt))
+	imp := &Imputer{Strategy: "constant", FillValue: -1, AddIndicator: true}
+	Xt, _ = imp.FitTransform(X.Slice(0, 3, 0, 3), nil)
+	fmt.Println(mat.Formatted(Xt))
+	Xinv, _ := imp.InverseTransform(Xt, nil)
+	fmt.Println(mat.Formatted(Xinv))
+	// Output:
+	// [0 2 3] [missingindicator_x0 missingindicator_x2 missingindicator_x3]
+	// ⎡1  0  1⎤
+	// ⎢0  1  1⎥
+	// ⎣0  0  1⎦
+	// ⎡-1   1   3   1   0⎤
+	// ⎢ 4   0  -1   0   1⎥
+	// ⎣ 8   1   0   0   0⎦
+	// ⎡NaN    1    3⎤
+	// ⎢  4    0  NaN⎥
+	// ⎣  8    1    0⎦
+}
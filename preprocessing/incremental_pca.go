@@ -0,0 +1,179 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// IncrementalPCA is a principal component analysis computed over mini-batches.
+// PartialFit updates Components with a new batch so that data need not fit in memory.
+// Fit splits X in batches of BatchSize rows (5*NFeatures if BatchSize<=0).
+// Transform, InverseTransform and scoring methods are those of PCA
+// see D. Ross, J. Lim, R. Lin, M. Yang, Incremental Learning for Robust Visual Tracking,
+// International Journal of Computer Vision, Volume 77, Issue 1-3, pp. 125-141, May 2008.
+type IncrementalPCA struct {
+	PCA
+	BatchSize int
+
+	Var          []float64
+	NSamplesSeen int
+}
+
+var _ InverseTransformer = &IncrementalPCA{}
+
+// NewIncrementalPCA returns an *IncrementalPCA
+func NewIncrementalPCA() *IncrementalPCA { return &IncrementalPCA{} }
+
+// TransformerClone ...
+func (m *IncrementalPCA) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Reset resets IncrementalPCA to its initial state
+func (m *IncrementalPCA) Reset() *IncrementalPCA {
+	m.NSamplesSeen = 0
+	m.Components = nil
+	return m
+}
+
+// Fit fits the model with X using mini-batches of BatchSize rows
+func (m *IncrementalPCA) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := base.ToDense(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	m.Reset()
+	batchSize := m.BatchSize
+	if batchSize <= 0 {
+		batchSize = 5 * nFeatures
+	}
+	for _, batch := range genBatches(nSamples, batchSize, m.NComponents) {
+		m.PartialFit(X.Slice(batch[0], batch[1], 0, nFeatures), nil)
+	}
+	return m
+}
+
+// PartialFit updates the model with the batch X
+func (m *IncrementalPCA) PartialFit(Xmatrix, Ymatrix mat.Matrix) base.Transformer {
+	X := mat.DenseCopyOf(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	if nSamples == 0 {
+		return m
+	}
+	if m.NComponents <= 0 {
+		if m.Components == nil {
+			m.NComponents = nSamples
+			if nFeatures < nSamples {
+				m.NComponents = nFeatures
+			}
+		} else {
+			m.NComponents, _ = m.Components.Dims()
+		}
+	}
+	if m.NComponents > nFeatures {
+		panic(fmt.Errorf("NComponents=%d invalid for NFeatures=%d, need more rows than columns for IncrementalPCA processing", m.NComponents, nFeatures))
+	}
+	if m.NComponents > nSamples {
+		panic(fmt.Errorf("NComponents=%d must be less or equal to the batch number of samples %d", m.NComponents, nSamples))
+	}
+	if m.NSamplesSeen > 0 && m.NFeatures != nFeatures {
+		panic(fmt.Errorf("number of features of the new batch (%d) does not match the number of features of previous data (%d)", nFeatures, m.NFeatures))
+	}
+	var lastMean, lastVar *mat.Dense
+	if m.NSamplesSeen == 0 {
+		lastMean, lastVar = mat.NewDense(1, nFeatures, nil), mat.NewDense(1, nFeatures, nil)
+	} else {
+		lastMean, lastVar = mat.NewDense(1, nFeatures, m.Mean), mat.NewDense(1, nFeatures, m.Var)
+	}
+	colMean, colVar, nTotal := IncrementalMeanAndVar(X, lastMean, lastVar, m.NSamplesSeen)
+
+	// whitening is computed on centered data
+	if m.NSamplesSeen == 0 {
+		for i := 0; i < nSamples; i++ {
+			floats.Sub(X.RawRowView(i), colMean.RawRowView(0))
+		}
+	} else {
+		colBatchMean := DenseMean(nil, X)
+		for i := 0; i < nSamples; i++ {
+			floats.Sub(X.RawRowView(i), colBatchMean.RawRowView(0))
+		}
+		// build matrix of combined previous basis and new data
+		nComponents, _ := m.Components.Dims()
+		meanCorrection := make([]float64, nFeatures)
+		floats.SubTo(meanCorrection, lastMean.RawRowView(0), colBatchMean.RawRowView(0))
+		floats.Scale(math.Sqrt(float64(m.NSamplesSeen)*float64(nSamples)/float64(nTotal)), meanCorrection)
+		stacked := mat.NewDense(nComponents+nSamples+1, nFeatures, nil)
+		for c := 0; c < nComponents; c++ {
+			floats.ScaleTo(stacked.RawRowView(c), m.SingularValues[c], m.Components.RawRowView(c))
+		}
+		for i := 0; i < nSamples; i++ {
+			copy(stacked.RawRowView(nComponents+i), X.RawRowView(i))
+		}
+		copy(stacked.RawRowView(nComponents+nSamples), meanCorrection)
+		X = stacked
+	}
+
+	var svd mat.SVD
+	if !svd.Factorize(X, mat.SVDThin) {
+		panic("IncrementalPCA: svd failed")
+	}
+	V := &mat.Dense{}
+	svd.VTo(V)
+	VT := mat.DenseCopyOf(V.T())
+	base.SVDFlip(nil, VT, false)
+	S := svd.Values(nil)
+
+	explainedVariance := make([]float64, len(S))
+	explainedVarianceRatio := make([]float64, len(S))
+	totalVariance := floats.Sum(colVar.RawRowView(0)) * float64(nTotal)
+	for i, s := range S {
+		explainedVariance[i] = s * s / float64(nTotal-1)
+		explainedVarianceRatio[i] = s * s / totalVariance
+	}
+
+	m.NSamplesSeen = nTotal
+	m.NSamples, m.NFeatures = nTotal, nFeatures
+	m.Components = mat.DenseCopyOf(VT.Slice(0, m.NComponents, 0, nFeatures))
+	m.SingularValues = S[:m.NComponents]
+	m.Mean = colMean.RawRowView(0)
+	m.Var = colVar.RawRowView(0)
+	m.ExplainedVariance = explainedVariance[:m.NComponents]
+	m.ExplainedVarianceRatio = explainedVarianceRatio[:m.NComponents]
+	m.NoiseVariance = 0
+	if m.NComponents < len(explainedVariance) {
+		m.NoiseVariance = floats.Sum(explainedVariance[m.NComponents:]) / float64(len(explainedVariance)-m.NComponents)
+	}
+	return m
+}
+
+// FitTransform fit to data, then transform it
+func (m *IncrementalPCA) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns incrementalpca0, incrementalpca1... one name per component. inputNames are unused
+func (m *IncrementalPCA) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("incrementalpca", m.Components.RawMatrix().Rows)
+}
+
+// genBatches returns [start,end) row ranges of size batchSize.
+// the last batch is merged with the previous one when it is smaller than minBatchSize
+func genBatches(n, batchSize, minBatchSize int) (batches [][2]int) {
+	start := 0
+	for ; start+batchSize <= n; start += batchSize {
+		end := start + batchSize
+		if end+minBatchSize > n {
+			break
+		}
+		batches = append(batches, [2]int{start, end})
+	}
+	if start < n {
+		batches = append(batches, [2]int{start, n})
+	}
+	return
+}
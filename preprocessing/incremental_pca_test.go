@@ -0,0 +1,58 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleIncrementalPCA() {
+	X := mat.NewDense(12, 3, nil)
+	for i := 0; i < 12; i++ {
+		t := float64(i)
+		X.SetRow(i, []float64{t, math.Sin(t), .3*t - 2*math.Sin(t)})
+	}
+	ipca := NewIncrementalPCA()
+	ipca.NComponents = 2
+	ipca.BatchSize = 4
+	ipca.Fit(X, nil)
+
+	pca := NewPCA()
+	pca.NComponents = 2
+	pca.Fit(X, nil)
+
+	fmt.Printf("samples seen       : %d\n", ipca.NSamplesSeen)
+	fmt.Printf("ipca variance ratio: %.4f\n", ipca.ExplainedVarianceRatio)
+	fmt.Printf("pca variance ratio : %.4f\n", pca.ExplainedVarianceRatio)
+	// components are equal up to sign
+	absComponents := func(c *mat.Dense) []float64 {
+		a := mat.DenseCopyOf(c).RawMatrix().Data
+		for i := range a {
+			a[i] = math.Abs(a[i])
+		}
+		return a
+	}
+	fmt.Println("same components    :", floats.EqualApprox(absComponents(ipca.Components), absComponents(pca.Components), 1e-6))
+
+	// partial fits on batches give the same model
+	ipca2 := NewIncrementalPCA()
+	ipca2.NComponents = 2
+	for start := 0; start < 12; start += 6 {
+		ipca2.PartialFit(X.Slice(start, start+6, 0, 3), nil)
+	}
+	Xt, _ := ipca2.Transform(X, nil)
+	X2, _ := ipca2.InverseTransform(Xt, nil)
+	fmt.Printf("mean               : %.4f\n", ipca2.Mean)
+	diff := &mat.Dense{}
+	diff.Sub(X, X2)
+	fmt.Printf("reconstruction err : %.4f\n", mat.Norm(diff, 2))
+	// Output:
+	// samples seen       : 12
+	// ipca variance ratio: [0.8841 0.1159]
+	// pca variance ratio : [0.8841 0.1159]
+	// same components    : true
+	// mean               : [5.5000 0.0343 1.5815]
+	// reconstruction err : 0.0000
+}
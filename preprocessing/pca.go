@@ -1,39 +1,118 @@
 package preprocessing
 
 import (
+	"math"
+
 	"github.com/RobinRCM/sklearn/base"
 
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
-// PCA is a thin single value decomposition transformer
+// PCA is a principal component analysis transformer.
+// data is centered on Mean before being projected on Components.
+// SVDSolver is "auto","full" or "randomized". "auto" uses "randomized" when data is larger than 500x500 and
+// less than 80% of components are kept.
+// IteratedPower is the number of power iterations for the randomized solver (<0 for auto) and NOversamples the
+// number of additional random vectors it samples.
+// if Whiten is true, transformed components are scaled to unit variance
 type PCA struct {
-	mat.SVD
-	MinVarianceRatio                       float64
-	NComponents                            int
-	SingularValues, ExplainedVarianceRatio []float64
+	MinVarianceRatio float64
+	NComponents      int
+	Whiten           bool
+	SVDSolver        string
+	IteratedPower    int
+	NOversamples     int
+	RandomState      base.RandomState
+
+	Components                                                      *mat.Dense
+	Mean, SingularValues, ExplainedVariance, ExplainedVarianceRatio []float64
+	NoiseVariance                                                   float64
+	NSamples, NFeatures                                             int
 }
 
+var _ InverseTransformer = &PCA{}
+
 // NewPCA returns a *PCA
-func NewPCA() *PCA { return &PCA{} }
+func NewPCA() *PCA { return &PCA{SVDSolver: "auto", IteratedPower: -1, NOversamples: 10} }
 
 // TransformerClone ...
 func (m *PCA) TransformerClone() base.Transformer {
 	clone := *m
+	if sourceCloner, ok := clone.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
 	return &clone
 }
 
-// Fit computes the svd of X
+// Fit computes Mean and Components of X
 func (m *PCA) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 	X := base.ToDense(Xmatrix)
-	_, c := X.Dims()
-	m.SVD.Factorize(X, mat.SVDThin)
-	m.SingularValues = make([]float64, c)
-	m.ExplainedVarianceRatio = make([]float64, c)
-	m.SVD.Values(m.SingularValues)
-	floats.MulTo(m.ExplainedVarianceRatio, m.SingularValues, m.SingularValues)
-	floats.Scale(1./floats.Sum(m.ExplainedVarianceRatio), m.ExplainedVarianceRatio)
+	nSamples, nFeatures := X.Dims()
+	m.NSamples, m.NFeatures = nSamples, nFeatures
+	minDim := nSamples
+	if nFeatures < minDim {
+		minDim = nFeatures
+	}
+	m.Mean = make([]float64, nFeatures)
+	Xc := mat.NewDense(nSamples, nFeatures, nil)
+	for j := 0; j < nFeatures; j++ {
+		col := Xc.ColView(j).(*mat.VecDense).RawVector()
+		for i := 0; i < nSamples; i++ {
+			m.Mean[j] += X.At(i, j)
+		}
+		m.Mean[j] /= float64(nSamples)
+		for i, pos := 0, 0; i < nSamples; i, pos = i+1, pos+col.Inc {
+			col.Data[pos] = X.At(i, j) - m.Mean[j]
+		}
+	}
+	ddof := float64(nSamples - 1)
+	if ddof <= 0 {
+		ddof = 1
+	}
+
+	solver := m.SVDSolver
+	if solver == "" || solver == "auto" {
+		solver = "full"
+		if nSamples > 500 && nFeatures > 500 && m.MinVarianceRatio <= 0 && m.NComponents >= 1 && float64(m.NComponents) < .8*float64(minDim) {
+			solver = "randomized"
+		}
+	}
+	if m.NComponents <= 0 || m.NComponents > minDim {
+		m.NComponents = minDim
+	}
+	var VT *mat.Dense
+	var totalVariance float64
+	switch solver {
+	case "randomized":
+		if m.MinVarianceRatio > 0 {
+			panic("PCA: MinVarianceRatio is not supported by randomized solver")
+		}
+		_, m.SingularValues, VT = base.RandomizedSVD(Xc, m.NComponents, m.NOversamples, m.IteratedPower, m.RandomState)
+		xcol := make([]float64, nSamples)
+		for j := 0; j < nFeatures; j++ {
+			mat.Col(xcol, j, Xc)
+			totalVariance += floats.Dot(xcol, xcol) / ddof
+		}
+	default:
+		var svd mat.SVD
+		if !svd.Factorize(Xc, mat.SVDThin) {
+			panic("PCA: svd failed")
+		}
+		V := &mat.Dense{}
+		svd.VTo(V)
+		VT = mat.DenseCopyOf(V.T())
+		m.SingularValues = svd.Values(nil)
+		for _, s := range m.SingularValues {
+			totalVariance += s * s / ddof
+		}
+	}
+	m.ExplainedVariance = make([]float64, len(m.SingularValues))
+	m.ExplainedVarianceRatio = make([]float64, len(m.SingularValues))
+	for i, s := range m.SingularValues {
+		m.ExplainedVariance[i] = s * s / ddof
+		m.ExplainedVarianceRatio[i] = m.ExplainedVariance[i] / totalVariance
+	}
 
 	if m.MinVarianceRatio > 0 {
 		thres := m.MinVarianceRatio
@@ -43,23 +122,40 @@ func (m *PCA) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 			ExplainedVarianceRatio += m.ExplainedVarianceRatio[nComponents]
 		}
 		m.NComponents = nComponents
-	} else {
-		if m.NComponents == 0 {
-			m.NComponents = c
-		}
 	}
 
+	m.NoiseVariance = 0
+	if m.NComponents < minDim {
+		if solver == "randomized" {
+			m.NoiseVariance = (totalVariance - floats.Sum(m.ExplainedVariance)) / float64(minDim-m.NComponents)
+		} else {
+			m.NoiseVariance = floats.Sum(m.ExplainedVariance[m.NComponents:]) / float64(len(m.ExplainedVariance)-m.NComponents)
+		}
+	}
+	m.Components = mat.DenseCopyOf(VT.Slice(0, m.NComponents, 0, nFeatures))
+	m.SingularValues = m.SingularValues[:m.NComponents]
+	m.ExplainedVariance = m.ExplainedVariance[:m.NComponents]
+	m.ExplainedVarianceRatio = m.ExplainedVarianceRatio[:m.NComponents]
 	return m
 }
 
-// Transform Transforms X
+// Transform projects X on Components
 func (m *PCA) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
-	var v = new(mat.Dense)
-	m.SVD.VTo(v)
 	nSamples, _ := X.Dims()
-	vRows, _ := v.Dims()
+	Xc := m.center(X)
 	Xout = mat.NewDense(nSamples, m.NComponents, nil)
-	Xout.Mul(X, base.MatDenseSlice(v, 0, vRows, 0, m.NComponents))
+	Xout.Mul(Xc, m.Components.T())
+	if m.Whiten {
+		for c, ev := range m.ExplainedVariance {
+			scale := math.Sqrt(ev)
+			if scale == 0 {
+				continue
+			}
+			for i := 0; i < nSamples; i++ {
+				Xout.Set(i, c, Xout.At(i, c)/scale)
+			}
+		}
+	}
 
 	Yout = base.ToDense(Y)
 	return
@@ -71,18 +167,102 @@ func (m *PCA) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
 	return m.Transform(X, Y)
 }
 
+// GetFeatureNamesOut returns pca0, pca1... one name per component. inputNames are unused
+func (m *PCA) GetFeatureNamesOut(inputNames []string) []string {
+	return base.PrefixedFeatureNames("pca", m.Components.RawMatrix().Rows)
+}
+
 // InverseTransform put X into original space
 func (m *PCA) InverseTransform(X, Y *mat.Dense) (Xout, Yout *mat.Dense) {
 	if X == nil {
 		return X, Y
 	}
-
-	var v = new(mat.Dense)
-	m.SVD.VTo(v)
 	nSamples, _ := X.Dims()
-	_, vCols := v.Dims()
-	Xout = mat.NewDense(nSamples, vCols, nil)
-	Xout.Mul(X, v.T())
+	Xw := X
+	if m.Whiten {
+		Xw = mat.DenseCopyOf(X)
+		for c, ev := range m.ExplainedVariance {
+			scale := math.Sqrt(ev)
+			for i := 0; i < nSamples; i++ {
+				Xw.Set(i, c, Xw.At(i, c)*scale)
+			}
+		}
+	}
+	Xout = mat.NewDense(nSamples, m.NFeatures, nil)
+	Xout.Mul(Xw, m.Components)
+	for i := 0; i < nSamples; i++ {
+		floats.Add(Xout.RawRowView(i), m.Mean)
+	}
 	Yout = Y
 	return
 }
+
+// GetCovariance computes data covariance with the generative model.
+// cov = Components.T * diag(ExplainedVariance-NoiseVariance) * Components + NoiseVariance * I
+func (m *PCA) GetCovariance() *mat.Dense {
+	components := m.whitenedComponents()
+	nFeatures := m.NFeatures
+	scaled := mat.NewDense(m.NComponents, nFeatures, nil)
+	for c, ev := range m.ExplainedVariance {
+		diff := math.Max(ev-m.NoiseVariance, 0)
+		floats.ScaleTo(scaled.RawRowView(c), diff, components.RawRowView(c))
+	}
+	cov := mat.NewDense(nFeatures, nFeatures, nil)
+	cov.Mul(components.T(), scaled)
+	for j := 0; j < nFeatures; j++ {
+		cov.Set(j, j, cov.At(j, j)+m.NoiseVariance)
+	}
+	return cov
+}
+
+// GetPrecision computes data precision matrix (inverse of covariance) with the generative model.
+func (m *PCA) GetPrecision() *mat.Dense {
+	precision := &mat.Dense{}
+	if err := precision.Inverse(m.GetCovariance()); err != nil {
+		panic(err)
+	}
+	return precision
+}
+
+// ScoreSamples returns the log-likelihood of each sample under the probabilistic PCA model
+// see M. Tipping and C. Bishop, Probabilistic Principal Component Analysis,
+// Journal of the Royal Statistical Society, Series B, 61, Part 3, pp. 611-622
+func (m *PCA) ScoreSamples(X mat.Matrix) []float64 {
+	nSamples, nFeatures := X.Dims()
+	Xc := m.center(X)
+	precision := m.GetPrecision()
+	logDetPrecision, _ := mat.LogDet(precision)
+	XP := &mat.Dense{}
+	XP.Mul(Xc, precision)
+	logLike := make([]float64, nSamples)
+	for i := range logLike {
+		logLike[i] = -.5*floats.Dot(XP.RawRowView(i), Xc.RawRowView(i)) - .5*(float64(nFeatures)*math.Log(2*math.Pi)-logDetPrecision)
+	}
+	return logLike
+}
+
+// Score returns the average log-likelihood of all samples. Y is unused
+func (m *PCA) Score(X, Y mat.Matrix) float64 {
+	logLike := m.ScoreSamples(X)
+	return floats.Sum(logLike) / float64(len(logLike))
+}
+
+func (m *PCA) center(X mat.Matrix) *mat.Dense {
+	Xc := mat.DenseCopyOf(X)
+	nSamples, _ := Xc.Dims()
+	for i := 0; i < nSamples; i++ {
+		floats.Sub(Xc.RawRowView(i), m.Mean)
+	}
+	return Xc
+}
+
+func (m *PCA) whitenedComponents() *mat.Dense {
+	components := m.Components
+	if m.Whiten {
+		components = mat.DenseCopyOf(m.Components)
+		for c, ev := range m.ExplainedVariance {
+			floats.Scale(math.Sqrt(ev), components.RawRowView(c))
+		}
+	}
+	return components
+}
@@ -3,7 +3,9 @@ package preprocessing
 import (
 	"fmt"
 
+	"github.com/RobinRCM/sklearn/base"
 	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
 )
 
 func ExamplePCA() {
@@ -30,3 +32,60 @@ func ExamplePCA() {
 	// inversed   : [-1.000 -1.000 -2.000 -1.000 -3.000 -2.000 1.000 1.000 2.000 1.000 3.000 2.000]
 
 }
+
+func ExamplePCA_whiten() {
+	X := mat.NewDense(6, 3, []float64{
+		-1, -1, 2,
+		-2, -1, 1,
+		-3, -2, 0,
+		1, 1, 1,
+		2, 1, -1,
+		3, 2, 0})
+	pca := NewPCA()
+	pca.NComponents = 2
+	pca.Whiten = true
+	Xp, _ := pca.FitTransform(X, nil)
+	fmt.Printf("mean       : %.3f\n", pca.Mean)
+	fmt.Printf("variance   : %.3f\n", pca.ExplainedVariance)
+	fmt.Printf("noise      : %.3f\n", pca.NoiseVariance)
+	fmt.Printf("unit var   : %.3f %.3f\n", stat.Variance(mat.Col(nil, 0, Xp), nil), stat.Variance(mat.Col(nil, 1, Xp), nil))
+	X2, _ := pca.InverseTransform(Xp, nil)
+	fmt.Printf("inversed   :\n%.3f\n", mat.Formatted(X2))
+	fmt.Printf("score      : %.3f\n", pca.Score(X, nil))
+	// Output:
+	// mean       : [0.000 0.000 0.500]
+	// variance   : [8.133 0.909]
+	// noise      : 0.058
+	// unit var   : 1.000 1.000
+	// inversed   :
+	// ⎡-1.196  -0.704   1.983⎤
+	// ⎢-1.866  -1.202   1.012⎥
+	// ⎢-2.996  -2.006   0.000⎥
+	// ⎢ 1.143   0.784   1.012⎥
+	// ⎢ 1.893   1.162  -1.009⎥
+	// ⎣ 3.022   1.966   0.002⎦
+	// score      : -4.261
+}
+
+func ExamplePCA_randomized() {
+	X := mat.NewDense(8, 4, nil)
+	for i := 0; i < 8; i++ {
+		t := float64(i)
+		X.SetRow(i, []float64{t, 2 * t, -t, .5 * t * t})
+	}
+	full := NewPCA()
+	full.NComponents = 2
+	full.Fit(X, nil)
+	randomized := NewPCA()
+	randomized.NComponents = 2
+	randomized.SVDSolver = "randomized"
+	randomized.RandomState = base.NewSource(7)
+	randomized.Fit(X, nil)
+	fmt.Printf("full       : %.4f\n", full.SingularValues)
+	fmt.Printf("randomized : %.4f\n", randomized.SingularValues)
+	fmt.Printf("ratio      : %.4f\n", randomized.ExplainedVarianceRatio)
+	// Output:
+	// full       : [28.1991 3.6483]
+	// randomized : [28.1991 3.6483]
+	// ratio      : [0.9835 0.0165]
+}
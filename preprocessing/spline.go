@@ -0,0 +1,300 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SplineTransformer generates a univariate B-spline basis of each feature.
+// Knots is "uniform" (default, NKnots equidistant knots between the min and max of the feature), "quantile" (NKnots
+// quantiles of the feature) or a mat.Matrix of sorted knots, one column per feature.
+// NKnots (default 5) is the number of knots, Degree (default 3) the degree of the polynomial pieces.
+// Extrapolation is the behaviour outside the range of the knots: "constant" (default, the value at the boundary),
+// "linear" (linear continuation of the boundary splines), "continue" (the boundary polynomial pieces are extended),
+// "periodic" (periodic splines with a period equal to the range of the knots, for cyclic features) or "error".
+// the output has NKnots+Degree-1 columns per feature (NKnots-1 for periodic splines), less one if IncludeBias is false
+type SplineTransformer struct {
+	NKnots        int
+	Degree        int
+	Knots         interface{}
+	Extrapolation string
+	IncludeBias   bool
+
+	NFeaturesIn, NFeaturesOut int
+	// BSplineKnots are the knots of the splines of each feature, including Degree extra knots on each side
+	BSplineKnots [][]float64
+}
+
+// NewSplineTransformer returns a *SplineTransformer with 5 uniform knots, cubic splines and constant extrapolation
+func NewSplineTransformer() *SplineTransformer {
+	return &SplineTransformer{NKnots: 5, Degree: 3, Knots: "uniform", Extrapolation: "constant", IncludeBias: true}
+}
+
+// TransformerClone ...
+func (m *SplineTransformer) TransformerClone() base.Transformer {
+	clone := *m
+	return &clone
+}
+
+// Fit computes the knots of each feature
+func (m *SplineTransformer) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
+	X := base.ToDense(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	if m.Degree < 0 {
+		panic(fmt.Errorf("SplineTransformer: Degree must be >= 0, got %d", m.Degree))
+	}
+	switch m.Extrapolation {
+	case "error", "constant", "linear", "continue", "periodic":
+	default:
+		panic(fmt.Errorf("SplineTransformer: unknown Extrapolation %s", m.Extrapolation))
+	}
+	m.NFeaturesIn = nFeatures
+	m.BSplineKnots = make([][]float64, nFeatures)
+	col := make([]float64, nSamples)
+	for feature := 0; feature < nFeatures; feature++ {
+		var knots []float64
+		switch k := m.Knots.(type) {
+		case nil, string:
+			if m.NKnots < 2 {
+				panic(fmt.Errorf("SplineTransformer: NKnots must be >= 2, got %d", m.NKnots))
+			}
+			mat.Col(col, feature, X)
+			sort.Float64s(col)
+			knots = make([]float64, m.NKnots)
+			switch k {
+			case nil, "uniform":
+				for i := range knots {
+					knots[i] = col[0] + (col[nSamples-1]-col[0])*float64(i)/float64(m.NKnots-1)
+				}
+			case "quantile":
+				for i := range knots {
+					knots[i] = percentile(col, float64(i)/float64(m.NKnots-1))
+				}
+			default:
+				panic(fmt.Errorf("SplineTransformer: unknown Knots %s", k))
+			}
+		case mat.Matrix:
+			nKnots, c := k.Dims()
+			if c != nFeatures || nKnots < 2 {
+				panic(fmt.Errorf("SplineTransformer: Knots must have at least 2 rows and %d columns, got (%d,%d)", nFeatures, nKnots, c))
+			}
+			knots = mat.Col(nil, feature, k)
+		default:
+			panic(fmt.Errorf("SplineTransformer: Knots must be \"uniform\", \"quantile\" or a mat.Matrix, got %T", m.Knots))
+		}
+		for i := 1; i < len(knots); i++ {
+			if !(knots[i] > knots[i-1]) {
+				panic(fmt.Errorf("SplineTransformer: knots of feature %d must be strictly increasing", feature))
+			}
+		}
+		m.BSplineKnots[feature] = m.extendKnots(knots)
+	}
+	m.NFeaturesOut = nFeatures * m.nSplinesOut()
+	return m
+}
+
+// extendKnots adds Degree knots on each side of knots: periodically for periodic splines, else equidistant with the
+// first and last intervals
+func (m *SplineTransformer) extendKnots(knots []float64) []float64 {
+	n, degree := len(knots), m.Degree
+	extended := make([]float64, 0, n+2*degree)
+	if m.Extrapolation == "periodic" {
+		if degree >= n {
+			panic(fmt.Errorf("SplineTransformer: periodic splines require Degree < number of knots, got %d >= %d", degree, n))
+		}
+		period := knots[n-1] - knots[0]
+		for _, t := range knots[n-1-degree : n-1] {
+			extended = append(extended, t-period)
+		}
+		extended = append(extended, knots...)
+		for _, t := range knots[1 : degree+1] {
+			extended = append(extended, t+period)
+		}
+		return extended
+	}
+	distMin, distMax := knots[1]-knots[0], knots[n-1]-knots[n-2]
+	for i := degree; i > 0; i-- {
+		extended = append(extended, knots[0]-float64(i)*distMin)
+	}
+	extended = append(extended, knots...)
+	for i := 1; i <= degree; i++ {
+		extended = append(extended, knots[n-1]+float64(i)*distMax)
+	}
+	return extended
+}
+
+// nSplines returns the number of B-splines of a feature before periodic wrapping
+func (m *SplineTransformer) nSplines() int {
+	return len(m.BSplineKnots[0]) - m.Degree - 1
+}
+
+// nSplinesOut returns the number of output columns of a feature
+func (m *SplineTransformer) nSplinesOut() int {
+	n := m.nSplines()
+	if m.Extrapolation == "periodic" {
+		n -= m.Degree
+	}
+	if !m.IncludeBias {
+		n--
+	}
+	return n
+}
+
+// Transform computes the B-spline basis of each feature
+func (m *SplineTransformer) Transform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != m.NFeaturesIn {
+		panic(fmt.Errorf("SplineTransformer: X has %d features, expected %d", nFeatures, m.NFeaturesIn))
+	}
+	degree, nSplines, nOut := m.Degree, m.nSplines(), m.nSplinesOut()
+	Xout = mat.NewDense(nSamples, nFeatures*nOut, nil)
+	basis := make([]float64, nSplines)
+	values, derivatives := make([]float64, degree+1), make([]float64, degree+1)
+	for feature, t := range m.BSplineKnots {
+		xmin, xmax := t[degree], t[nSplines]
+		for i := 0; i < nSamples; i++ {
+			x := X.At(i, feature)
+			for j := range basis {
+				basis[j] = 0
+			}
+			switch {
+			case m.Extrapolation == "periodic":
+				x = xmin + math.Mod(x-xmin, xmax-xmin)
+				if x < xmin {
+					x += xmax - xmin
+				}
+				first := bsplineBasis(t, degree, x, values)
+				for r, v := range values {
+					basis[(first+r)%(nSplines-degree)] += v
+				}
+			case x >= xmin && x <= xmax, m.Extrapolation == "continue":
+				first := bsplineBasis(t, degree, x, values)
+				copy(basis[first:], values)
+			case m.Extrapolation == "error":
+				panic(fmt.Errorf("SplineTransformer: X contains value %g out of the range [%g,%g] of feature %d", x, xmin, xmax, feature))
+			default:
+				// constant or linear: value (and slope) of the boundary splines at the boundary
+				boundary, first, last := xmin, 0, degree
+				if x > xmax {
+					boundary, first, last = xmax, nSplines-degree, nSplines
+				}
+				offset := bsplineBasis(t, degree, boundary, values)
+				if m.Extrapolation == "linear" {
+					bsplineDerivatives(t, degree, boundary, derivatives)
+				}
+				for j := first; j < last; j++ {
+					if r := j - offset; r >= 0 && r <= degree {
+						basis[j] = values[r]
+						if m.Extrapolation == "linear" {
+							basis[j] += (x - boundary) * derivatives[r]
+						}
+					}
+				}
+			}
+			copy(Xout.RawRowView(i)[feature*nOut:(feature+1)*nOut], basis)
+		}
+	}
+	return Xout, base.ToDense(Y)
+}
+
+// FitTransform fit to data, then transform it
+func (m *SplineTransformer) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	m.Fit(X, Y)
+	return m.Transform(X, Y)
+}
+
+// GetFeatureNamesOut returns the input feature name (default x0, x1...) followed by _sp_ and the spline index
+func (m *SplineTransformer) GetFeatureNamesOut(inputNames []string) []string {
+	nOut := m.nSplinesOut()
+	names := make([]string, 0, m.NFeaturesOut)
+	for _, name := range base.FeatureNamesIn(inputNames, m.NFeaturesIn) {
+		for j := 0; j < nOut; j++ {
+			names = append(names, fmt.Sprintf("%s_sp_%d", name, j))
+		}
+	}
+	return names
+}
+
+// bsplineInterval returns the index i of the knot interval [t[i],t[i+1]) containing x, clamped to
+// [degree,len(t)-degree-2] so that values outside the knots extend the boundary polynomial pieces
+func bsplineInterval(t []float64, degree int, x float64) int {
+	i := sort.Search(len(t), func(i int) bool { return t[i] > x }) - 1
+	if i < degree {
+		i = degree
+	}
+	if last := len(t) - degree - 2; i > last {
+		i = last
+	}
+	return i
+}
+
+// bsplineBasis stores into values the degree+1 B-splines not null at x (Cox-de Boor recursion) and returns the index
+// of the first one
+func bsplineBasis(t []float64, degree int, x float64, values []float64) int {
+	i := bsplineInterval(t, degree, x)
+	bsplineBasisAt(t, degree, i, x, values)
+	return i - degree
+}
+
+// bsplineDerivatives stores into derivatives the first derivatives at x of the degree+1 B-splines returned by
+// bsplineBasis
+func bsplineDerivatives(t []float64, degree int, x float64, derivatives []float64) {
+	for r := range derivatives[:degree+1] {
+		derivatives[r] = 0
+	}
+	if degree == 0 {
+		return
+	}
+	lower := make([]float64, degree)
+	i := bsplineInterval(t, degree, x)
+	// splines of degree-1 not null on interval i are i-degree+1..i
+	bsplineBasisAt(t, degree-1, i, x, lower)
+	k := float64(degree)
+	for r := 0; r <= degree; r++ {
+		j := i - degree + r
+		// B'(j,degree) = degree * (B(j,degree-1)/(t[j+degree]-t[j]) - B(j+1,degree-1)/(t[j+degree+1]-t[j+1]))
+		if r-1 >= 0 {
+			if denom := t[j+degree] - t[j]; denom > 0 {
+				derivatives[r] += k * lower[r-1] / denom
+			}
+		}
+		if r < degree {
+			if denom := t[j+degree+1] - t[j+1]; denom > 0 {
+				derivatives[r] -= k * lower[r] / denom
+			}
+		}
+	}
+}
+
+// bsplineBasisAt is bsplineBasis for a given knot interval i
+func bsplineBasisAt(t []float64, degree, i int, x float64, values []float64) {
+	values[0] = 1
+	for j := 1; j <= degree; j++ {
+		saved := 0.
+		for r := 0; r < j; r++ {
+			right, left := t[i+r+1]-x, x-t[i+1-j+r]
+			temp := 0.
+			if denom := t[i+r+1] - t[i+1-j+r]; denom > 0 {
+				temp = values[r] / denom
+			}
+			values[r] = saved + right*temp
+			saved = left * temp
+		}
+		values[j] = saved
+	}
+}
+
+// percentile returns the q quantile of sorted values, linearly interpolated like numpy's percentile
+func percentile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(math.Floor(pos))
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
@@ -0,0 +1,70 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleSplineTransformer() {
+	// adapted from https://scikit-learn.org/stable/modules/preprocessing.html#spline-transformer
+	X := mat.NewDense(5, 1, []float64{0, 1, 2, 3, 4})
+	spline := NewSplineTransformer()
+	spline.Degree = 2
+	spline.NKnots = 3
+	Xt, _ := spline.FitTransform(X, nil)
+	fmt.Println(spline.GetFeatureNamesOut(nil))
+	fmt.Printf("%g\n", mat.Formatted(Xt))
+	for _, extrapolation := range []string{"constant", "linear", "continue"} {
+		spline.Extrapolation = extrapolation
+		spline.Fit(X, nil)
+		Xt, _ = spline.Transform(mat.NewDense(2, 1, []float64{-1, 5}), nil)
+		fmt.Printf("%s\n%g\n", extrapolation, mat.Formatted(Xt))
+	}
+	// Output:
+	// [x0_sp_0 x0_sp_1 x0_sp_2 x0_sp_3]
+	// ⎡  0.5    0.5      0      0⎤
+	// ⎢0.125   0.75  0.125      0⎥
+	// ⎢    0    0.5    0.5      0⎥
+	// ⎢    0  0.125   0.75  0.125⎥
+	// ⎣    0      0    0.5    0.5⎦
+	// constant
+	// ⎡0.5  0.5    0    0⎤
+	// ⎣  0    0  0.5  0.5⎦
+	// linear
+	// ⎡1  0  0  0⎤
+	// ⎣0  0  0  1⎦
+	// continue
+	// ⎡1.125  -0.25  0.125      0⎤
+	// ⎣    0  0.125  -0.25  1.125⎦
+}
+
+func ExampleSplineTransformer_periodic() {
+	// hour of day is a cyclic feature: periodic splines have the same values at 0 and 24
+	X := mat.NewDense(25, 1, nil)
+	y := make([]float64, 25)
+	for h := range y {
+		X.Set(h, 0, float64(h))
+		y[h] = math.Sin(2 * math.Pi * float64(h) / 24)
+	}
+	spline := NewSplineTransformer()
+	spline.NKnots = 7
+	spline.Extrapolation = "periodic"
+	Xt, _ := spline.FitTransform(X, nil)
+	_, nSplines := Xt.Dims()
+	fmt.Println(nSplines, mat.Equal(Xt.RowView(0), Xt.RowView(24)), floats.Sum(Xt.RawRowView(7)))
+	// least squares fit of sin(2*pi*h/24) on the splines, evaluated at 30h = 6h
+	coef := mat.NewVecDense(nSplines, nil)
+	if err := coef.SolveVec(Xt, mat.NewVecDense(25, y)); err != nil {
+		panic(err)
+	}
+	Xnew, _ := spline.Transform(mat.NewDense(2, 1, []float64{6, 30}), nil)
+	pred := mat.NewVecDense(2, nil)
+	pred.MulVec(Xnew, coef)
+	fmt.Printf("%.3f %.3f\n", pred.AtVec(0), pred.AtVec(1))
+	// Output:
+	// 6 true 0.9999999999999999
+	// 0.998 0.998
+}
@@ -0,0 +1,59 @@
+package preprocessing
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/table"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// tableEncoding lets encoders accept a *table.Table: string categories are replaced by their index in the
+// StringCategories learnt at fit (-1 for unknown strings), so that codes are consistent between fit and transform.
+// FeatureNamesIn are the column names of the table seen at fit, used as default input feature names.
+// both are nil if the encoder was fitted on another mat.Matrix
+type tableEncoding struct {
+	FeatureNamesIn   []string
+	StringCategories [][]string
+}
+
+// fitTable learns the categories of X if it is a *table.Table and returns X encoded
+func (e *tableEncoding) fitTable(X mat.Matrix) mat.Matrix {
+	t, ok := X.(*table.Table)
+	if !ok {
+		e.FeatureNamesIn, e.StringCategories = nil, nil
+		return X
+	}
+	e.FeatureNamesIn, e.StringCategories = t.Names(), t.Dictionaries()
+	return t.Encode(e.StringCategories)
+}
+
+// transformTable returns X encoded with the learnt categories if it is a *table.Table
+func (e *tableEncoding) transformTable(X mat.Matrix) mat.Matrix {
+	t, ok := X.(*table.Table)
+	if !ok {
+		return X
+	}
+	if e.StringCategories == nil {
+		e.StringCategories = make([][]string, len(t.Columns))
+	}
+	return t.Encode(e.StringCategories)
+}
+
+// inputNames returns inputNames, or FeatureNamesIn if inputNames is nil
+func (e *tableEncoding) inputNames(inputNames []string) []string {
+	if inputNames == nil {
+		return e.FeatureNamesIn
+	}
+	return inputNames
+}
+
+// categoryName returns the string category of code v of feature if it is a string category, else v formatted with %g
+func (e *tableEncoding) categoryName(feature int, v float64) string {
+	if e.StringCategories != nil && e.StringCategories[feature] != nil {
+		if c := int(v); float64(c) == v && c >= 0 && c < len(e.StringCategories[feature]) {
+			return e.StringCategories[feature][c]
+		}
+	}
+	return fmt.Sprintf("%g", v)
+}
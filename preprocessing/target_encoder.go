@@ -0,0 +1,336 @@
+package preprocessing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+// TargetEncoder encodes each category of a feature by a shrunk mean of the target over the samples of this category.
+// TargetType is "auto" (default), "continuous", "binary" or "multiclass". "auto" infers binary for 2 distinct
+// targets, multiclass for more than 2 distinct integer targets and continuous otherwise. binary targets are encoded as
+// the probability of the greater class. multiclass targets are encoded one-vs-rest, giving one output column per class
+// for each feature.
+// Smooth is "auto" (default, empirical Bayes estimate of the shrinkage) or a float64 >= 0: the weight of the target
+// mean in the encodings, larger values meaning more regularization.
+// FitTransform uses cross fitting to avoid target leakage: samples are partitioned in CV folds (default 5) like
+// scikit-learn's KFold, shuffled using RandomState if Shuffle is true, and each fold is encoded with the encodings
+// learnt on the other folds. Transform uses the encodings learnt on the whole training data.
+// unknown categories are encoded as the target mean.
+type TargetEncoder struct {
+	Categories  [][]float64
+	TargetType  string
+	Smooth      interface{}
+	CV          int
+	Shuffle     bool
+	RandomState base.RandomState
+
+	Values [][]float64
+	// TypeOfTarget is the inferred or given target type, Classes the sorted classes of binary and multiclass targets
+	TypeOfTarget string
+	Classes      []float64
+	// TargetMean[k] is the mean of target column k (one column per class for multiclass targets)
+	TargetMean []float64
+	// Encodings[feature*len(TargetMean)+k][c] is the encoding of category c of feature for target column k
+	Encodings     [][]float64
+	categoryIndex []categoryIndex
+	tableEncoding
+}
+
+// NewTargetEncoder returns a *TargetEncoder with automatic target type and smoothing and 5 shuffled folds
+func NewTargetEncoder() *TargetEncoder {
+	return &TargetEncoder{TargetType: "auto", Smooth: "auto", CV: 5, Shuffle: true}
+}
+
+// TransformerClone ...
+func (m *TargetEncoder) TransformerClone() base.Transformer {
+	var clone = *m
+	if sourceCloner, ok := m.RandomState.(base.SourceCloner); ok && sourceCloner != base.SourceCloner(nil) {
+		clone.RandomState = sourceCloner.SourceClone()
+	}
+	return &clone
+}
+
+// Fit learns the encodings of the categories of X on the whole data
+func (m *TargetEncoder) Fit(X, Y mat.Matrix) base.Fiter {
+	m.fit(X, Y)
+	return m
+}
+
+// FitTransform learns the encodings of X on the whole data, and returns the cross fitted encodings of X
+func (m *TargetEncoder) FitTransform(X, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	codes, T := m.fit(X, Y)
+	nSamples, _ := X.Dims()
+	Xout = mat.NewDense(nSamples, len(m.Encodings), nil)
+	for _, test := range m.folds(nSamples) {
+		inTest := make([]bool, nSamples)
+		for _, sample := range test {
+			inTest[sample] = true
+		}
+		train := make([]int, 0, nSamples-len(test))
+		for sample := range inTest {
+			if !inTest[sample] {
+				train = append(train, sample)
+			}
+		}
+		mean, encodings := m.encodings(codes, T, train)
+		targetEncode(codes, mean, encodings, test, Xout)
+	}
+	return Xout, base.ToDense(Y)
+}
+
+// Transform encodes X using the encodings learnt by Fit
+func (m *TargetEncoder) Transform(Xmatrix, Y mat.Matrix) (Xout, Yout *mat.Dense) {
+	X := m.transformTable(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	if nFeatures != len(m.Values) {
+		panic(fmt.Errorf("TargetEncoder: X has %d features, expected %d", nFeatures, len(m.Values)))
+	}
+	codes := m.categoryCodes(X, false)
+	Xout = mat.NewDense(nSamples, len(m.Encodings), nil)
+	targetEncode(codes, m.TargetMean, m.Encodings, sequence(nSamples), Xout)
+	return Xout, base.ToDense(Y)
+}
+
+// GetFeatureNamesOut returns inputNames (default x0, x1...), suffixed with an underscore and the class for
+// multiclass targets
+func (m *TargetEncoder) GetFeatureNamesOut(inputNames []string) []string {
+	var names []string
+	for _, name := range base.FeatureNamesIn(m.inputNames(inputNames), len(m.Values)) {
+		if m.TypeOfTarget != "multiclass" {
+			names = append(names, name)
+			continue
+		}
+		for _, class := range m.Classes {
+			names = append(names, fmt.Sprintf("%s_%g", name, class))
+		}
+	}
+	return names
+}
+
+// fit learns the encodings on all samples and returns the category codes of X and the target columns
+func (m *TargetEncoder) fit(X, Y mat.Matrix) (codes [][]int, T *mat.Dense) {
+	codes, T = m.prepare(m.fitTable(X), Y)
+	nSamples, _ := X.Dims()
+	m.TargetMean, m.Encodings = m.encodings(codes, T, sequence(nSamples))
+	return
+}
+
+// prepare determines categories and target type, and returns the category codes of X and the target columns
+func (m *TargetEncoder) prepare(Xmatrix, Ymatrix mat.Matrix) (codes [][]int, T *mat.Dense) {
+	X := base.ToDense(Xmatrix)
+	nSamples, nFeatures := X.Dims()
+	if Ymatrix == nil {
+		panic(fmt.Errorf("TargetEncoder: Y is required"))
+	}
+	if nY, nOutputs := Ymatrix.Dims(); nY != nSamples || nOutputs != 1 {
+		panic(fmt.Errorf("TargetEncoder: Y must be a (%d,1) matrix, got (%d,%d)", nSamples, nY, nOutputs))
+	}
+	if m.Categories != nil && len(m.Categories) != nFeatures {
+		panic(fmt.Errorf("TargetEncoder: Categories has %d features, X has %d", len(m.Categories), nFeatures))
+	}
+	m.Values, m.categoryIndex = make([][]float64, nFeatures), make([]categoryIndex, nFeatures)
+	col := make([]float64, nSamples)
+	for feature := 0; feature < nFeatures; feature++ {
+		if m.Categories == nil {
+			m.Values[feature] = uniqueSorted(mat.Col(col, feature, X))
+		} else {
+			m.Values[feature] = append([]float64(nil), m.Categories[feature]...)
+		}
+		m.categoryIndex[feature] = newCategoryIndex(m.Values[feature])
+	}
+	codes = m.categoryCodes(X, true)
+
+	y := mat.Col(nil, 0, Ymatrix)
+	m.Classes = uniqueSorted(y)
+	m.TypeOfTarget = m.TargetType
+	if m.TypeOfTarget == "" || m.TypeOfTarget == "auto" {
+		m.TypeOfTarget = "continuous"
+		if len(m.Classes) == 2 {
+			m.TypeOfTarget = "binary"
+		} else if len(m.Classes) > 2 && isIntegral(m.Classes) {
+			m.TypeOfTarget = "multiclass"
+		}
+	}
+	switch m.TypeOfTarget {
+	case "continuous":
+		m.Classes = nil
+		T = mat.NewDense(nSamples, 1, y)
+	case "binary":
+		if len(m.Classes) != 2 {
+			panic(fmt.Errorf("TargetEncoder: binary target has %d classes", len(m.Classes)))
+		}
+		T = mat.NewDense(nSamples, 1, nil)
+		for i, v := range y {
+			if v == m.Classes[1] {
+				T.Set(i, 0, 1)
+			}
+		}
+	case "multiclass":
+		T = mat.NewDense(nSamples, len(m.Classes), nil)
+		classIndex := newCategoryIndex(m.Classes)
+		for i, v := range y {
+			T.Set(i, classIndex.get(v), 1)
+		}
+	default:
+		panic(fmt.Errorf("TargetEncoder: unknown TargetType %s", m.TargetType))
+	}
+	return
+}
+
+// categoryCodes returns the category index of each value of X, -1 for unknown categories
+func (m *TargetEncoder) categoryCodes(X mat.Matrix, fitting bool) [][]int {
+	nSamples, nFeatures := X.Dims()
+	codes := make([][]int, nSamples)
+	for sample := range codes {
+		codes[sample] = make([]int, nFeatures)
+		for feature := range codes[sample] {
+			v := X.At(sample, feature)
+			c := m.categoryIndex[feature].get(v)
+			if c < 0 && fitting {
+				panic(fmt.Errorf("TargetEncoder: found unknown category %g in column %d during fit", v, feature))
+			}
+			codes[sample][feature] = c
+		}
+	}
+	return codes
+}
+
+// encodings returns the target mean and the smoothed category encodings learnt on samples
+func (m *TargetEncoder) encodings(codes [][]int, T *mat.Dense, samples []int) (mean []float64, encodings [][]float64) {
+	autoSmooth, smooth := false, 0.
+	switch s := m.Smooth.(type) {
+	case nil:
+		autoSmooth = true
+	case string:
+		if s != "auto" {
+			panic(fmt.Errorf("TargetEncoder: unknown Smooth %s", s))
+		}
+		autoSmooth = true
+	case float64:
+		if s < 0 {
+			panic(fmt.Errorf("TargetEncoder: Smooth must be >= 0, got %g", s))
+		}
+		smooth = s
+	default:
+		panic(fmt.Errorf("TargetEncoder: Smooth must be \"auto\" or a float64, got %T", m.Smooth))
+	}
+	_, nTargets := T.Dims()
+	n := float64(len(samples))
+	mean, variance := make([]float64, nTargets), make([]float64, nTargets)
+	for k := range mean {
+		for _, sample := range samples {
+			mean[k] += T.At(sample, k)
+		}
+		mean[k] /= n
+		for _, sample := range samples {
+			d := T.At(sample, k) - mean[k]
+			variance[k] += d * d
+		}
+		variance[k] /= n
+	}
+	encodings = make([][]float64, len(m.Values)*nTargets)
+	for feature, values := range m.Values {
+		counts := make([]float64, len(values))
+		for _, sample := range samples {
+			counts[codes[sample][feature]]++
+		}
+		for k := 0; k < nTargets; k++ {
+			sums, sumSquares := make([]float64, len(values)), make([]float64, len(values))
+			for _, sample := range samples {
+				t := T.At(sample, k)
+				c := codes[sample][feature]
+				sums[c] += t
+				sumSquares[c] += t * t
+			}
+			encoding := make([]float64, len(values))
+			for c, count := range counts {
+				encoding[c] = mean[k]
+				switch {
+				case count == 0:
+				case autoSmooth:
+					categoryMean := sums[c] / count
+					categoryVariance := math.Max(0, sumSquares[c]/count-categoryMean*categoryMean)
+					if denom := variance[k]*count + categoryVariance; denom > 0 {
+						lambda := variance[k] * count / denom
+						encoding[c] = lambda*categoryMean + (1-lambda)*mean[k]
+					}
+				case count+smooth > 0:
+					encoding[c] = (sums[c] + smooth*mean[k]) / (count + smooth)
+				}
+			}
+			encodings[feature*nTargets+k] = encoding
+		}
+	}
+	return
+}
+
+// folds partitions sample indices in m.CV folds, the first nSamples%CV ones having one more sample
+func (m *TargetEncoder) folds(nSamples int) [][]int {
+	nFolds := m.CV
+	if nFolds <= 0 {
+		nFolds = 5
+	}
+	if nFolds < 2 || nFolds > nSamples {
+		panic(fmt.Errorf("TargetEncoder: CV must be in [2,%d], got %d", nSamples, nFolds))
+	}
+	perm := sequence(nSamples)
+	if m.Shuffle {
+		Perm := rand.Perm
+		if m.RandomState != base.Source(nil) {
+			Perm = rand.New(m.RandomState).Perm
+		}
+		perm = Perm(nSamples)
+	}
+	folds := make([][]int, nFolds)
+	start := 0
+	for fold := range folds {
+		size := nSamples / nFolds
+		if fold < nSamples%nFolds {
+			size++
+		}
+		folds[fold] = perm[start : start+size]
+		start += size
+	}
+	return folds
+}
+
+// targetEncode writes the encodings of samples into Xout. unknown categories are encoded as the target mean
+func targetEncode(codes [][]int, mean []float64, encodings [][]float64, samples []int, Xout *mat.Dense) {
+	nTargets := len(mean)
+	for _, sample := range samples {
+		row := Xout.RawRowView(sample)
+		for feature, c := range codes[sample] {
+			for k := 0; k < nTargets; k++ {
+				if c < 0 {
+					row[feature*nTargets+k] = mean[k]
+				} else {
+					row[feature*nTargets+k] = encodings[feature*nTargets+k][c]
+				}
+			}
+		}
+	}
+}
+
+// isIntegral returns true if all values are integers
+func isIntegral(values []float64) bool {
+	for _, v := range values {
+		if v != math.Trunc(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// sequence returns 0,1...n-1
+func sequence(n int) []int {
+	a := make([]int, n)
+	for i := range a {
+		a[i] = i
+	}
+	return a
+}
@@ -0,0 +1,53 @@
+package preprocessing
+
+import (
+	"fmt"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleTargetEncoder() {
+	// feature 0 is a category, Y is about 10 times the category
+	X := mat.NewDense(10, 1, []float64{1, 1, 1, 2, 2, 2, 3, 3, 3, 3})
+	Y := mat.NewDense(10, 1, []float64{9, 10, 11, 19, 20, 21, 28, 30, 32, 30})
+	enc := NewTargetEncoder()
+	enc.TargetType = "continuous"
+	enc.Smooth = 1.
+	enc.RandomState = base.NewSource(7)
+	Xt, _ := enc.FitTransform(X, Y)
+	fmt.Println(enc.TypeOfTarget, enc.TargetMean)
+	fmt.Printf("%.3f\n", enc.Encodings)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt.T()))
+	Xt, _ = enc.Transform(mat.NewDense(2, 1, []float64{2, 4}), nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt.T()))
+	// Output:
+	// continuous [21]
+	// [[12.750 20.250 28.200]]
+	// [16.875  13.667  16.875  20.375  20.062  20.062  27.000  27.531  27.250  27.000]
+	// [20.250  21.000]
+}
+
+func ExampleTargetEncoder_multiclass() {
+	X := mat.NewDense(9, 2, []float64{
+		0, 5,
+		0, 5,
+		0, 6,
+		1, 6,
+		1, 5,
+		1, 6,
+		2, 5,
+		2, 6,
+		2, 6,
+	})
+	Y := mat.NewDense(9, 1, []float64{0, 0, 0, 1, 1, 2, 2, 2, 2})
+	enc := NewTargetEncoder()
+	enc.Fit(X, Y)
+	fmt.Println(enc.TypeOfTarget, enc.Classes, enc.GetFeatureNamesOut([]string{"a", "b"}))
+	Xt, _ := enc.Transform(mat.NewDense(1, 2, []float64{0, 5}), nil)
+	fmt.Printf("%.3f\n", mat.Formatted(Xt))
+	// Output:
+	// multiclass [0 1 2] [a_0 a_1 a_2 b_0 b_1 b_2]
+	// [1.000  0.000  0.000  0.463  0.244  0.281]
+}
@@ -24,15 +24,15 @@ func (LinearKernel) Func(a, b []float64) (sumprod float64) {
 }
 
 // PolynomialKernel ...
-type PolynomialKernel struct{ gamma, coef0, degree float64 }
+type PolynomialKernel struct{ Gamma, Coef0, Degree float64 }
 
 // Func for PolynomialKernel
 func (kdata PolynomialKernel) Func(a, b []float64) (sumprod float64) {
-	return math.Pow(kdata.gamma*floats.Dot(a, b)+kdata.coef0, kdata.degree)
+	return math.Pow(kdata.Gamma*floats.Dot(a, b)+kdata.Coef0, kdata.Degree)
 }
 
 // RBFKernel ...
-type RBFKernel struct{ gamma float64 }
+type RBFKernel struct{ Gamma float64 }
 
 // Func for RBFKernel
 func (kdata RBFKernel) Func(a, b []float64) float64 {
@@ -41,13 +41,25 @@ func (kdata RBFKernel) Func(a, b []float64) float64 {
 		v := a[i] - b[i]
 		L2 += v * v
 	}
-	return math.Exp(-kdata.gamma * L2)
+	return math.Exp(-kdata.Gamma * L2)
 }
 
 // SigmoidKernel ...
-type SigmoidKernel struct{ gamma, coef0 float64 }
+type SigmoidKernel struct{ Gamma, Coef0 float64 }
 
 // Func for SigmoidKernel
 func (kdata SigmoidKernel) Func(a, b []float64) (sumprod float64) {
-	return math.Tanh(kdata.gamma*floats.Dot(a, b) + kdata.coef0)
+	return math.Tanh(kdata.Gamma*floats.Dot(a, b) + kdata.Coef0)
+}
+
+// CosineKernel is the dot product of L2-normalized vectors
+type CosineKernel struct{}
+
+// Func for CosineKernel
+func (CosineKernel) Func(a, b []float64) float64 {
+	na, nb := floats.Norm(a, 2), floats.Norm(b, 2)
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return floats.Dot(a, b) / (na * nb)
 }
@@ -224,7 +224,7 @@ type SVC struct {
 }
 
 // NewSVC ...
-// Kernel: "linear","poly","rbf","sigmoid" default is "rbf"
+// Kernel: "linear","poly","rbf","sigmoid","cosine" default is "rbf"
 // if Gamma<=0 il will be changed to 1/NFeatures
 // Cachesize is in MB. defaults to 200
 func NewSVC() *SVC {
@@ -276,11 +276,13 @@ func (m *BaseLibSVM) fit(X, Y *mat.Dense, svmTrain func(X *mat.Dense, Y []float6
 		case "linear":
 			K = (LinearKernel{}).Func
 		case "poly", "polynomial":
-			K = (PolynomialKernel{gamma: m.Gamma, coef0: m.Coef0, degree: m.Degree}).Func
+			K = (PolynomialKernel{Gamma: m.Gamma, Coef0: m.Coef0, Degree: m.Degree}).Func
+		case "cosine":
+			K = (CosineKernel{}).Func
 		case "sigmoid":
-			K = (SigmoidKernel{gamma: m.Gamma, coef0: m.Coef0}).Func
+			K = (SigmoidKernel{Gamma: m.Gamma, Coef0: m.Coef0}).Func
 		default: //rbf
-			K = (RBFKernel{gamma: m.Gamma}).Func
+			K = (RBFKernel{Gamma: m.Gamma}).Func
 		}
 	case Kernel:
 		K = v.Func
@@ -307,6 +309,32 @@ func (m *BaseLibSVM) fit(X, Y *mat.Dense, svmTrain func(X *mat.Dense, Y []float6
 	})
 }
 
+// linearCoef returns the weights of the features for each output, as a (NFeatures,NOutputs) matrix: the sum of the
+// support vectors weighted by their alphas, and by their labels if labelled. Kernel must be "linear"
+func (m *BaseLibSVM) linearCoef(labelled bool) *mat.Dense {
+	switch m.Kernel {
+	case "linear", LinearKernel{}:
+	default:
+		panic(fmt.Errorf("Coef is only available with linear kernel"))
+	}
+	_, nFeatures := m.Model[0].X.Dims()
+	coef := mat.NewDense(nFeatures, len(m.Model), nil)
+	for output, model := range m.Model {
+		for i, alpha := range model.Alphas {
+			if labelled {
+				alpha *= model.Y[i]
+			}
+			for j, x := range model.X.RawRowView(i) {
+				coef.Set(j, output, coef.At(j, output)+alpha*x)
+			}
+		}
+	}
+	return coef
+}
+
+// Coef returns the weights of the features for each output, as a (NFeatures,NOutputs) matrix. Kernel must be "linear"
+func (m *SVC) Coef() *mat.Dense { return m.linearCoef(true) }
+
 // Predict for SVC
 func (m *SVC) Predict(Xmatrix mat.Matrix, Ymutable mat.Mutable) *mat.Dense {
 	X, Y := base.ToDense(Xmatrix), base.ToDense(Ymutable)
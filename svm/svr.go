@@ -190,6 +190,9 @@ func svrTrain(X *mat.Dense, Y []float64, C, Epsilon float64, KernelFunction func
 	return model
 }
 
+// Coef returns the weights of the features for each output, as a (NFeatures,NOutputs) matrix. Kernel must be "linear"
+func (m *SVR) Coef() *mat.Dense { return m.linearCoef(false) }
+
 // Fit for SVR
 func (m *SVR) Fit(Xmatrix, Ymatrix mat.Matrix) base.Fiter {
 	_, m.nOutputs = Ymatrix.Dims()
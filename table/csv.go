@@ -0,0 +1,138 @@
+package table
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions are the options of ReadCSV.
+// Comma is the field delimiter (default ','). fields equal to one of NullValues (default "", "NA", "NaN", "null") are
+// missing. Kinds forces the kind of named columns, other columns being Int if all their non null values are integers,
+// Float if they are numbers and Categorical otherwise
+type CSVOptions struct {
+	Comma      rune
+	NullValues []string
+	Kinds      map[string]Kind
+}
+
+// DefaultNullValues are the fields considered missing by ReadCSV
+var DefaultNullValues = []string{"", "NA", "NaN", "null"}
+
+// ReadCSV reads a table from CSV data having a header line. options may be nil
+func ReadCSV(r io.Reader, options *CSVOptions) (*Table, error) {
+	if options == nil {
+		options = &CSVOptions{}
+	}
+	reader := csv.NewReader(r)
+	if options.Comma != 0 {
+		reader.Comma = options.Comma
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("table: missing CSV header")
+	}
+	nullValues := options.NullValues
+	if nullValues == nil {
+		nullValues = DefaultNullValues
+	}
+	isNull := make(map[string]bool)
+	for _, s := range nullValues {
+		isNull[s] = true
+	}
+	header, rows := records[0], records[1:]
+	columns := make([]*Column, len(header))
+	for j, name := range header {
+		fields := make([]string, len(rows))
+		null := make([]bool, len(rows))
+		hasNull := false
+		for i, row := range rows {
+			fields[i] = strings.TrimSpace(row[j])
+			null[i] = isNull[fields[i]]
+			hasNull = hasNull || null[i]
+		}
+		if !hasNull {
+			null = nil
+		}
+		kind, forced := options.Kinds[name]
+		if !forced {
+			kind = inferKind(fields, null)
+		}
+		if columns[j], err = parseColumn(name, kind, fields, null); err != nil {
+			return nil, err
+		}
+	}
+	return New(columns...), nil
+}
+
+// ReadCSVFile reads a table from a CSV file having a header line. options may be nil
+func ReadCSVFile(path string, options *CSVOptions) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSV(f, options)
+}
+
+// inferKind returns Int if all non null fields are integers, Float if they are numbers, else Categorical
+func inferKind(fields []string, null []bool) Kind {
+	kind := Int
+	for i, field := range fields {
+		if null != nil && null[i] {
+			continue
+		}
+		if kind == Int {
+			if _, err := strconv.Atoi(field); err == nil {
+				continue
+			}
+			kind = Float
+		}
+		if _, err := strconv.ParseFloat(field, 64); err != nil {
+			return Categorical
+		}
+	}
+	return kind
+}
+
+func parseColumn(name string, kind Kind, fields []string, null []bool) (*Column, error) {
+	switch kind {
+	case Int:
+		values := make([]int, len(fields))
+		for i, field := range fields {
+			if null != nil && null[i] {
+				continue
+			}
+			v, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("table: column %s row %d: %v", name, i, err)
+			}
+			values[i] = v
+		}
+		return NewIntColumn(name, values, null), nil
+	case Float:
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			if null != nil && null[i] {
+				values[i] = math.NaN()
+				continue
+			}
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("table: column %s row %d: %v", name, i, err)
+			}
+			values[i] = v
+		}
+		return NewFloatColumn(name, values), nil
+	case Categorical:
+		return NewCategoricalColumn(name, fields, null), nil
+	}
+	return nil, fmt.Errorf("table: unknown kind %v for column %s", kind, name)
+}
@@ -0,0 +1,36 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleReadCSV() {
+	data := `city,rooms,surface,price
+Paris,3,65.5,520
+London,,80,
+Paris,2,NA,410
+Berlin,4,95,380
+`
+	t, err := ReadCSV(strings.NewReader(data), &CSVOptions{Kinds: map[string]Kind{"price": Float}})
+	if err != nil {
+		panic(err)
+	}
+	for _, c := range t.Columns {
+		fmt.Println(c.Name, c.Kind, c.Null)
+	}
+	fmt.Println(t.Column("city").Categories())
+	fmt.Printf("%g\n", mat.Formatted(t))
+	// Output:
+	// city categorical []
+	// rooms int [false true false false]
+	// surface float [false false true false]
+	// price float [false true false false]
+	// [Berlin London Paris]
+	// ⎡   2     3  65.5   520⎤
+	// ⎢   1   NaN    80   NaN⎥
+	// ⎢   2     2   NaN   410⎥
+	// ⎣   0     4    95   380⎦
+}
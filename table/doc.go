@@ -0,0 +1,6 @@
+// Package table is a lightweight container for heterogeneous tabular data: named Float, Int and Categorical (string)
+// columns with missing values, read from CSV.
+// a *Table is a mat.Matrix, so it can be given to any transformer or predicter. preprocessing encoders and
+// compose.ColumnTransformer handle the string categories of tables, and datasets.NewMLDatasetFromTable builds an
+// MLDataset from a table.
+package table
@@ -0,0 +1,329 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/RobinRCM/sklearn/base"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Kind is the type of the values of a Column
+type Kind int
+
+// column kinds
+const (
+	Float Kind = iota
+	Int
+	Categorical
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Float:
+		return "float"
+	case Int:
+		return "int"
+	case Categorical:
+		return "categorical"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Column is a named column of values of a single Kind: Floats, Ints or Strings.
+// Null marks missing values (nil if there is none). NaN float values are missing too.
+// values must not be modified once Categories has been called
+type Column struct {
+	Name    string
+	Kind    Kind
+	Floats  []float64
+	Ints    []int
+	Strings []string
+	Null    []bool
+
+	// categories are the sorted distinct non null Strings, computed once
+	once       sync.Once
+	categories []string
+	codes      map[string]int
+}
+
+// NewFloatColumn returns a Float column. NaN values are missing
+func NewFloatColumn(name string, values []float64) *Column {
+	c := &Column{Name: name, Kind: Float, Floats: values}
+	for i, v := range values {
+		if math.IsNaN(v) {
+			c.setNull(i)
+		}
+	}
+	return c
+}
+
+// NewIntColumn returns an Int column. null may be nil if no value is missing
+func NewIntColumn(name string, values []int, null []bool) *Column {
+	return &Column{Name: name, Kind: Int, Ints: values, Null: checkNull(null, len(values))}
+}
+
+// NewCategoricalColumn returns a Categorical column. null may be nil if no value is missing
+func NewCategoricalColumn(name string, values []string, null []bool) *Column {
+	return &Column{Name: name, Kind: Categorical, Strings: values, Null: checkNull(null, len(values))}
+}
+
+func checkNull(null []bool, n int) []bool {
+	if null != nil && len(null) != n {
+		panic(fmt.Errorf("table: null mask has %d elements, expected %d", len(null), n))
+	}
+	return null
+}
+
+func (c *Column) setNull(i int) {
+	if c.Null == nil {
+		c.Null = make([]bool, c.Len())
+	}
+	c.Null[i] = true
+}
+
+// Len returns the number of values of the column
+func (c *Column) Len() int {
+	switch c.Kind {
+	case Int:
+		return len(c.Ints)
+	case Categorical:
+		return len(c.Strings)
+	}
+	return len(c.Floats)
+}
+
+// IsNull returns true if value i is missing
+func (c *Column) IsNull(i int) bool {
+	return (c.Null != nil && c.Null[i]) || (c.Kind == Float && math.IsNaN(c.Floats[i]))
+}
+
+// Categories returns the sorted distinct non null values of a Categorical column
+func (c *Column) Categories() []string {
+	if c.Kind != Categorical {
+		panic(fmt.Errorf("table: column %s is not categorical", c.Name))
+	}
+	c.once.Do(func() {
+		c.codes = make(map[string]int)
+		for i, s := range c.Strings {
+			if !c.IsNull(i) {
+				c.codes[s] = 0
+			}
+		}
+		c.categories = make([]string, 0, len(c.codes))
+		for s := range c.codes {
+			c.categories = append(c.categories, s)
+		}
+		sort.Strings(c.categories)
+		for code, s := range c.categories {
+			c.codes[s] = code
+		}
+	})
+	return c.categories
+}
+
+// Float returns value i as a float64: the value of Float and Int columns, the index of the value in Categories for
+// Categorical columns. missing values are NaN
+func (c *Column) Float(i int) float64 {
+	if c.IsNull(i) {
+		return math.NaN()
+	}
+	switch c.Kind {
+	case Int:
+		return float64(c.Ints[i])
+	case Categorical:
+		c.Categories()
+		return float64(c.codes[c.Strings[i]])
+	}
+	return c.Floats[i]
+}
+
+// Table is a set of named columns of the same length. it implements mat.Matrix, categorical values being replaced by
+// their index in the categories of their column and missing values by NaN
+type Table struct {
+	Columns []*Column
+}
+
+// New returns a *Table of columns, which must have the same length and distinct names
+func New(columns ...*Column) *Table {
+	names := make(map[string]bool)
+	for _, c := range columns {
+		if names[c.Name] {
+			panic(fmt.Errorf("table: duplicate column %s", c.Name))
+		}
+		names[c.Name] = true
+		if c.Len() != columns[0].Len() {
+			panic(fmt.Errorf("table: column %s has %d rows, expected %d", c.Name, c.Len(), columns[0].Len()))
+		}
+	}
+	return &Table{Columns: columns}
+}
+
+// FromDense returns a table of Float columns from the columns of X. names default to x0, x1...
+func FromDense(X mat.Matrix, names []string) *Table {
+	_, nColumns := X.Dims()
+	names = base.FeatureNamesIn(names, nColumns)
+	columns := make([]*Column, nColumns)
+	for j := range columns {
+		columns[j] = NewFloatColumn(names[j], mat.Col(nil, j, X))
+	}
+	return New(columns...)
+}
+
+// Dims returns the number of rows and columns
+func (t *Table) Dims() (r, c int) {
+	if len(t.Columns) == 0 {
+		return 0, 0
+	}
+	return t.Columns[0].Len(), len(t.Columns)
+}
+
+// At returns the float value of row i of column j (see Column.Float)
+func (t *Table) At(i, j int) float64 {
+	return t.Columns[j].Float(i)
+}
+
+// T returns the transpose of the table
+func (t *Table) T() mat.Matrix { return base.MatTranspose{Matrix: t} }
+
+// Names returns the names of the columns
+func (t *Table) Names() []string {
+	names := make([]string, len(t.Columns))
+	for j, c := range t.Columns {
+		names[j] = c.Name
+	}
+	return names
+}
+
+// Index returns the index of the column named name, or -1
+func (t *Table) Index(name string) int {
+	for j, c := range t.Columns {
+		if c.Name == name {
+			return j
+		}
+	}
+	return -1
+}
+
+// Column returns the column named name. it panics if there is none
+func (t *Table) Column(name string) *Column {
+	j := t.Index(name)
+	if j < 0 {
+		panic(fmt.Errorf("table: unknown column %s", name))
+	}
+	return t.Columns[j]
+}
+
+// Select returns a table with the named columns, which are shared with t
+func (t *Table) Select(names ...string) *Table {
+	columns := make([]*Column, len(names))
+	for j, name := range names {
+		columns[j] = t.Column(name)
+	}
+	return New(columns...)
+}
+
+// SelectIndices returns a table with the columns of given indices, which are shared with t
+func (t *Table) SelectIndices(indices []int) *Table {
+	columns := make([]*Column, len(indices))
+	for j, index := range indices {
+		columns[j] = t.Columns[index]
+	}
+	return &Table{Columns: columns}
+}
+
+// Drop returns a table without the named columns
+func (t *Table) Drop(names ...string) *Table {
+	dropped := make(map[string]bool)
+	for _, name := range names {
+		t.Column(name)
+		dropped[name] = true
+	}
+	var columns []*Column
+	for _, c := range t.Columns {
+		if !dropped[c.Name] {
+			columns = append(columns, c)
+		}
+	}
+	return &Table{Columns: columns}
+}
+
+// KindIndices returns the indices of the columns of given kinds
+func (t *Table) KindIndices(kinds ...Kind) []int {
+	var indices []int
+	for j, c := range t.Columns {
+		for _, kind := range kinds {
+			if c.Kind == kind {
+				indices = append(indices, j)
+				break
+			}
+		}
+	}
+	return indices
+}
+
+// KindSelector returns a function selecting the columns of given kinds of a *Table, usable as the Columns of a
+// compose.NamedTransformer
+func KindSelector(kinds ...Kind) func(X mat.Matrix) []int {
+	return func(X mat.Matrix) []int {
+		t, ok := X.(*Table)
+		if !ok {
+			panic(fmt.Errorf("table: KindSelector needs a *table.Table, got %T", X))
+		}
+		return t.KindIndices(kinds...)
+	}
+}
+
+// Dictionaries returns the categories of each Categorical column, and nil for the other columns
+func (t *Table) Dictionaries() [][]string {
+	dictionaries := make([][]string, len(t.Columns))
+	for j, c := range t.Columns {
+		if c.Kind == Categorical {
+			dictionaries[j] = append([]string(nil), c.Categories()...)
+		}
+	}
+	return dictionaries
+}
+
+// Encode returns a dense copy of the table where the values of Categorical columns are replaced by their index in
+// dictionaries, or -1 if they are not found. missing values are NaN.
+// dictionaries are usually the ones of the table used to fit a model, so that codes are consistent between tables
+func (t *Table) Encode(dictionaries [][]string) *mat.Dense {
+	nRows, nColumns := t.Dims()
+	if len(dictionaries) != nColumns {
+		panic(fmt.Errorf("table: got %d dictionaries for %d columns", len(dictionaries), nColumns))
+	}
+	X := mat.NewDense(nRows, nColumns, nil)
+	for j, c := range t.Columns {
+		if c.Kind != Categorical {
+			if dictionaries[j] != nil {
+				panic(fmt.Errorf("table: column %s is not categorical", c.Name))
+			}
+			for i := 0; i < nRows; i++ {
+				X.Set(i, j, c.Float(i))
+			}
+			continue
+		}
+		if dictionaries[j] == nil {
+			panic(fmt.Errorf("table: no dictionary for categorical column %s", c.Name))
+		}
+		codes := make(map[string]int, len(dictionaries[j]))
+		for code, s := range dictionaries[j] {
+			codes[s] = code
+		}
+		for i, s := range c.Strings {
+			v := math.NaN()
+			if !c.IsNull(i) {
+				v = -1
+				if code, ok := codes[s]; ok {
+					v = float64(code)
+				}
+			}
+			X.Set(i, j, v)
+		}
+	}
+	return X
+}
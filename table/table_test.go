@@ -0,0 +1,32 @@
+package table
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleTable() {
+	t := New(
+		NewCategoricalColumn("color", []string{"red", "green", "", "red"}, []bool{false, false, true, false}),
+		NewIntColumn("size", []int{1, 2, 3, 4}, nil),
+		NewFloatColumn("weight", []float64{.5, math.NaN(), 1.5, 2}),
+	)
+	fmt.Println(t.Dims())
+	fmt.Println(t.Names(), t.KindIndices(Int, Float), t.Column("weight").IsNull(1))
+	fmt.Printf("%g\n", mat.Formatted(t.Select("weight", "color")))
+
+	// categorical values of another table are coded using the categories of t
+	other := New(NewCategoricalColumn("color", []string{"green", "blue"}, nil))
+	fmt.Printf("%g\n", mat.Formatted(other.Encode(t.Select("color").Dictionaries())))
+	// Output:
+	// 4 3
+	// [color size weight] [1 2] true
+	// ⎡0.5    1⎤
+	// ⎢NaN    0⎥
+	// ⎢1.5  NaN⎥
+	// ⎣  2    1⎦
+	// ⎡ 0⎤
+	// ⎣-1⎦
+}